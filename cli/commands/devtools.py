@@ -10,17 +10,6 @@ def git(
     """Utilitaires Git multi-projets"""
     run_script("git-helper.ps1", [action])
 
-@app.command()
-def docker(
-    action: str = typer.Argument("status", help="Action: status, start, stop, clean, logs"),
-    container: str = typer.Option(None, "--container", "-c", help="Nom du conteneur")
-):
-    """Gestion Docker"""
-    args = [action]
-    if container:
-        args.extend(["-Container", container])
-    run_script("docker-helper.ps1", args)
-
 @app.command()
 def ports(
     action: str = typer.Argument("list", help="Action: list, find, kill"),
@@ -3,23 +3,29 @@ package multicmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/badie/bdev/internal/core/config"
 	"github.com/badie/bdev/internal/core/multi"
 	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/internal/core/runner"
 	"github.com/badie/bdev/pkg/ui"
 )
 
 var (
 	filterType  string
 	filterName  []string
+	filterTags  []string
 	concurrency int
+	refresh     bool
+	ordered     bool
 )
 
 // NewCommand creates the multi-project command group
@@ -33,19 +39,26 @@ func NewCommand() *cobra.Command {
 
 	cmd.PersistentFlags().StringVarP(&filterType, "type", "t", "", "Filter by project type (e.g., go, node, py)")
 	cmd.PersistentFlags().StringSliceVarP(&filterName, "name", "n", nil, "Filter by project name (exact)")
+	cmd.PersistentFlags().StringSliceVar(&filterTags, "tag", nil, "Filter by project tag")
 	cmd.PersistentFlags().IntVarP(&concurrency, "concurrency", "c", 4, "Maximum concurrent jobs")
+	cmd.PersistentFlags().BoolVar(&refresh, "refresh", false, "Rescan projects instead of using the project index")
+	cmd.PersistentFlags().BoolVar(&ordered, "ordered", false, "Run projects after the local projects they depend on")
 
 	cmd.AddCommand(execCmd())
 	cmd.AddCommand(listCmd())
 	cmd.AddCommand(gitCmd())
+	cmd.AddCommand(depsCmd())
 
 	return cmd
 }
 
 // getExecutor scans projects and returns a configured executor
 func getExecutor() (*multi.Executor, error) {
-	cfg := config.Get()
-	allProjects, err := projects.Scan(cfg.Paths.Projects)
+	scan := projects.ScanAll
+	if refresh {
+		scan = projects.RefreshAll
+	}
+	allProjects, err := scan()
 	if err != nil {
 		return nil, err
 	}
@@ -58,19 +71,17 @@ func getExecutor() (*multi.Executor, error) {
 	if len(filterName) > 0 {
 		filter.Names = filterName
 	}
+	if len(filterTags) > 0 {
+		filter.Tags = filterTags
+	}
 
-	// Type filter parsing
-	// This is basic, might need better mapping from string to ProjectType
-	// For now, we rely on the user knowing internal types or we improve ProjectType parsing.
-	// Since ProjectType is an int constant, mapping string "go" -> TypeGo is needed.
-	// We'll skip complex type filtering for this MVP step and accept basic filtering logic later if needed.
-	// Or we can do simple string matching on ProjectType.String()
-
+	// The type filter narrows the selection, it is not one more alternative.
+	// Known type IDs and names match exactly, anything else by substring.
 	if filterType != "" {
-		// Filter manually for now as Filter struct expects ProjectType enum
+		want, known := projects.ParseType(filterType)
 		var filtered []projects.Project
 		for _, p := range exec.Projects {
-			if strings.Contains(strings.ToLower(p.Type.String()), strings.ToLower(filterType)) {
+			if known && p.Type == want || !known && strings.Contains(strings.ToLower(p.Type.String()), strings.ToLower(filterType)) {
 				filtered = append(filtered, p)
 			}
 		}
@@ -79,6 +90,14 @@ func getExecutor() (*multi.Executor, error) {
 
 	exec.Filter(filter)
 
+	if ordered {
+		// The graph spans every project, so dependencies through filtered out
+		// projects still order the selected ones
+		if err := exec.Order(projects.BuildGraph(allProjects)); err != nil {
+			return nil, err
+		}
+	}
+
 	return exec, nil
 }
 
@@ -123,9 +142,13 @@ func execCmd() *cobra.Command {
 
 			successCount := 0
 			failCount := 0
+			skipCount := 0
 
 			for res := range results {
-				if res.Error != nil {
+				if res.Skipped {
+					skipCount++
+					fmt.Printf("%s %s %s\n", ui.Warning(ui.ActiveGlyphs.Warning), ui.Bold(res.Project.Name), ui.Muted("skipped: "+res.Output))
+				} else if res.Error != nil {
 					failCount++
 					fmt.Printf("%s %s %s\n", ui.Error(ui.ActiveGlyphs.Cross), ui.Bold(res.Project.Name), ui.Muted(fmt.Sprintf("(%s)", res.Duration.Round(time.Millisecond))))
 					fmt.Println(ui.Error(fmt.Sprintf("  Error: %v", res.Error)))
@@ -136,7 +159,11 @@ func execCmd() *cobra.Command {
 			}
 
 			fmt.Println()
-			fmt.Printf("Summary: %d success, %d failed\n", successCount, failCount)
+			if skipCount > 0 {
+				fmt.Printf("Summary: %d success, %d failed, %d skipped\n", successCount, failCount, skipCount)
+			} else {
+				fmt.Printf("Summary: %d success, %d failed\n", successCount, failCount)
+			}
 
 			if failCount > 0 {
 				return fmt.Errorf("some commands failed")
@@ -203,7 +230,9 @@ func gitCmd() *cobra.Command {
 			failCount := 0
 
 			for res := range results {
-				if res.Error != nil {
+				if res.Skipped {
+					fmt.Printf("%s %s %s\n", ui.Warning(ui.ActiveGlyphs.Warning), res.Project.Name, ui.Muted("skipped: "+res.Output))
+				} else if res.Error != nil {
 					failCount++
 					fmt.Printf("%s %s\n", ui.Error(ui.ActiveGlyphs.Cross), res.Project.Name)
 				} else {
@@ -217,6 +246,91 @@ func gitCmd() *cobra.Command {
 	}
 }
 
+// ============================================================
+// DEPS - Portfolio-wide dependency reports
+// ============================================================
+
+func depsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deps",
+		Short: "Check dependencies across selected projects",
+	}
+
+	cmd.AddCommand(depsRunCmd("outdated", "List outdated dependencies across projects", (*runner.Runner).Outdated))
+	cmd.AddCommand(depsRunCmd("audit", "List vulnerable dependencies across projects", (*runner.Runner).Audit))
+	return cmd
+}
+
+func depsRunCmd(use, short string, run func(*runner.Runner) (*runner.DepsReport, error)) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			execCtx, err := getExecutor()
+			if err != nil {
+				return err
+			}
+
+			if len(execCtx.Projects) == 0 {
+				fmt.Println(ui.Warning("No projects matched the filter"))
+				return nil
+			}
+
+			if !asJSON {
+				fmt.Println(ui.Bold(fmt.Sprintf("Checking dependencies of %d projects...", len(execCtx.Projects))))
+				fmt.Println()
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer cancel()
+
+			var mu sync.Mutex
+			checked := make(map[string]*runner.DepsReport)
+			results := execCtx.ExecuteFunc(ctx, func(ctx context.Context, p projects.Project) (string, error) {
+				r := runner.New(&p)
+				r.Stdout = io.Discard
+				r.Stderr = io.Discard
+				r.Stdin = nil
+
+				report, err := run(r)
+				if err != nil {
+					return "", err
+				}
+				mu.Lock()
+				checked[p.Path] = report
+				mu.Unlock()
+				return "", nil
+			})
+
+			// Failed projects, including the ones skipped by --ordered,
+			// are reported with their error
+			var reports []*runner.DepsReport
+			for res := range results {
+				mu.Lock()
+				report := checked[res.Project.Path]
+				mu.Unlock()
+				switch {
+				case res.Error != nil:
+					report = &runner.DepsReport{Project: res.Project.Name, Error: res.Error.Error()}
+				case res.Skipped:
+					report = &runner.DepsReport{Project: res.Project.Name, Error: "skipped: " + res.Output}
+				}
+				if report != nil {
+					reports = append(reports, report)
+				}
+			}
+
+			sort.Slice(reports, func(i, j int) bool { return reports[i].Project < reports[j].Project })
+			return runner.WriteDepsReports(os.Stdout, reports, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the reports as JSON")
+	return cmd
+}
+
 // ============================================================
 // LIST - Show matched projects
 // ============================================================
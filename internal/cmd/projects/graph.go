@@ -0,0 +1,127 @@
+package projectcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// ============================================================
+// GRAPH
+// ============================================================
+
+func graphCmd() *cobra.Command {
+	var dot bool
+	var mermaid bool
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show local dependencies between projects",
+		Long: `Show which projects depend on each other through replace directives in
+go.mod, file:, link: and workspace: dependencies in package.json, path
+dependencies in Cargo.toml and path repositories in composer.json.
+
+Use --dot for Graphviz (bdev projects graph --dot | dot -Tsvg > graph.svg)
+or --mermaid for Markdown documents.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, label, err := loadGraph()
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case asJSON:
+				return printJSON(g.Edges)
+			case dot:
+				fmt.Print(g.DOT(label))
+				return nil
+			case mermaid:
+				fmt.Print(g.Mermaid(label))
+				return nil
+			}
+
+			if len(g.Edges) == 0 {
+				fmt.Println(ui.Muted("No local dependencies between projects"))
+				return nil
+			}
+
+			ui.PrintHeader("Project dependencies")
+			from := ""
+			for _, e := range g.Edges {
+				if e.From != from {
+					from = e.From
+					fmt.Println("  " + ui.Bold(label(from)))
+				}
+				fmt.Printf("    %s %s %s\n", ui.Primary(ui.ActiveGlyphs.Pointer), label(e.To), ui.Muted(e.Kind+": "+e.Spec))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dot, "dot", false, "Print as Graphviz DOT")
+	cmd.Flags().BoolVar(&mermaid, "mermaid", false, "Print as a Mermaid flowchart")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the edges as JSON")
+	cmd.MarkFlagsMutuallyExclusive("dot", "mermaid", "json")
+	return cmd
+}
+
+// ============================================================
+// DEPENDENTS
+// ============================================================
+
+func dependentsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dependents <name>",
+		Short: "List the projects depending on a project",
+		Long: `List the projects that depend on a project, directly or through other
+projects, to know what to rebuild or test after changing it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project, err := projects.Find(args[0])
+			if err != nil {
+				return err
+			}
+			g, label, err := loadGraph()
+			if err != nil {
+				return err
+			}
+			if g.Node(project.Path) == nil {
+				return fmt.Errorf("%s is not among the scanned projects", project.Name)
+			}
+
+			direct, transitive := g.Dependents(project.Path)
+			if len(direct) == 0 {
+				fmt.Println(ui.Muted("No project depends on " + project.Name))
+				return nil
+			}
+
+			ui.PrintHeader("Dependents of " + project.Name)
+			for _, path := range direct {
+				fmt.Println("  " + label(path))
+			}
+			for _, path := range transitive {
+				fmt.Println("  " + label(path) + ui.Muted(" (indirect)"))
+			}
+			return nil
+		},
+	}
+}
+
+// loadGraph builds the dependency graph of the scanned projects. The label
+// function names projects by their path relative to their root.
+func loadGraph() (*projects.Graph, func(string) string, error) {
+	registry, err := projects.DefaultRegistry()
+	if err != nil {
+		return nil, nil, err
+	}
+	list, err := scanProjects()
+	if err != nil {
+		return nil, nil, err
+	}
+	return projects.BuildGraph(list), registry.Label, nil
+}
@@ -0,0 +1,158 @@
+package projectcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/health"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// ============================================================
+// HEALTH
+// ============================================================
+
+func healthCmd() *cobra.Command {
+	var all bool
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "health [name]",
+		Short: "Score projects on git hygiene, docs, tests, lockfiles and CI",
+		Long: `Score a project from 0 to 100 using local signals only: uncommitted and
+unpushed changes, last commit age, missing README, LICENSE or tests, lockfiles
+out of date with their manifest and missing CI configuration. Every finding
+comes with an action to fix it.
+
+Without a name the current project is checked. With --all every project is
+scored, worst first.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var reports []*health.Report
+
+			switch {
+			case all:
+				list, err := scanProjects()
+				if err != nil {
+					return err
+				}
+				reports = checkAll(list)
+			default:
+				name := "."
+				if len(args) == 1 {
+					name = args[0]
+				}
+				project, err := projects.Find(name)
+				if err != nil {
+					return err
+				}
+				reports = []*health.Report{health.Check(project)}
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				enc.SetEscapeHTML(false)
+				if all {
+					return enc.Encode(reports)
+				}
+				return enc.Encode(reports[0])
+			}
+
+			if all {
+				printHealthSummary(reports)
+				return nil
+			}
+			printHealth(reports[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Score every project")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+	return cmd
+}
+
+// checkAll scores the projects in parallel, worst first
+func checkAll(list []projects.Project) []*health.Report {
+	reports := make([]*health.Report, len(list))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+
+	for i := range list {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			reports[i] = health.Check(&list[i])
+		}(i)
+	}
+	wg.Wait()
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].Score != reports[j].Score {
+			return reports[i].Score < reports[j].Score
+		}
+		return reports[i].Project < reports[j].Project
+	})
+	return reports
+}
+
+// scoreColor colors a score by how healthy it is
+func scoreColor(score int) func(...interface{}) string {
+	switch {
+	case score >= 80:
+		return ui.Success
+	case score >= 50:
+		return ui.Warning
+	default:
+		return ui.Error
+	}
+}
+
+// printHealth prints the findings of a project with their actions
+func printHealth(r *health.Report) {
+	ui.PrintHeader("Health of " + r.Project)
+	fmt.Printf("  Score  %s\n", scoreColor(r.Score)(fmt.Sprintf("%d/100", r.Score)))
+	if r.LastCommit != nil {
+		fmt.Printf("  Commit %s\n", ui.Muted(health.FormatAge(time.Since(*r.LastCommit))+" ago"))
+	}
+	fmt.Println()
+
+	if len(r.Findings) == 0 {
+		fmt.Printf("%s %s\n", ui.Success(ui.ActiveGlyphs.Check), "Nothing to fix")
+		return
+	}
+	for _, f := range r.Findings {
+		fmt.Printf("  %s %-9s %s %s\n", ui.Warning(ui.ActiveGlyphs.Warning), f.Check, f.Message, ui.Muted(fmt.Sprintf("(-%d)", f.Penalty)))
+		fmt.Printf("    %s %s\n", ui.Muted(ui.ActiveGlyphs.Pointer), ui.Muted(f.Action))
+	}
+}
+
+// printHealthSummary prints one line per project, worst first
+func printHealthSummary(reports []*health.Report) {
+	if len(reports) == 0 {
+		fmt.Println(ui.Muted("No projects found"))
+		return
+	}
+
+	ui.PrintHeader("Project health")
+	for _, r := range reports {
+		issues := ui.Success(ui.ActiveGlyphs.Check)
+		if n := len(r.Findings); n > 0 {
+			issues = ui.Muted(fmt.Sprintf("%d issue(s): %s", n, r.Findings[0].Message))
+		}
+		fmt.Printf("  %s  %-24s %s\n", scoreColor(r.Score)(fmt.Sprintf("%3d", r.Score)), r.Project, issues)
+	}
+	fmt.Println()
+	fmt.Println(ui.Muted("Details: bdev projects health <name>"))
+}
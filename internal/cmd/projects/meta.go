@@ -0,0 +1,182 @@
+package projectcmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// ============================================================
+// TAG
+// ============================================================
+
+func tagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Group projects with tags",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "add <project> <tag...>",
+		Short: "Tag a project",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateMeta(args[0], func(p *projects.Project, m *projects.Meta) {
+				m.AddTags(args[1:]...)
+				fmt.Printf("%s %s: %s\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(p.Name), strings.Join(m.Tags, ", "))
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "rm <project> <tag...>",
+		Aliases: []string{"remove"},
+		Short:   "Remove tags from a project",
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateMeta(args[0], func(p *projects.Project, m *projects.Meta) {
+				if !m.RemoveTags(args[1:]...) {
+					fmt.Println(ui.Muted(p.Name + " has none of these tags"))
+					return
+				}
+				fmt.Printf("%s Removed from %s\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(p.Name))
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "ls [project]",
+		Aliases: []string{"list"},
+		Short:   "List all tags, or the tags of a project",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := projects.LoadMeta(config.Get().ProjectMetaFile())
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				project, err := projects.Find(args[0])
+				if err != nil {
+					return err
+				}
+				m, _ := store.Lookup(project)
+				if m == nil || len(m.Tags) == 0 {
+					fmt.Println(ui.Muted(project.Name + " has no tags"))
+					return nil
+				}
+				fmt.Println(strings.Join(m.Tags, "\n"))
+				return nil
+			}
+
+			counts := store.Tags()
+			if len(counts) == 0 {
+				fmt.Println(ui.Muted("No tags yet. Add one with: bdev projects tag add <project> <tag>"))
+				return nil
+			}
+			tags := make([]string, 0, len(counts))
+			for t := range counts {
+				tags = append(tags, t)
+			}
+			sort.Strings(tags)
+			for _, t := range tags {
+				fmt.Printf("  %s %s\n", ui.Info("#"+t), ui.Muted(fmt.Sprintf("(%d)", counts[t])))
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+// ============================================================
+// FAVORITE
+// ============================================================
+
+func favoriteCmd() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:     "favorite <project>",
+		Aliases: []string{"fav"},
+		Short:   "Mark a project as favorite, listed first",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateMeta(args[0], func(p *projects.Project, m *projects.Meta) {
+				m.Favorite = !remove
+				if remove {
+					fmt.Printf("%s %s is no longer a favorite\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(p.Name))
+					return
+				}
+				fmt.Printf("%s %s %s\n", ui.Warning(ui.ActiveGlyphs.Star), ui.Bold(p.Name), ui.Muted("added to favorites"))
+			})
+		},
+	}
+
+	cmd.Flags().BoolVarP(&remove, "remove", "r", false, "Remove from favorites")
+	return cmd
+}
+
+// ============================================================
+// NOTE
+// ============================================================
+
+func noteCmd() *cobra.Command {
+	var clearNote bool
+
+	cmd := &cobra.Command{
+		Use:   "note <project> [text...]",
+		Short: "Show or set a short note on a project",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 && !clearNote {
+				project, err := projects.Find(args[0])
+				if err != nil {
+					return err
+				}
+				store, err := projects.LoadMeta(config.Get().ProjectMetaFile())
+				if err != nil {
+					return err
+				}
+				if m, _ := store.Lookup(project); m != nil && m.Note != "" {
+					fmt.Println(m.Note)
+				} else {
+					fmt.Println(ui.Muted(project.Name + " has no note"))
+				}
+				return nil
+			}
+
+			return updateMeta(args[0], func(p *projects.Project, m *projects.Meta) {
+				m.Note = strings.Join(args[1:], " ")
+				if clearNote {
+					m.Note = ""
+				}
+				fmt.Printf("%s Note updated for %s\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(p.Name))
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&clearNote, "clear", false, "Remove the note")
+	return cmd
+}
+
+// updateMeta loads the metadata of a project, applies fn and saves it
+func updateMeta(name string, fn func(*projects.Project, *projects.Meta)) error {
+	project, err := projects.Find(name)
+	if err != nil {
+		return err
+	}
+
+	store, err := projects.LoadMeta(config.Get().ProjectMetaFile())
+	if err != nil {
+		return err
+	}
+	fn(project, store.For(project))
+	return store.Save()
+}
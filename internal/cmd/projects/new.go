@@ -0,0 +1,179 @@
+package projectcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/internal/core/runner"
+	"github.com/badie/bdev/internal/core/scaffold"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// ============================================================
+// NEW
+// ============================================================
+
+func newCmd() *cobra.Command {
+	var initGit bool
+	var install bool
+	var skipHooks bool
+	var rawVars []string
+
+	cmd := &cobra.Command{
+		Use:   "new [template] [name]",
+		Short: "Create a project from a template",
+		Long: `Create a project in the projects folder from a template.
+
+Templates are searched in the directories listed under paths.templates in the
+config, then in ~/.bdev/templates, then among the bundled ones. A git URL,
+including a local bare repository, can be used instead of a template name.
+
+Files and paths may use {{ .Name }}, {{ .Date }}, {{ .Year }}, {{ .Author }}
+and any variable declared in the template's template.yml or passed with --var.
+Without arguments the available templates are listed.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 2 {
+				return fmt.Errorf("expected a template and a project name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.Get()
+			if len(args) == 0 {
+				printTemplates(scaffold.List(cfg.TemplateDirs()))
+				return nil
+			}
+
+			overrides, err := parseVars(rawVars)
+			if err != nil {
+				return err
+			}
+
+			name := args[1]
+			if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+				return fmt.Errorf("invalid project name: %s", name)
+			}
+
+			tpl, err := resolveTemplate(args[0], cfg.TemplateDirs())
+			if err != nil {
+				return err
+			}
+			if tpl.cleanup != nil {
+				defer tpl.cleanup()
+			}
+
+			if _, ok := overrides["Author"]; !ok && cfg.User.Name != "" {
+				overrides["Author"] = cfg.User.Name
+			}
+			vars := scaffold.Vars(name, tpl.Spec, overrides)
+
+			dest := filepath.Join(cfg.Paths.Projects, name)
+			fmt.Println(ui.Primary("Creating ") + ui.Bold(name) + ui.Muted(" from "+tpl.Name))
+			if err := tpl.Create(dest, vars); err != nil {
+				return err
+			}
+			fmt.Printf("%s Copied template to %s\n", ui.Success(ui.ActiveGlyphs.Check), dest)
+
+			if !skipHooks && len(tpl.Spec.PostCreate) > 0 {
+				fmt.Println(ui.Muted("Running post-create hooks..."))
+				if err := tpl.RunHooks(dest, os.Stdout, os.Stderr); err != nil {
+					return err
+				}
+				fmt.Printf("%s Ran %d post-create hook(s)\n", ui.Success(ui.ActiveGlyphs.Check), len(tpl.Spec.PostCreate))
+			}
+
+			if install {
+				project := projects.Analyze(dest)
+				if project == nil {
+					return fmt.Errorf("cannot install dependencies: %s is not a recognized project", dest)
+				}
+				if err := runner.New(project).Install(); err != nil {
+					return err
+				}
+				fmt.Printf("%s Installed dependencies\n", ui.Success(ui.ActiveGlyphs.Check))
+			}
+
+			// Commit last so hook and install output such as lock files is included
+			if initGit {
+				if err := scaffold.InitGit(dest, tpl.Name); err != nil {
+					return err
+				}
+				fmt.Printf("%s Initialized git repository\n", ui.Success(ui.ActiveGlyphs.Check))
+			}
+
+			projects.RecordUse(cfg.UsageFile(), dest)
+			fmt.Println()
+			fmt.Println(ui.Muted("Next: ") + "cd " + dest)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&initGit, "git", false, "Initialize a git repository with a first commit")
+	cmd.Flags().BoolVar(&install, "install", false, "Install dependencies after creating the project")
+	cmd.Flags().BoolVar(&skipHooks, "skip-hooks", false, "Do not run the template's post-create hooks")
+	cmd.Flags().StringArrayVar(&rawVars, "var", nil, "Set a template variable (key=value, repeatable)")
+	return cmd
+}
+
+// resolvedTemplate is a template with the cleanup of its git clone, if any
+type resolvedTemplate struct {
+	*scaffold.Template
+	cleanup func()
+}
+
+// resolveTemplate finds a template by name or clones it from a git URL
+func resolveTemplate(arg string, dirs []string) (*resolvedTemplate, error) {
+	if scaffold.IsGitURL(arg) {
+		fmt.Println(ui.Muted("Cloning template " + arg + "..."))
+		tpl, cleanup, err := scaffold.FromGit(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &resolvedTemplate{Template: tpl, cleanup: cleanup}, nil
+	}
+
+	tpl, err := scaffold.Find(arg, dirs)
+	if err != nil {
+		return nil, err
+	}
+	return &resolvedTemplate{Template: tpl}, nil
+}
+
+// parseVars parses key=value pairs given with --var
+func parseVars(raw []string) (map[string]string, error) {
+	vars := make(map[string]string, len(raw))
+	for _, kv := range raw {
+		key, value, ok := strings.Cut(kv, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q, expected key=value", kv)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// printTemplates lists templates with their description and origin
+func printTemplates(list []scaffold.Template) {
+	if len(list) == 0 {
+		fmt.Println(ui.Muted("No templates found"))
+		return
+	}
+
+	ui.PrintHeader("Templates")
+	for _, t := range list {
+		line := "  " + ui.Bold(t.Name)
+		if t.Spec.Description != "" {
+			line += "  " + t.Spec.Description
+		}
+		fmt.Println(line + "  " + ui.Muted(t.Source))
+	}
+	fmt.Println()
+	fmt.Println(ui.Muted("Create one with: bdev projects new <template> <name>"))
+}
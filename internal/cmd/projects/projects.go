@@ -5,15 +5,30 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/badie/bdev/internal/core/clean"
 	"github.com/badie/bdev/internal/core/config"
 	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/internal/core/runner"
 	"github.com/badie/bdev/pkg/ui"
 )
 
+// refresh bypasses the project index
+var refresh bool
+
+// scanProjects lists the projects of every root and the registered ones,
+// honoring --refresh
+func scanProjects() ([]projects.Project, error) {
+	if refresh {
+		return projects.RefreshAll()
+	}
+	return projects.ScanAll()
+}
+
 // NewCommand creates the projects command group
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -23,11 +38,25 @@ func NewCommand() *cobra.Command {
 		Long:    "List, create, and manage your development projects",
 	}
 
+	cmd.PersistentFlags().BoolVar(&refresh, "refresh", false, "Rescan projects instead of using the project index")
+
 	cmd.AddCommand(listCmd())
 	cmd.AddCommand(openCmd())
 	cmd.AddCommand(runCmd())
 	cmd.AddCommand(findCmd())
 	cmd.AddCommand(describeCmd())
+	cmd.AddCommand(tagCmd())
+	cmd.AddCommand(favoriteCmd())
+	cmd.AddCommand(noteCmd())
+	cmd.AddCommand(newCmd())
+	cmd.AddCommand(healthCmd())
+	cmd.AddCommand(statsCmd())
+	cmd.AddCommand(graphCmd())
+	cmd.AddCommand(dependentsCmd())
+	cmd.AddCommand(typesCmd())
+	cmd.AddCommand(addCmd())
+	cmd.AddCommand(removeCmd())
+	cmd.AddCommand(rootsCmd())
 
 	return cmd
 }
@@ -39,39 +68,69 @@ func NewCommand() *cobra.Command {
 func listCmd() *cobra.Command {
 	var sortBy string
 	var filterType string
+	var filterTags []string
+	var tree bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all projects",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.Get()
-			projectsDir := cfg.Paths.Projects
+			registry, err := projects.DefaultRegistry()
+			if err != nil {
+				return err
+			}
 
-			projectList, err := projects.Scan(projectsDir)
+			projectList, err := scanProjects()
 			if err != nil {
 				return err
 			}
 
 			if len(projectList) == 0 {
-				fmt.Println(ui.Muted("No projects found in " + projectsDir))
+				fmt.Println(ui.Muted("No projects found in " + rootPaths(registry)))
 				return nil
 			}
 
+			if filterType != "" {
+				projectList = withType(projectList, filterType)
+				if len(projectList) == 0 {
+					fmt.Println(ui.Muted("No " + filterType + " projects"))
+					return nil
+				}
+			}
+
+			if len(filterTags) > 0 {
+				projectList = withTags(projectList, filterTags)
+				if len(projectList) == 0 {
+					fmt.Println(ui.Muted("No projects tagged " + strings.Join(filterTags, ", ")))
+					return nil
+				}
+			}
+
 			fmt.Println(ui.Bold(fmt.Sprintf("Projects (%d)", len(projectList))))
 			fmt.Println(ui.Muted(ui.SeparatorLight))
 			fmt.Println()
 
-			for _, p := range projectList {
-				icon := p.Type.Icon()
-				typeName := p.Type.String()
+			if tree {
+				printTree(buildTree(registry, projectList), 1)
+				return nil
+			}
 
-				line := fmt.Sprintf("  %s %s", icon, ui.Bold(p.Name))
-				line += ui.Muted(fmt.Sprintf(" (%s)", typeName))
+			if sortBy == "size" || sortBy == "code" {
+				projects.EnsureStats(projectList, cfg.ProjectIndexFile(), refresh)
+			}
+			if err := sortProjects(projectList, sortBy); err != nil {
+				return err
+			}
 
-				if p.GitBranch != "" {
-					line += ui.Primary(fmt.Sprintf(" [%s]", p.GitBranch))
+			for _, p := range projectList {
+				line := "  " + projectLine(p)
+				switch {
+				case sortBy == "size" && p.Stats != nil:
+					line += ui.Muted(" " + clean.FormatSize(p.Size))
+				case sortBy == "code" && p.Stats != nil:
+					line += ui.Muted(" " + itoa(linesOfCode(&p)) + " lines")
 				}
-
 				fmt.Println(line)
 			}
 
@@ -79,11 +138,144 @@ func listCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&sortBy, "sort", "s", "name", "Sort by: name, type, date")
+	cmd.Flags().StringVarP(&sortBy, "sort", "s", "date", "Sort by: name, type, date, size, code")
 	cmd.Flags().StringVarP(&filterType, "type", "t", "", "Filter by type")
+	cmd.Flags().StringSliceVar(&filterTags, "tag", nil, "Only show projects with any of these tags")
+	cmd.Flags().BoolVar(&tree, "tree", false, "Show groups and workspace members as a tree")
 	return cmd
 }
 
+// withType returns the projects of a type, matched by ID or name ignoring
+// case and punctuation, so "next.js" and "nextjs" match Next.js
+func withType(list []projects.Project, typ string) []projects.Project {
+	var found []projects.Project
+	want := normalizeType(typ)
+	for _, p := range list {
+		if normalizeType(string(p.Type)) == want || normalizeType(p.Type.String()) == want {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+func normalizeType(s string) string {
+	return strings.ToLower(strings.NewReplacer(".", "", "-", "", " ", "").Replace(s))
+}
+
+// withTags returns the projects and workspace members carrying any of the tags
+func withTags(list []projects.Project, tags []string) []projects.Project {
+	var found []projects.Project
+	for _, p := range projects.Flatten(list) {
+		for _, t := range tags {
+			if p.HasTag(t) {
+				p.Children = nil
+				found = append(found, p)
+				break
+			}
+		}
+	}
+	return found
+}
+
+func projectLine(p projects.Project) string {
+	line := fmt.Sprintf("%s %s", p.Type.Icon(), ui.Bold(p.Name))
+	if p.Favorite {
+		line += " " + ui.Warning(ui.ActiveGlyphs.Star)
+	}
+	line += ui.Muted(fmt.Sprintf(" (%s)", p.Type.String()))
+
+	if p.Workspace != "" {
+		line += ui.Muted(fmt.Sprintf(" %s workspace", p.Workspace))
+	}
+	if p.GitBranch != "" {
+		line += ui.Primary(fmt.Sprintf(" [%s]", p.GitBranch))
+	}
+	for _, t := range p.Tags {
+		line += ui.Info(" #" + t)
+	}
+	return line
+}
+
+// treeNode is a group directory or a project in `projects list --tree`
+type treeNode struct {
+	name     string
+	project  *projects.Project
+	children []*treeNode
+}
+
+// buildTree arranges projects by their path below root, with workspace
+// members nested under their root
+func buildTree(registry *projects.Registry, list []projects.Project) *treeNode {
+	// With several roots or registered projects, each root is a top-level node
+	grouped := len(registry.Roots) > 1 || len(registry.Projects) > 0
+
+	top := &treeNode{}
+	for i := range list {
+		p := &list[i]
+		parts := strings.Split(registry.Label(p.Path), "/")
+		if grouped {
+			group := "registered"
+			if root, ok := registry.RootOf(p.Path); ok {
+				group = root.Path
+			}
+			parts = append([]string{group}, parts...)
+		}
+		top.insert(parts, p)
+	}
+	return top
+}
+
+// rootPaths joins the project roots for messages
+func rootPaths(registry *projects.Registry) string {
+	paths := make([]string, len(registry.Roots))
+	for i, root := range registry.Roots {
+		paths[i] = root.Path
+	}
+	return strings.Join(paths, ", ")
+}
+
+func (n *treeNode) insert(parts []string, p *projects.Project) {
+	node := n
+	for _, part := range parts {
+		var next *treeNode
+		for _, c := range node.children {
+			if c.name == part {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			next = &treeNode{name: part}
+			node.children = append(node.children, next)
+		}
+		node = next
+	}
+	node.project = p
+
+	for i := range p.Children {
+		child := &p.Children[i]
+		rel, err := filepath.Rel(p.Path, child.Path)
+		if err != nil {
+			rel = child.Name
+		}
+		node.insert(strings.Split(filepath.ToSlash(rel), "/"), child)
+	}
+}
+
+func printTree(n *treeNode, depth int) {
+	sort.SliceStable(n.children, func(i, j int) bool { return n.children[i].name < n.children[j].name })
+
+	indent := strings.Repeat("  ", depth)
+	for _, c := range n.children {
+		if c.project != nil {
+			fmt.Println(indent + projectLine(*c.project))
+		} else {
+			fmt.Println(indent + ui.Muted(ui.ActiveGlyphs.Folder+" "+c.name+"/"))
+		}
+		printTree(c, depth+1)
+	}
+}
+
 // ============================================================
 // OPEN
 // ============================================================
@@ -98,10 +290,11 @@ func openCmd() *cobra.Command {
 			projectName := args[0]
 
 			// Find project
-			projectPath := filepath.Join(cfg.Paths.Projects, projectName)
-			if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-				return fmt.Errorf("project not found: %s", projectName)
+			project, err := projects.Find(projectName)
+			if err != nil {
+				return err
 			}
+			projectPath := project.Path
 
 			// Open in VS Code
 			vscode := exec.Command("code", projectPath)
@@ -109,6 +302,7 @@ func openCmd() *cobra.Command {
 				return fmt.Errorf("failed to open VS Code: %v", err)
 			}
 
+			projects.RecordUse(cfg.UsageFile(), projectPath)
 			fmt.Println(ui.Success("Opening ") + ui.Bold(projectName) + ui.Muted(" in VS Code"))
 			return nil
 		},
@@ -132,11 +326,12 @@ func runCmd() *cobra.Command {
 				script = args[1]
 			}
 
-			projectPath := filepath.Join(cfg.Paths.Projects, projectName)
-			project := projects.Analyze(projectPath)
-			if project == nil {
-				return fmt.Errorf("project not found: %s", projectName)
+			project, err := projects.Find(projectName)
+			if err != nil {
+				return err
 			}
+			projectPath := project.Path
+			projects.RecordUse(cfg.UsageFile(), projectPath)
 
 			// Get the appropriate command
 			var runCmd *exec.Cmd
@@ -160,8 +355,13 @@ func runCmd() *cobra.Command {
 				}
 				runCmd = exec.Command(bin, cmdArgs...)
 			default:
-				// Run npm script
-				runCmd = exec.Command("npm", "run", script)
+				// .bdev.yml tasks take precedence over package scripts
+				if _, ok := project.Overrides().Tasks[script]; ok {
+					return runner.New(project).RunTask(script, args[2:])
+				}
+				// Run package.json script through the project's package manager
+				bin, cmdArgs := project.GetPackageManager().RunScript(script)
+				runCmd = exec.Command(bin, cmdArgs...)
 			}
 
 			runCmd.Dir = projectPath
@@ -184,25 +384,20 @@ func runCmd() *cobra.Command {
 func findCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "find [query]",
-		Short: "Search for projects by name",
+		Short: "Fuzzy search for projects by name",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.Get()
 			query := args[0]
 
-			projectList, err := projects.Scan(cfg.Paths.Projects)
+			projectList, err := scanProjects()
 			if err != nil {
 				return err
 			}
 
-			matches := make([]projects.Project, 0)
-			queryLower := strings.ToLower(query)
-
-			for _, p := range projectList {
-				if containsIgnoreCase(p.Name, queryLower) {
-					matches = append(matches, p)
-				}
-			}
+			// Fuzzy match, frequently and recently used projects first
+			usage, _ := projects.LoadUsage(cfg.UsageFile())
+			matches := projects.Rank(projects.Flatten(projectList), query, usage)
 
 			if len(matches) == 0 {
 				fmt.Println(ui.Muted("No projects matching: " + query))
@@ -210,8 +405,8 @@ func findCmd() *cobra.Command {
 			}
 
 			fmt.Println(ui.Bold(fmt.Sprintf("Found %d project(s)", len(matches))))
-			for _, p := range matches {
-				fmt.Printf("  %s %s %s\n", p.Type.Icon(), ui.Bold(p.Name), ui.Muted(p.Path))
+			for _, m := range matches {
+				fmt.Printf("  %s %s %s\n", m.Project.Type.Icon(), ui.Bold(m.Project.Name), ui.Muted(m.Project.Path))
 			}
 
 			return nil
@@ -219,10 +414,6 @@ func findCmd() *cobra.Command {
 	}
 }
 
-func containsIgnoreCase(s, substr string) bool {
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
-}
-
 // ============================================================
 // DESCRIBE
 // ============================================================
@@ -234,12 +425,9 @@ func describeCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.Get()
-			projectName := args[0]
-
-			projectPath := filepath.Join(cfg.Paths.Projects, projectName)
-			project := projects.Analyze(projectPath)
-			if project == nil {
-				return fmt.Errorf("project not found: %s", projectName)
+			project, err := projects.Find(args[0])
+			if err != nil {
+				return err
 			}
 
 			fmt.Println(ui.Bold(project.Name))
@@ -251,11 +439,29 @@ func describeCmd() *cobra.Command {
 				fmt.Printf("  %s %s\n", ui.Primary("Framework:"), project.Framework)
 			}
 
+			if pm := project.GetPackageManager(); pm != projects.PMNone {
+				fmt.Printf("  %s %s\n", ui.Primary("Package Manager:"), pm)
+			}
+
 			if project.HasGit {
 				fmt.Printf("  %s %s\n", ui.Primary("Git:"), ui.Success("yes"))
 				fmt.Printf("  %s %s\n", ui.Primary("Branch:"), project.GitBranch)
 			}
 
+			if store, err := projects.LoadMeta(cfg.ProjectMetaFile()); err == nil {
+				if m, _ := store.Lookup(project); m != nil {
+					if m.Favorite {
+						fmt.Printf("  %s %s\n", ui.Primary("Favorite:"), ui.Warning(ui.ActiveGlyphs.Star))
+					}
+					if len(m.Tags) > 0 {
+						fmt.Printf("  %s %s\n", ui.Primary("Tags:"), strings.Join(m.Tags, ", "))
+					}
+					if m.Note != "" {
+						fmt.Printf("  %s %s\n", ui.Primary("Note:"), m.Note)
+					}
+				}
+			}
+
 			// Scripts
 			if len(project.Scripts) > 0 {
 				fmt.Println()
@@ -268,6 +474,32 @@ func describeCmd() *cobra.Command {
 				}
 			}
 
+			// Tasks from .bdev.yml
+			if pf := project.Overrides(); len(pf.Tasks) > 0 {
+				fmt.Println()
+				fmt.Println(ui.Bold("Tasks:"))
+				for _, name := range pf.TaskNames() {
+					fmt.Printf("  %s %s\n", ui.Primary(name+":"), ui.Muted(pf.Tasks[name].Command))
+				}
+			}
+
+			// Compose services
+			if c := project.Containers; c != nil && c.ComposeFile != "" {
+				fmt.Println()
+				fmt.Println(ui.Bold("Services (" + c.ComposeFile + "):"))
+				for _, s := range c.Services {
+					image := s.Image
+					if image == "" {
+						image = "build " + s.Build
+					}
+					var ports []string
+					for _, p := range s.Ports {
+						ports = append(ports, p.String())
+					}
+					fmt.Printf("  %s %s %s\n", ui.Primary(s.Name+":"), image, ui.Muted(strings.Join(ports, ", ")))
+				}
+			}
+
 			return nil
 		},
 	}
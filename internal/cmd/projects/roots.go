@@ -0,0 +1,137 @@
+package projectcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// ============================================================
+// ADD / REMOVE
+// ============================================================
+
+func addCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <path>",
+		Short: "Register a project outside the project roots",
+		Long: `Register a project directory so it is listed, searched and run like the
+projects found under the project roots (paths.projects and paths.roots).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := projects.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+			path, err := registry.Add(args[0])
+			if err != nil {
+				return err
+			}
+			if err := registry.Save(); err != nil {
+				return err
+			}
+
+			project := projects.Analyze(path)
+			fmt.Printf("%s Registered %s %s\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(project.Name), ui.Muted(project.Type.String()))
+			if root, ok := registry.RootOf(path); ok {
+				fmt.Println(ui.Muted("It is also under the root " + root.Path + ", it is listed once"))
+			}
+			return nil
+		},
+	}
+}
+
+func removeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <path|name>",
+		Aliases: []string{"rm"},
+		Short:   "Unregister a project added with projects add",
+		Long:    "Unregister a project added with projects add. Its files are not touched.",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := projects.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+			path, err := registry.Remove(args[0])
+			if err != nil {
+				return err
+			}
+			if err := registry.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("%s Unregistered %s\n", ui.Success(ui.ActiveGlyphs.Check), path)
+			return nil
+		},
+	}
+}
+
+// ============================================================
+// ROOTS
+// ============================================================
+
+func rootsCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "roots",
+		Short: "List the project roots and registered projects",
+		Long: `List where projects are found: paths.projects, the paths.roots of the
+config and the projects registered with projects add.
+
+  "paths": {
+    "projects": "~/Dev/Projects",
+    "roots": [
+      {"path": "~/work", "scan_depth": 3},
+      {"path": "/mnt/clients", "ignore": ["archive", "old-*"]}
+    ]
+  }`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := projects.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				return printJSON(map[string]interface{}{"roots": registry.Roots, "projects": registry.Projects})
+			}
+
+			ui.PrintHeader("Project roots")
+			for _, root := range registry.Roots {
+				line := fmt.Sprintf("  %s %s %s", ui.ActiveGlyphs.Folder, root.Path, ui.Muted("depth "+itoa(root.ScanDepth)))
+				if len(root.Ignore) > 0 {
+					line += ui.Muted(fmt.Sprintf(", ignoring %v", root.Ignore))
+				}
+				if !exists(root.Path) {
+					line += " " + ui.Warning("(not found)")
+				}
+				fmt.Println(line)
+			}
+
+			if len(registry.Projects) > 0 {
+				fmt.Println()
+				fmt.Println(ui.Bold("Registered projects:"))
+				for _, path := range registry.Projects {
+					line := "  " + path
+					if !exists(path) {
+						line += " " + ui.Warning("(not found)")
+					}
+					fmt.Println(line)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+	return cmd
+}
+
+// exists reports whether a root or registered project is reachable
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,200 @@
+package projectcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/clean"
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// ============================================================
+// STATS
+// ============================================================
+
+func statsCmd() *cobra.Command {
+	var sortBy string
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "stats [name]",
+		Short: "Show disk size and lines of code per language",
+		Long: `Show the disk size of projects split into sources, installed dependencies and
+other ignored content (.git, build output), with files and lines of code,
+comments and blanks per language. Files ignored by .gitignore are not counted
+as sources.
+
+Stats are cached in the project index until a file or directory of the
+project is modified; use --refresh to measure again. Without a name every
+project is summarized.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.Get()
+
+			if len(args) == 1 {
+				project, err := projects.Find(args[0])
+				if err != nil {
+					return err
+				}
+				// A single project is quick to measure, so it is always fresh
+				list := []projects.Project{*project}
+				projects.EnsureStats(list, cfg.ProjectIndexFile(), true)
+				if list[0].Stats == nil {
+					return fmt.Errorf("failed to measure %s", project.Name)
+				}
+				if asJSON {
+					return printJSON(list[0].Stats)
+				}
+				printStats(&list[0])
+				return nil
+			}
+
+			list, err := scanProjects()
+			if err != nil {
+				return err
+			}
+			projects.EnsureStats(list, cfg.ProjectIndexFile(), refresh)
+			if err := sortProjects(list, sortBy); err != nil {
+				return err
+			}
+
+			if asJSON {
+				out := make(map[string]*projects.Stats, len(list))
+				for _, p := range list {
+					out[p.Path] = p.Stats
+				}
+				return printJSON(out)
+			}
+			printStatsSummary(list)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&sortBy, "sort", "s", "size", "Sort by: size, code, name")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+	return cmd
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printStats prints the language table and disk usage of a project
+func printStats(p *projects.Project) {
+	s := p.Stats
+	ui.PrintHeader("Stats of " + p.Name)
+
+	row := " %-16s %7s %9s %9s %9s\n"
+	fmt.Print(ui.Bold(fmt.Sprintf(row, "Language", "Files", "Code", "Comments", "Blanks")))
+	fmt.Println(ui.Muted(ui.SeparatorLight))
+	if len(s.Languages) == 0 {
+		fmt.Println(ui.Muted(" No source code found"))
+	}
+	for _, l := range s.Languages {
+		fmt.Printf(row, l.Language, itoa(l.Files), itoa(l.Code), itoa(l.Comments), itoa(l.Blanks))
+	}
+	if len(s.Languages) > 1 {
+		t := s.Total()
+		fmt.Println(ui.Muted(ui.SeparatorLight))
+		fmt.Print(ui.Bold(fmt.Sprintf(row, t.Language, itoa(t.Files), itoa(t.Code), itoa(t.Comments), itoa(t.Blanks))))
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-13s %10s  %s\n", "Sources", clean.FormatSize(s.SourceBytes), ui.Muted(fmt.Sprintf("%d files", s.SourceFiles)))
+	fmt.Printf("  %-13s %10s  %s\n", "Dependencies", clean.FormatSize(s.DependencyBytes), ui.Muted(fmt.Sprintf("%d files", s.DependencyFiles)))
+	fmt.Printf("  %-13s %10s  %s\n", "Other", clean.FormatSize(s.OtherBytes), ui.Muted(fmt.Sprintf("%d files (.git, build output)", s.OtherFiles)))
+	fmt.Print(ui.Bold(fmt.Sprintf("  %-13s %10s\n", "Total", clean.FormatSize(s.TotalBytes()))))
+}
+
+// printStatsSummary prints one line per project and the totals
+func printStatsSummary(list []projects.Project) {
+	if len(list) == 0 {
+		fmt.Println(ui.Muted("No projects found"))
+		return
+	}
+
+	ui.PrintHeader("Project stats")
+	row := "  %-24s %10s %10s %10s  %s\n"
+	fmt.Print(ui.Bold(fmt.Sprintf(row, "Project", "Size", "Deps", "Code", "Main language")))
+	fmt.Println(ui.Muted(ui.SeparatorLight))
+
+	var size, deps int64
+	var code int
+	for _, p := range list {
+		if p.Stats == nil {
+			fmt.Printf(row, p.Name, "-", "-", "-", ui.Muted("not measured"))
+			continue
+		}
+		main := "-"
+		if len(p.Stats.Languages) > 0 {
+			main = p.Stats.Languages[0].Language
+		}
+		total := p.Stats.Total()
+		fmt.Printf(row, p.Name, clean.FormatSize(p.Size), clean.FormatSize(p.Stats.DependencyBytes), itoa(total.Code), main)
+
+		size += p.Size
+		deps += p.Stats.DependencyBytes
+		code += total.Code
+	}
+
+	fmt.Println(ui.Muted(ui.SeparatorLight))
+	fmt.Print(ui.Bold(fmt.Sprintf(row, fmt.Sprintf("Total (%d)", len(list)), clean.FormatSize(size), clean.FormatSize(deps), itoa(code), "")))
+}
+
+// sortProjects orders projects by name, type, date (favorites first, most
+// recent first), size or code (largest first)
+func sortProjects(list []projects.Project, by string) error {
+	var less func(a, b *projects.Project) bool
+	switch by {
+	case "", "date":
+		less = func(a, b *projects.Project) bool {
+			if a.Favorite != b.Favorite {
+				return a.Favorite
+			}
+			return a.LastModified.After(b.LastModified)
+		}
+	case "name":
+		less = func(a, b *projects.Project) bool { return a.Name < b.Name }
+	case "type":
+		less = func(a, b *projects.Project) bool {
+			if a.Type != b.Type {
+				return a.Type.String() < b.Type.String()
+			}
+			return a.Name < b.Name
+		}
+	case "size":
+		less = func(a, b *projects.Project) bool { return a.Size > b.Size }
+	case "code":
+		less = func(a, b *projects.Project) bool { return linesOfCode(a) > linesOfCode(b) }
+	default:
+		return fmt.Errorf("unknown sort %q (use name, type, date, size or code)", by)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return less(&list[i], &list[j]) })
+	return nil
+}
+
+func linesOfCode(p *projects.Project) int {
+	if p.Stats == nil {
+		return 0
+	}
+	return p.Stats.Total().Code
+}
+
+// itoa formats a count with thousands separators
+func itoa(n int) string {
+	s := fmt.Sprint(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+package projectcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// ============================================================
+// TYPES
+// ============================================================
+
+func typesCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "types [id]",
+		Short: "List project type definitions and their detection rules",
+		Long: `List the project types bdev detects. Types are defined by YAML files: the
+built-in ones, plus *.yml files in the project-types folder of the bdev
+directory. A user file with the id of a built-in type replaces it; print
+the built-in one with "bdev projects types <id> --json" to start from.
+
+  id: dotnet
+  name: .NET
+  icon: "[NE]"
+  detect:
+    - files: ["*.csproj", "*.sln"]
+      priority: 150
+  commands:
+    start:
+      - run: dotnet run --urls http://localhost:{{port}}
+    test: dotnet test
+    build: dotnet build -c Release
+    install: dotnet restore
+    format: dotnet format
+    format_check: dotnet format --verify-no-changes
+  port: 5000
+  artifacts: [bin, obj]
+  watch: ["**/*.cs", "*.csproj"]
+
+A detect rule matches when any of its files exists, any of its
+dependencies is in package.json, every contains pattern matches the file
+content and a top-level file has any of its extensions. The matching rule
+with the highest priority wins. Built-in config files use 120-190,
+package.json dependencies 90-99 and file extensions 25-50.
+
+Commands are start, test, build, install, lint, lint_fix, format and
+format_check. Each is a command line or a list of options, the first
+whose conditions hold is used. An option has one of run (a command line,
+with {{name}}, {{image}} and {{port}}), exec (a tool run through the
+package manager), script (a package script, if it exists) or install
+(the package manager install). Options, ports and artifacts take the
+detect conditions plus scripts, script_uses, package_managers and tools.
+
+An ecosystem of js or python only lets the package managers of that
+ecosystem be detected, so a Python project keeps pip next to a
+package-lock.json. deps (npm, go, pip, cargo or composer) enables
+dependency updates and audits.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				t, ok := projects.ParseType(args[0])
+				if !ok {
+					return fmt.Errorf("unknown project type: %s", args[0])
+				}
+				def := projects.LookupType(t)
+				if asJSON {
+					return printJSON(def)
+				}
+				printTypeDef(def)
+				return nil
+			}
+
+			defs := projects.Types()
+			if asJSON {
+				return printJSON(defs)
+			}
+
+			ui.PrintHeader("Project types")
+			for _, def := range defs {
+				source := ""
+				if def.Source != projects.SourceBuiltin {
+					source = ui.Info(" " + def.Source)
+				}
+				fmt.Printf("  %-5s %-14s %s%s\n", def.Icon, def.Name, ui.Muted(string(def.ID)), source)
+			}
+
+			if errs := projects.TypeErrors(); len(errs) > 0 {
+				fmt.Println()
+				fmt.Println(ui.Warning(fmt.Sprintf("Skipped invalid definitions in %s:", config.Get().ProjectTypesDir())))
+				for _, err := range errs {
+					fmt.Printf("  %s %v\n", ui.Error(ui.ActiveGlyphs.Cross), err)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+	return cmd
+}
+
+// printTypeDef prints the rules and commands of a type
+func printTypeDef(def *projects.TypeDef) {
+	ui.PrintHeader(def.Icon + " " + def.Name)
+	fmt.Printf("  %s %s\n", ui.Primary("ID:"), string(def.ID))
+	fmt.Printf("  %s %s\n", ui.Primary("Source:"), def.Source)
+
+	fmt.Println()
+	fmt.Println(ui.Bold("Detection:"))
+	for _, r := range def.Detect {
+		fmt.Printf("  %s %s\n", ui.Muted(fmt.Sprintf("%4d", r.Priority)), strings.Join(matchConditions(r.DirMatch), " and "))
+	}
+
+	var commands [][2]string
+	for _, kind := range projects.CommandKinds {
+		for i, o := range def.Commands.Options(kind) {
+			label := ""
+			if i == 0 {
+				label = kind + ":"
+			}
+			commands = append(commands, [2]string{label, describeOption(o)})
+		}
+	}
+	if len(commands) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Bold("Commands:"))
+		for _, c := range commands {
+			fmt.Printf("  %s %s\n", ui.Primary(fmt.Sprintf("%-13s", c[0])), c[1])
+		}
+	}
+
+	if len(def.Port) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Bold("Port:"))
+		for _, o := range def.Port {
+			fmt.Printf("  %d%s\n", o.Port, describeCondition(o.Condition))
+		}
+	}
+	if len(def.Artifacts) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Bold("Artifacts:"))
+		for _, a := range def.Artifacts {
+			name := a.Name
+			if a.Recursive {
+				name = "**/" + name
+			}
+			fmt.Printf("  %s%s\n", name, describeCondition(a.Condition))
+		}
+	}
+	if len(def.Watch) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %s\n", ui.Bold("Watch:"), strings.Join(def.Watch, " "))
+	}
+}
+
+// describeOption returns what a command option runs and when
+func describeOption(o projects.CommandOption) string {
+	var s string
+	switch {
+	case o.Script != "":
+		s = "script " + o.Script
+		if len(o.Args) > 0 {
+			s += " " + strings.Join(o.Args, " ")
+		}
+	case o.Exec != "":
+		s = o.Exec + ui.Muted(" (through the package manager)")
+	case o.Install:
+		s = "package manager install"
+	default:
+		s = o.Run
+		if o.Wrapper != "" {
+			s += ui.Muted(" (or " + o.Wrapper + ")")
+		}
+	}
+	if o.Sources != nil {
+		s += " " + ui.Muted("<"+strings.Join(o.Sources.Extensions, " ")+" files>")
+	}
+	if o.Port != "" {
+		s += ui.Muted(" port " + o.Port)
+	}
+	return s + describeCondition(o.Condition)
+}
+
+// describeCondition returns " if ..." for a condition, or "" if it has none
+func describeCondition(c projects.Condition) string {
+	conditions := matchConditions(c.DirMatch)
+	if len(c.Scripts) > 0 {
+		conditions = append(conditions, "scripts "+strings.Join(c.Scripts, ", "))
+	}
+	if len(c.ScriptUses) > 0 {
+		conditions = append(conditions, "scripts using "+strings.Join(c.ScriptUses, ", "))
+	}
+	if len(c.PackageManagers) > 0 {
+		names := make([]string, len(c.PackageManagers))
+		for i, pm := range c.PackageManagers {
+			names[i] = string(pm)
+		}
+		conditions = append(conditions, "package manager "+strings.Join(names, ", "))
+	}
+	if len(c.Tools) > 0 {
+		conditions = append(conditions, "installed "+strings.Join(c.Tools, ", "))
+	}
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ui.Muted(" if " + strings.Join(conditions, " and "))
+}
+
+// matchConditions describes the directory conditions of a rule
+func matchConditions(m projects.DirMatch) []string {
+	var conditions []string
+	if len(m.Files) > 0 {
+		conditions = append(conditions, "files "+strings.Join(m.Files, ", "))
+	}
+	if len(m.Dependencies) > 0 {
+		conditions = append(conditions, "dependencies "+strings.Join(m.Dependencies, ", "))
+	}
+	for _, c := range m.Contains {
+		conditions = append(conditions, fmt.Sprintf("%s contains /%s/", c.File, c.Pattern))
+	}
+	if len(m.Extensions) > 0 {
+		conditions = append(conditions, "extensions "+strings.Join(m.Extensions, ", "))
+	}
+	return conditions
+}
@@ -0,0 +1,255 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/backup"
+	"github.com/badie/bdev/internal/core/clean"
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// newBackupCmd creates the `bdev backup` command group
+func newBackupCmd() *cobra.Command {
+	var all bool
+	var skipGit bool
+	var noPrune bool
+
+	cmd := &cobra.Command{
+		Use:   "backup [project]",
+		Short: "Back up projects to compressed archives",
+		Long: `Write a .tar.gz archive of a project to paths.backups, with a manifest holding
+the checksum of every file. Files ignored by the project .gitignore and
+dependency directories such as node_modules are left out; the .git directory
+is kept unless --skip-git is set. Without arguments the current project is
+backed up.
+
+Old backups are then removed following backup.keep_daily and
+backup.keep_weekly: the latest backup of each of the last 7 days and 4 weeks
+having backups is kept by default.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.Get()
+			list, err := cleanTargets(args, all)
+			if err != nil {
+				return err
+			}
+			registry, err := projects.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+
+			ui.PrintHeader("Backup")
+			names := make(map[string]bool)
+			failed := 0
+			for _, p := range list {
+				name := backup.Name(registry.Label(p.Path))
+				start := time.Now()
+				m, err := backup.Create(p.Path, backupDir(name), name, backup.Options{SkipGit: skipGit})
+				if err != nil {
+					fmt.Printf("  %s %s %s\n", ui.Error(ui.ActiveGlyphs.Cross), ui.Bold(p.Name), ui.Error(err.Error()))
+					failed++
+					continue
+				}
+				names[name] = true
+				fmt.Printf("  %s %s %s\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(p.Name), ui.Muted(fmt.Sprintf("%d files, %s -> %s in %s",
+					len(m.Files), clean.FormatSize(m.SourceSize()), clean.FormatSize(m.Size), time.Since(start).Round(time.Millisecond))))
+				fmt.Printf("    %s\n", ui.Muted(m.ArchivePath()))
+			}
+
+			if !noPrune && len(names) > 0 {
+				backups, err := backup.List(cfg.Paths.Backups)
+				if err != nil {
+					return err
+				}
+				var expired []*backup.Manifest
+				for _, m := range retention(cfg).Expired(backups) {
+					if names[m.Name] {
+						expired = append(expired, m)
+					}
+				}
+				if err := removeBackups(expired); err != nil {
+					return err
+				}
+				if len(expired) > 0 {
+					fmt.Println()
+					fmt.Println(ui.Muted(fmt.Sprintf("Removed %d old backup(s)", len(expired))))
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d backups failed", failed, len(list))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Back up every project")
+	cmd.Flags().BoolVar(&skipGit, "skip-git", false, "Leave out the .git directory")
+	cmd.Flags().BoolVar(&noPrune, "no-prune", false, "Keep old backups regardless of the retention policy")
+
+	cmd.AddCommand(newBackupListCmd())
+	cmd.AddCommand(newBackupRestoreCmd())
+	cmd.AddCommand(newBackupPruneCmd())
+	return cmd
+}
+
+func newBackupListCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list [project]",
+		Short: "List backups, newest first",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := config.Get().Paths.Backups
+			list, err := backup.List(root)
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				var filtered []*backup.Manifest
+				for _, m := range list {
+					if m.Name == backup.Name(args[0]) {
+						filtered = append(filtered, m)
+					}
+				}
+				list = filtered
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(list)
+			}
+			if len(list) == 0 {
+				fmt.Println(ui.Muted("No backups in " + root))
+				return nil
+			}
+
+			ui.PrintHeader(fmt.Sprintf("Backups (%d)", len(list)))
+			for _, m := range list {
+				fmt.Printf("  %-40s %s  %8s  %s\n", m.Archive, m.Created.Local().Format("2006-01-02 15:04"),
+					clean.FormatSize(m.Size), ui.Muted(fmt.Sprintf("%d files from %s", len(m.Files), m.Source)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+	return cmd
+}
+
+func newBackupRestoreCmd() *cobra.Command {
+	var to string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Verify a backup and extract it",
+		Long: `Verify the archive and file checksums of a backup against its manifest, then
+extract it to the directory it was taken from, or to --to. The archive is
+given by path or by name, as shown by bdev backup list. Nothing is written
+when verification fails.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := backup.Find(config.Get().Paths.Backups, args[0])
+			if err != nil {
+				return err
+			}
+			dest := m.Source
+			if to != "" {
+				dest = to
+			}
+
+			if entries, err := os.ReadDir(dest); err == nil && len(entries) > 0 && !force {
+				return fmt.Errorf("%s is not empty (restore elsewhere with --to, or overwrite its files with --force)", dest)
+			}
+			if err := backup.Restore(m, dest, force); err != nil {
+				return err
+			}
+			fmt.Printf("%s Verified and restored %d files from %s to %s\n",
+				ui.Success(ui.ActiveGlyphs.Check), len(m.Files), ui.Bold(m.Archive), dest)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Directory to restore into (default: the original project directory)")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Restore into a non-empty directory, replacing archived files")
+	return cmd
+}
+
+func newBackupPruneCmd() *cobra.Command {
+	var dryRun bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove backups outside the retention policy",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.Get()
+			list, err := backup.List(cfg.Paths.Backups)
+			if err != nil {
+				return err
+			}
+			policy := retention(cfg)
+			expired := policy.Expired(list)
+			if len(expired) == 0 {
+				fmt.Println(ui.Muted(fmt.Sprintf("Nothing to prune (keeping %d daily and %d weekly)", policy.Daily, policy.Weekly)))
+				return nil
+			}
+
+			var total int64
+			for _, m := range expired {
+				fmt.Printf("  %-40s %s  %s\n", m.Archive, m.Created.Local().Format("2006-01-02 15:04"), ui.Muted(clean.FormatSize(m.Size)))
+				total += m.Size
+			}
+			fmt.Println()
+
+			if dryRun {
+				fmt.Printf("%s %d backup(s), %s would be removed\n", ui.Info("Dry run:"), len(expired), ui.Bold(clean.FormatSize(total)))
+				return nil
+			}
+			if !yes && !confirm(fmt.Sprintf("Delete %d backup(s) (%s)?", len(expired), clean.FormatSize(total))) {
+				fmt.Println(ui.Muted("Aborted"))
+				return nil
+			}
+			if err := removeBackups(expired); err != nil {
+				return err
+			}
+			fmt.Printf("%s Freed %s\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(clean.FormatSize(total)))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "List what would be removed without deleting")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	return cmd
+}
+
+// backupDir is the folder holding the backups of a project
+func backupDir(name string) string {
+	return filepath.Join(config.Get().Paths.Backups, name)
+}
+
+// retention returns the configured retention policy
+func retention(cfg *config.Config) backup.Policy {
+	return backup.Policy{Daily: cfg.Backup.KeepDaily, Weekly: cfg.Backup.KeepWeekly}
+}
+
+func removeBackups(list []*backup.Manifest) error {
+	for _, m := range list {
+		if err := backup.Remove(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
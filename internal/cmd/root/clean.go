@@ -0,0 +1,141 @@
+package root
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/clean"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// newCleanCmd creates the `bdev clean` command
+func newCleanCmd() *cobra.Command {
+	var all bool
+	var dryRun bool
+	var olderThan string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "clean [project]",
+		Short: "Remove build artifacts and dependency caches",
+		Long: `Remove build outputs and dependency directories such as node_modules, target/,
+.next, __pycache__, vendor and build/, including those of monorepo workspace members.
+Without arguments the current project is cleaned.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var opts clean.Options
+			if olderThan != "" {
+				age, err := clean.ParseAge(olderThan)
+				if err != nil {
+					return err
+				}
+				opts.OlderThan = age
+			}
+
+			list, err := cleanTargets(args, all)
+			if err != nil {
+				return err
+			}
+
+			results := clean.Find(list, opts)
+			if len(results) == 0 {
+				fmt.Println(ui.Muted("Nothing to clean"))
+				return nil
+			}
+
+			ui.PrintHeader("Clean")
+			var total int64
+			var dirs int
+			for _, r := range results {
+				if r.Recent {
+					fmt.Printf("  %s %s %s\n", ui.Muted(ui.ActiveGlyphs.Warning), ui.Muted(r.Project.Name),
+						ui.Muted(fmt.Sprintf("(modified within %s, skipped)", olderThan)))
+					continue
+				}
+				fmt.Printf("  %s %s\n", ui.Bold(r.Project.Name), ui.Muted(clean.FormatSize(r.Size())))
+				for _, a := range r.Artifacts {
+					fmt.Printf("    %-30s %s\n", a.Rel, ui.Muted(clean.FormatSize(a.Size)))
+					dirs++
+				}
+				total += r.Size()
+			}
+			fmt.Println()
+
+			if dirs == 0 {
+				fmt.Println(ui.Muted("Nothing to clean"))
+				return nil
+			}
+			if dryRun {
+				fmt.Printf("%s %s in %d directories would be freed\n", ui.Info("Dry run:"), ui.Bold(clean.FormatSize(total)), dirs)
+				return nil
+			}
+
+			if !yes && !confirm(fmt.Sprintf("Delete %d directories (%s)?", dirs, clean.FormatSize(total))) {
+				fmt.Println(ui.Muted("Aborted"))
+				return nil
+			}
+
+			freed, err := clean.Remove(results)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s Freed %s\n", ui.Success(ui.ActiveGlyphs.Check), ui.Bold(clean.FormatSize(freed)))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Clean every project in the projects directory")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "List what would be removed without deleting")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Skip projects with artifacts modified more recently (e.g. 30d, 2w)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	return cmd
+}
+
+// cleanTargets resolves the projects to clean from the arguments
+func cleanTargets(args []string, all bool) ([]projects.Project, error) {
+	switch {
+	case all:
+		if len(args) > 0 {
+			return nil, fmt.Errorf("--all cannot be combined with a project name")
+		}
+		return projects.ScanAll()
+
+	case len(args) == 1:
+		project, err := projects.Find(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return []projects.Project{*project}, nil
+
+	default:
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		project := projects.Analyze(cwd)
+		if project == nil {
+			return nil, fmt.Errorf("not a recognized project in %s (use --all or a project name)", cwd)
+		}
+		return []projects.Project{*project}, nil
+	}
+}
+
+// stdin is shared by every prompt, so piped answers buffered by one prompt
+// are still read by the next
+var stdin = bufio.NewReader(os.Stdin)
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s %s ", question, ui.Muted("[y/N]"))
+	answer, err := stdin.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,51 @@
+package root
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/runner"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// newDepsCmd creates the `bdev deps` command group
+func newDepsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deps",
+		Short: "Check dependencies for updates and vulnerabilities (current project)",
+		Long:  "Check dependencies of the current project. Use 'bdev multi deps' for every project.",
+	}
+
+	cmd.AddCommand(depsRunCmd("outdated", "List dependencies with newer versions", (*runner.Runner).Outdated))
+	cmd.AddCommand(depsRunCmd("audit", "List dependencies with known vulnerabilities", (*runner.Runner).Audit))
+	return cmd
+}
+
+func depsRunCmd(use, short string, run func(*runner.Runner) (*runner.DepsReport, error)) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := runner.NewFromCwd()
+			if err != nil {
+				return err
+			}
+
+			if !asJSON {
+				ui.PrintHeader(fmt.Sprintf("Dependencies: %s", r.Project.Name))
+			}
+			report, err := run(r)
+			if err != nil {
+				report = &runner.DepsReport{Project: r.Project.Name, Error: err.Error()}
+			}
+			return runner.WriteDepsReports(os.Stdout, []*runner.DepsReport{report}, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
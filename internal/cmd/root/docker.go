@@ -0,0 +1,189 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/docker"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// newDockerCmd creates the `bdev docker` command group
+func newDockerCmd() *cobra.Command {
+	var projectName string
+
+	cmd := &cobra.Command{
+		Use:   "docker",
+		Short: "Manage the Compose services of a project",
+		Long: `Run docker compose in the current project, or the one given with --project,
+using its compose.yaml or docker-compose.yml.`,
+	}
+	cmd.PersistentFlags().StringVarP(&projectName, "project", "p", "", "Project name instead of the current directory")
+
+	compose := func() (*docker.Compose, error) {
+		if err := docker.Available(); err != nil {
+			return nil, err
+		}
+		project, err := dockerProject(projectName)
+		if err != nil {
+			return nil, err
+		}
+		return docker.New(project)
+	}
+
+	cmd.AddCommand(dockerUpCmd(compose))
+	cmd.AddCommand(dockerDownCmd(compose))
+	cmd.AddCommand(dockerLogsCmd(compose))
+	cmd.AddCommand(dockerPsCmd(compose))
+	return cmd
+}
+
+func dockerUpCmd(compose func() (*docker.Compose, error)) *cobra.Command {
+	var attach bool
+	var build bool
+
+	cmd := &cobra.Command{
+		Use:   "up [service...]",
+		Short: "Start the services in the background",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := compose()
+			if err != nil {
+				return err
+			}
+			if err := c.Up(!attach, build, args...); err != nil {
+				return err
+			}
+			if attach {
+				return nil
+			}
+
+			fmt.Println()
+			statuses, err := c.PS()
+			if err != nil {
+				return err
+			}
+			printServices(statuses)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&attach, "attach", "a", false, "Stay attached and print the logs")
+	cmd.Flags().BoolVarP(&build, "build", "b", false, "Build images before starting")
+	return cmd
+}
+
+func dockerDownCmd(compose func() (*docker.Compose, error)) *cobra.Command {
+	var volumes bool
+
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Stop and remove the containers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := compose()
+			if err != nil {
+				return err
+			}
+			return c.Down(volumes)
+		},
+	}
+	cmd.Flags().BoolVarP(&volumes, "volumes", "v", false, "Also remove named volumes")
+	return cmd
+}
+
+func dockerLogsCmd(compose func() (*docker.Compose, error)) *cobra.Command {
+	var follow bool
+	var tail int
+
+	cmd := &cobra.Command{
+		Use:   "logs [service...]",
+		Short: "Print the service logs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := compose()
+			if err != nil {
+				return err
+			}
+			return c.Logs(follow, tail, args...)
+		},
+	}
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output")
+	cmd.Flags().IntVarP(&tail, "tail", "n", 100, "Lines to show per service (0 for all)")
+	return cmd
+}
+
+func dockerPsCmd(compose func() (*docker.Compose, error)) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "ps",
+		Short: "Show the status of the services",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := compose()
+			if err != nil {
+				return err
+			}
+			statuses, err := c.PS()
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			}
+
+			ui.PrintHeader("Services of " + filepath.Base(c.Dir))
+			printServices(statuses)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+	return cmd
+}
+
+// dockerProject resolves the project by name, or the current directory
+func dockerProject(name string) (*projects.Project, error) {
+	if name == "" {
+		name = "."
+	}
+	return projects.Find(name)
+}
+
+// printServices prints one line per service with its state and ports
+func printServices(statuses []docker.ServiceStatus) {
+	if len(statuses) == 0 {
+		fmt.Println(ui.Muted("No services defined"))
+		return
+	}
+
+	for _, s := range statuses {
+		glyph := ui.Muted(ui.ActiveGlyphs.Cross)
+		state := ui.Muted(s.State)
+		switch {
+		case s.Running() && (s.Health == "" || s.Health == "healthy"):
+			glyph = ui.Success(ui.ActiveGlyphs.Check)
+			state = ui.Success(s.Status)
+		case s.Running():
+			glyph = ui.Warning(ui.ActiveGlyphs.Warning)
+			state = ui.Warning(s.Status)
+		case s.Status != "":
+			state = ui.Muted(s.Status)
+		}
+
+		fmt.Printf("  %s %-20s %s\n", glyph, s.Service, state)
+		details := s.Image
+		if len(s.Ports) > 0 {
+			details += "  " + strings.Join(s.Ports, ", ")
+		}
+		if details != "" {
+			fmt.Printf("    %s\n", ui.Muted(details))
+		}
+	}
+}
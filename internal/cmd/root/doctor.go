@@ -0,0 +1,90 @@
+package root
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/doctor"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// newDoctorCmd creates the `bdev doctor` command
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor [project]",
+		Short: "Check toolchain versions and bdev prerequisites",
+		Long: `Compare the versions pinned by a project (.nvmrc, .node-version, .tool-versions,
+go.mod, package.json engines, rust-toolchain.toml, .python-version) with the
+tools installed on PATH, and check git, the Ollama endpoint and the editor.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.Get()
+
+			var project *projects.Project
+			if len(args) == 1 {
+				found, err := projects.Find(args[0])
+				if err != nil {
+					return err
+				}
+				project = found
+			} else if cwd, err := os.Getwd(); err == nil {
+				// Outside a project only the prerequisites are checked
+				project = projects.Analyze(cwd)
+			}
+
+			ui.PrintHeader("Doctor")
+			failed := printChecks("Prerequisites", doctor.CheckPrerequisites(cfg))
+
+			if project != nil {
+				fmt.Println()
+				checks := doctor.CheckProject(project)
+				if len(checks) == 0 {
+					fmt.Println(ui.Bold(project.Name))
+					fmt.Println(ui.Muted(ui.SeparatorLight))
+					fmt.Println(ui.Muted("  No version pins found"))
+				} else {
+					failed += printChecks(project.Name, checks)
+				}
+			}
+
+			fmt.Println()
+			if failed > 0 {
+				return fmt.Errorf("%d problem(s) found", failed)
+			}
+			fmt.Printf("%s %s\n", ui.Success(ui.ActiveGlyphs.Check), "No problems found")
+			return nil
+		},
+	}
+}
+
+// printChecks prints a section of checks and returns the number of failures
+func printChecks(title string, checks []doctor.Check) int {
+	fmt.Println(ui.Bold(title))
+	fmt.Println(ui.Muted(ui.SeparatorLight))
+
+	failed := 0
+	for _, c := range checks {
+		glyph := ui.Success(ui.ActiveGlyphs.Check)
+		switch c.Status {
+		case doctor.StatusWarn:
+			glyph = ui.Warning(ui.ActiveGlyphs.Warning)
+		case doctor.StatusFail:
+			glyph = ui.Error(ui.ActiveGlyphs.Cross)
+			failed++
+		}
+
+		line := fmt.Sprintf("  %s %-8s %s", glyph, c.Name, c.Message)
+		if c.Source != "" && c.Status == doctor.StatusOK {
+			line += ui.Muted(fmt.Sprintf(" (%s: %s)", c.Source, c.Want))
+		}
+		fmt.Println(line)
+		if c.Hint != "" {
+			fmt.Printf("    %s %s\n", ui.Muted(ui.ActiveGlyphs.Pointer), ui.Muted(c.Hint))
+		}
+	}
+	return failed
+}
@@ -0,0 +1,74 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/runner"
+	"github.com/badie/bdev/internal/core/vault"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// envFlags are the environment options shared by the runner shortcuts
+type envFlags struct {
+	files       []string
+	withSecrets []string
+	allSecrets  bool
+}
+
+func (f *envFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.files, "env-file", nil, "Load variables from a .env file (repeatable)")
+	cmd.Flags().StringSliceVar(&f.withSecrets, "with-secrets", nil, "Inject vault secrets as env vars (KEY1,KEY2)")
+	cmd.Flags().BoolVar(&f.allSecrets, "all-secrets", false, "Inject every vault secret as an env var")
+	cmd.MarkFlagsMutuallyExclusive("with-secrets", "all-secrets")
+}
+
+// apply fills the runner environment from env files and the vault.
+// Secrets are only passed to the child process, never written to disk.
+func (f *envFlags) apply(r *runner.Runner) error {
+	for _, path := range f.files {
+		if err := r.LoadEnvFile(path); err != nil {
+			return fmt.Errorf("failed to load env file: %w", err)
+		}
+	}
+
+	settings := config.Get().SettingsFor(r.Project.Name)
+	spec := runner.SecretSpec{
+		Keys:    f.withSecrets,
+		All:     f.allSecrets,
+		Mapped:  settings.InjectSecrets,
+		Mapping: settings.Secrets,
+	}
+	if spec.Empty() {
+		return nil
+	}
+
+	vlt := vault.New(config.Get().VaultFile())
+	if !vlt.Exists() {
+		return fmt.Errorf("vault not initialized. Run: bdev secrets init")
+	}
+
+	// Prompts go to stderr so `bdev test --report json` output stays clean
+	fmt.Fprint(os.Stderr, ui.Bold("Vault password required: "))
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+	if err := vlt.Unlock(string(password)); err != nil {
+		return fmt.Errorf("failed to unlock vault: %w", err)
+	}
+	defer vlt.Lock()
+
+	names, err := r.InjectSecrets(vlt, spec)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, ui.Success(fmt.Sprintf("Injected %d secret(s)", len(names)))+ui.Muted(" "+strings.Join(names, ", ")))
+	return nil
+}
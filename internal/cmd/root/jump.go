@@ -0,0 +1,114 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// maxPickerChoices is how many matches the interactive picker offers
+const maxPickerChoices = 9
+
+// newJumpCmd creates the `bdev jump` command
+func newJumpCmd() *cobra.Command {
+	var first bool
+
+	cmd := &cobra.Command{
+		Use:     "jump [query...]",
+		Aliases: []string{"j"},
+		Short:   "Print the path of the best matching project",
+		Long: `Fuzzy-find a project by name, ranked by how often and how recently it was used,
+and print its path. Without a query the most used project is printed.
+
+When stdout is a terminal and several projects match, a picker is shown.
+Use it from the shell with a wrapper such as:
+
+  j() { cd "$(bdev jump "$@")"; }`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := strings.Join(args, " ")
+			match, err := jump(query, first || !term.IsTerminal(int(os.Stdout.Fd())))
+			if err != nil {
+				return err
+			}
+			fmt.Println(match.Path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&first, "first", "1", false, "Print the best match without asking")
+	return cmd
+}
+
+// jump ranks the projects against query and records the chosen one. Unless
+// best is set, the user picks among several matches.
+func jump(query string, best bool) (*projects.Project, error) {
+	matches, err := rankProjects(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		if query == "" {
+			return nil, fmt.Errorf("no project used yet, try: bdev jump <query>")
+		}
+		return nil, fmt.Errorf("no project matches %q", query)
+	}
+
+	choice := 0
+	if !best && len(matches) > 1 {
+		if choice, err = pick(matches); err != nil {
+			return nil, err
+		}
+	}
+
+	p := matches[choice].Project
+	projects.RecordUse(config.Get().UsageFile(), p.Path)
+	return &p, nil
+}
+
+// rankProjects ranks every project and workspace member against query
+func rankProjects(query string) ([]projects.Match, error) {
+	cfg := config.Get()
+	list, err := projects.ScanAll()
+	if err != nil {
+		return nil, err
+	}
+	// Without usage, matches are ranked by name alone
+	usage, _ := projects.LoadUsage(cfg.UsageFile())
+	return projects.Rank(projects.Flatten(list), query, usage), nil
+}
+
+// pick asks which match to use. It writes to stderr so stdout only carries
+// the chosen path.
+func pick(matches []projects.Match) (int, error) {
+	if len(matches) > maxPickerChoices {
+		matches = matches[:maxPickerChoices]
+	}
+
+	for i, m := range matches {
+		fmt.Fprintf(os.Stderr, "  %s %s %s\n", ui.Primary(strconv.Itoa(i+1)), ui.Bold(m.Project.Name), ui.Muted(m.Project.Path))
+	}
+	fmt.Fprintf(os.Stderr, "%s [1-%d, default 1]: ", ui.Primary(ui.ActiveGlyphs.Pointer), len(matches))
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return 0, fmt.Errorf("no project selected")
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 || n > len(matches) {
+		return 0, fmt.Errorf("invalid choice: %s", line)
+	}
+	return n - 1, nil
+}
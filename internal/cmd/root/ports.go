@@ -0,0 +1,268 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/badie/bdev/internal/core/ports"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/internal/core/runner"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// newPortsCmd creates the `bdev ports` command group
+func newPortsCmd() *cobra.Command {
+	var devOnly bool
+	var asJSON bool
+
+	list := func(cmd *cobra.Command, args []string) error {
+		listeners, err := listPorts()
+		if err != nil {
+			return err
+		}
+		if devOnly {
+			var filtered []ports.Listener
+			for _, l := range listeners {
+				if ports.IsDevPort(l.Port) || l.Project != "" {
+					filtered = append(filtered, l)
+				}
+			}
+			listeners = filtered
+		}
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(listeners)
+		}
+
+		ui.PrintHeader("Listening Ports")
+		if len(listeners) == 0 {
+			fmt.Println(ui.Muted("No listening ports"))
+			return nil
+		}
+		printListeners(listeners)
+		return nil
+	}
+
+	cmd := &cobra.Command{
+		Use:   "ports",
+		Short: "List, find and free ports used by dev servers",
+		RunE:  list,
+	}
+	cmd.PersistentFlags().BoolVarP(&devOnly, "dev", "d", false, "Only show common dev ports and bdev projects")
+	cmd.PersistentFlags().BoolVar(&asJSON, "json", false, "Print as JSON")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List listening ports and the processes and projects behind them",
+		RunE:  list,
+	})
+	cmd.AddCommand(portsWhoCmd())
+	cmd.AddCommand(portsKillCmd())
+	cmd.AddCommand(portsFreeCmd())
+	return cmd
+}
+
+func portsWhoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "who <port>",
+		Short: "Show which process uses a port",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			port, err := parsePort(args[0])
+			if err != nil {
+				return err
+			}
+
+			listeners, err := portOwners(port)
+			if err != nil {
+				return err
+			}
+			if len(listeners) == 0 {
+				fmt.Printf("%s Port %d is free\n", ui.Success(ui.ActiveGlyphs.Check), port)
+				return nil
+			}
+
+			ui.PrintHeader(fmt.Sprintf("Port %d", port))
+			for _, l := range listeners {
+				fmt.Printf("  %-10s %s\n", ui.Primary("Address:"), l.Addr)
+				fmt.Printf("  %-10s %s\n", ui.Primary("Process:"), processLabel(l))
+				if l.Cwd != "" {
+					fmt.Printf("  %-10s %s\n", ui.Primary("Cwd:"), l.Cwd)
+				}
+				if l.Project != "" {
+					fmt.Printf("  %-10s %s\n", ui.Primary("Project:"), ui.Bold(l.Project))
+				}
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+}
+
+func portsKillCmd() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "kill <port>",
+		Short: "Stop the process listening on a port",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			port, err := parsePort(args[0])
+			if err != nil {
+				return err
+			}
+
+			listeners, err := portOwners(port)
+			if err != nil {
+				return err
+			}
+			if len(listeners) == 0 {
+				fmt.Printf("%s Port %d is already free\n", ui.Success(ui.ActiveGlyphs.Check), port)
+				return nil
+			}
+
+			for _, l := range listeners {
+				if !yes && !confirm(fmt.Sprintf("Stop %s on port %d?", processLabel(l), port)) {
+					fmt.Println(ui.Muted("Aborted"))
+					continue
+				}
+				if err := ports.Kill(l.PID); err != nil {
+					return fmt.Errorf("failed to stop %s: %w", processLabel(l), err)
+				}
+				fmt.Printf("%s Stopped %s\n", ui.Success(ui.ActiveGlyphs.Check), processLabel(l))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	return cmd
+}
+
+func portsFreeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "free [port]",
+		Short: "Print the first free port at or after port (default 3000)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := 3000
+			if len(args) == 1 {
+				port, err := parsePort(args[0])
+				if err != nil {
+					return err
+				}
+				start = port
+			}
+
+			free := ports.NextFree(start)
+			if free == 0 {
+				return fmt.Errorf("no free port found after %d", start)
+			}
+			fmt.Println(free)
+			return nil
+		},
+	}
+}
+
+// checkStartPort warns when the dev server port is taken and offers the next free one
+func checkStartPort(r *runner.Runner) {
+	conflict := r.CheckPort()
+	if conflict == nil {
+		return
+	}
+
+	msg := fmt.Sprintf("Port %d is already in use", conflict.Port)
+	if len(conflict.Holders) > 0 {
+		msg += " by " + processLabel(conflict.Holders[0])
+		if p := conflict.Holders[0].Project; p != "" {
+			msg += fmt.Sprintf(" (%s)", p)
+		}
+	}
+	fmt.Printf("%s %s\n", ui.Warning(ui.ActiveGlyphs.Warning), ui.Warning(msg))
+
+	// Only offered when the dev server can be told the port
+	if conflict.Free == 0 || !conflict.Movable || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return
+	}
+	if confirm(fmt.Sprintf("Start on port %d instead?", conflict.Free)) {
+		r.UsePort(conflict.Free)
+		fmt.Printf("%s port %d\n", ui.Muted("Using"), conflict.Free)
+	}
+	fmt.Println()
+}
+
+// listPorts lists listening ports attributed to bdev projects
+func listPorts() ([]ports.Listener, error) {
+	listeners, err := ports.List()
+	if err != nil {
+		return nil, err
+	}
+	if list, err := projects.ScanAll(); err == nil {
+		ports.Attribute(listeners, projects.Flatten(list))
+	}
+	return listeners, nil
+}
+
+// portOwners returns the listeners on a port, attributed to projects
+func portOwners(port int) ([]ports.Listener, error) {
+	listeners, err := ports.Who(port)
+	if err != nil {
+		return nil, err
+	}
+	if list, err := projects.ScanAll(); err == nil {
+		ports.Attribute(listeners, projects.Flatten(list))
+	}
+	return listeners, nil
+}
+
+func printListeners(listeners []ports.Listener) {
+	fmt.Printf("  %s\n", ui.Muted(fmt.Sprintf("%-7s %-24s %-8s %-18s %s", "PORT", "ADDRESS", "PID", "PROCESS", "PROJECT")))
+	fmt.Println(ui.Muted("  " + strings.Repeat("-", 72)))
+
+	for _, l := range listeners {
+		pid := "-"
+		if l.PID > 0 {
+			pid = strconv.Itoa(l.PID)
+		}
+		process := l.Process
+		if process == "" {
+			process = "-"
+		}
+
+		line := strings.TrimRight(fmt.Sprintf("%-7d %-24s %-8s %-18s %s", l.Port, l.Addr, pid, process, l.Project), " ")
+		switch {
+		case l.Project != "":
+			line = ui.Primary(line)
+		case ports.IsDevPort(l.Port):
+			line = ui.Warning(line)
+		}
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+}
+
+func processLabel(l ports.Listener) string {
+	switch {
+	case l.PID == 0:
+		return "an unknown process"
+	case l.Process == "":
+		return fmt.Sprintf("PID %d", l.PID)
+	}
+	return fmt.Sprintf("%s (PID %d)", l.Process, l.PID)
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port: %s", s)
+	}
+	return port, nil
+}
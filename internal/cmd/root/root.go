@@ -148,33 +148,40 @@ func registerShortcuts() {
 	})
 
 	// bdev start - start dev server in current project
-	rootCmd.AddCommand(&cobra.Command{
-		Use:   "start",
+	var startWatch bool
+	var startEnv envFlags
+	startCmd := &cobra.Command{
+		Use:   "start [service...]",
 		Short: "Start development server (current project)",
+		Long:  "Start the development server, or every service from a Procfile / .bdev.yml",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			r, err := runner.NewFromCwd()
 			if err != nil {
 				return err
 			}
+			if err := startEnv.apply(r); err != nil {
+				return err
+			}
+			if len(args) > 0 || (!startWatch && r.HasServices()) {
+				return r.StartServices(args...)
+			}
+			checkStartPort(r)
+			if startWatch {
+				return r.StartWatch()
+			}
 			return r.Start()
 		},
-	})
+	}
+	startCmd.Flags().BoolVarP(&startWatch, "watch", "w", false, "Restart on source changes")
+	startEnv.register(startCmd)
+	rootCmd.AddCommand(startCmd)
 
 	// bdev test - run tests in current project
-	rootCmd.AddCommand(&cobra.Command{
-		Use:   "test",
-		Short: "Run tests (current project)",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			r, err := runner.NewFromCwd()
-			if err != nil {
-				return err
-			}
-			return r.Test(false)
-		},
-	})
+	rootCmd.AddCommand(newTestCmd())
 
 	// bdev build - build current project
-	rootCmd.AddCommand(&cobra.Command{
+	var buildEnv envFlags
+	buildCmd := &cobra.Command{
 		Use:   "build",
 		Short: "Build for production (current project)",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -182,9 +189,14 @@ func registerShortcuts() {
 			if err != nil {
 				return err
 			}
+			if err := buildEnv.apply(r); err != nil {
+				return err
+			}
 			return r.Build()
 		},
-	})
+	}
+	buildEnv.register(buildCmd)
+	rootCmd.AddCommand(buildCmd)
 
 	// bdev install - install dependencies
 	rootCmd.AddCommand(&cobra.Command{
@@ -215,6 +227,75 @@ func registerShortcuts() {
 	lintCmd.Flags().BoolVar(&fix, "fix", false, "Auto-fix issues")
 	rootCmd.AddCommand(lintCmd)
 
+	// bdev fmt - run formatter
+	var check bool
+	fmtCmd := &cobra.Command{
+		Use:     "fmt",
+		Aliases: []string{"format"},
+		Short:   "Format source files (current project)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := runner.NewFromCwd()
+			if err != nil {
+				return err
+			}
+			return r.Format(check)
+		},
+	}
+	fmtCmd.Flags().BoolVar(&check, "check", false, "Only check formatting, do not write files")
+	rootCmd.AddCommand(fmtCmd)
+
+	// bdev fix - format then lint --fix
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "fix",
+		Short: "Format and auto-fix lint issues (current project)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := runner.NewFromCwd()
+			if err != nil {
+				return err
+			}
+			return r.Fix()
+		},
+	})
+
+	// bdev run - run a .bdev.yml task or package script
+	runCmd := &cobra.Command{
+		Use:   "run [task] [args...]",
+		Short: "Run a task from .bdev.yml or a package script (current project)",
+		Long:  "Run a task from .bdev.yml / bdev.toml, or a package script of the same name. Without a task, list what is available.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := runner.NewFromCwd()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				return printTasks(r)
+			}
+			return r.RunTask(args[0], args[1:])
+		},
+	}
+	// Flags after the task name belong to the task
+	runCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(runCmd)
+
+	// bdev clean - remove build artifacts and dependency caches
+	rootCmd.AddCommand(newCleanCmd())
+	rootCmd.AddCommand(newBackupCmd())
+
+	// bdev deps - outdated and vulnerable dependencies
+	rootCmd.AddCommand(newDepsCmd())
+
+	// bdev doctor - check toolchains and prerequisites
+	rootCmd.AddCommand(newDoctorCmd())
+
+	// bdev ports - list and free dev server ports
+	rootCmd.AddCommand(newPortsCmd())
+
+	// bdev docker - manage Compose services
+	rootCmd.AddCommand(newDockerCmd())
+
+	// bdev jump - frecency-ranked fuzzy project finder
+	rootCmd.AddCommand(newJumpCmd())
+
 	// bdev demo - show visual components
 	rootCmd.AddCommand(&cobra.Command{
 		Use:     "demo",
@@ -226,6 +307,30 @@ func registerShortcuts() {
 	})
 }
 
+// printTasks lists the tasks and scripts of the current project
+func printTasks(r *runner.Runner) error {
+	names := r.TaskList()
+	if len(names) == 0 {
+		return fmt.Errorf("no tasks or scripts defined for %s (add tasks: in .bdev.yml)", r.Project.Name)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Tasks in %s", r.Project.Name))
+	tasks := r.Project.Overrides().Tasks
+	for _, name := range names {
+		if task, ok := tasks[name]; ok {
+			desc := task.Description
+			if desc == "" {
+				desc = task.Command
+			}
+			fmt.Printf("  %-20s %s\n", ui.Primary(name), ui.Muted(desc))
+			continue
+		}
+		fmt.Printf("  %-20s %s\n", ui.Primary(name), ui.Muted(r.Project.Scripts[name]))
+	}
+	fmt.Println()
+	return nil
+}
+
 func initConfig() {
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
@@ -0,0 +1,183 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/badie/bdev/internal/core/runner"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// newTestCmd creates the `bdev test` shortcut and its subcommands
+func newTestCmd() *cobra.Command {
+	var watch bool
+	var reportFormat string
+	var rerunFailed bool
+	var onlyChanged bool
+	var env envFlags
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run tests (current project)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := runner.NewFromCwd()
+			if err != nil {
+				return err
+			}
+			if err := env.apply(r); err != nil {
+				return err
+			}
+
+			selective := rerunFailed || onlyChanged
+			if watch || reportFormat == "none" || !r.SupportsTestReport() {
+				if reportFormat == "json" || selective {
+					return fmt.Errorf("structured test runs are not supported for %s projects", r.Project.Type)
+				}
+				return r.Test(watch)
+			}
+
+			history, err := runner.LoadTestHistory(runner.TestHistoryDir(r.Project.Path))
+			if err != nil {
+				return fmt.Errorf("failed to read test history: %v", err)
+			}
+
+			var sel *runner.TestSelection
+			switch {
+			case rerunFailed:
+				failed := history.LastFailed()
+				if len(failed) == 0 {
+					fmt.Println(ui.Muted("No failed tests in the last recorded run"))
+					return nil
+				}
+				sel = &runner.TestSelection{Tests: failed}
+
+			case onlyChanged:
+				changed, err := r.ChangedFiles()
+				if err != nil {
+					return err
+				}
+				targets, all := runner.TestTargets(r.Project, changed)
+				if !all {
+					if len(targets) == 0 {
+						fmt.Println(ui.Muted("No tests affected by the current changes"))
+						return nil
+					}
+					sel = &runner.TestSelection{Targets: targets}
+				}
+			}
+
+			if reportFormat == "json" {
+				// Keep stdout for the report, tool output goes to stderr
+				r.Stdout = os.Stderr
+			}
+
+			report, err := r.TestWithReport(sel)
+			if err != nil {
+				return err
+			}
+
+			if sel != nil {
+				history.RecordPartial(report)
+			} else {
+				history.Record(report)
+			}
+			if err := history.Save(); err != nil {
+				fmt.Fprintln(os.Stderr, ui.Warning("Could not save test history: "+err.Error()))
+			}
+
+			switch reportFormat {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			default:
+				report.Print(os.Stdout)
+			}
+
+			if report.Failed > 0 {
+				return fmt.Errorf("%d test(s) failed", report.Failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Re-run tests on source changes")
+	cmd.Flags().StringVar(&reportFormat, "report", "table", "Result format: table, json, none")
+	cmd.Flags().BoolVar(&rerunFailed, "rerun-failed", false, "Only run the tests that failed last time")
+	cmd.Flags().BoolVar(&onlyChanged, "only-changed", false, "Only run tests covering files changed in git")
+	cmd.MarkFlagsMutuallyExclusive("rerun-failed", "only-changed")
+	env.register(cmd)
+
+	cmd.AddCommand(testStatsCmd())
+	return cmd
+}
+
+func testStatsCmd() *cobra.Command {
+	var runs int
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show slow and flaky tests from recorded runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := runner.NewFromCwd()
+			if err != nil {
+				return err
+			}
+
+			history, err := runner.LoadTestHistory(runner.TestHistoryDir(r.Project.Path))
+			if err != nil {
+				return fmt.Errorf("failed to read test history: %v", err)
+			}
+			full := len(history.FullRuns())
+			if full == 0 {
+				fmt.Println(ui.Muted("No recorded full test runs yet. Run 'bdev test' first."))
+				return nil
+			}
+
+			window := full
+			if runs > 0 && runs < window {
+				window = runs
+			}
+
+			ui.PrintHeader(fmt.Sprintf("Test Stats: %s", r.Project.Name))
+			fmt.Printf("  %s %d of %d recorded full runs\n", ui.Primary("Window:"), window, full)
+
+			fmt.Println()
+			fmt.Println(ui.Bold("Slowest (average)"))
+			fmt.Println(ui.Muted(ui.SeparatorLight))
+			for _, st := range history.Slowest(window, limit) {
+				fmt.Printf("  %8s  %s %s\n",
+					st.AvgDuration.Round(time.Millisecond), st.Name,
+					ui.Muted(fmt.Sprintf("(max %s)", st.MaxDuration.Round(time.Millisecond))))
+			}
+
+			fmt.Println()
+			fmt.Println(ui.Bold("Flaky"))
+			fmt.Println(ui.Muted(ui.SeparatorLight))
+			flaky := history.Flaky(window)
+			if len(flaky) == 0 {
+				fmt.Println(ui.Muted("  No tests flipped between pass and fail"))
+				return nil
+			}
+			if len(flaky) > limit {
+				flaky = flaky[:limit]
+			}
+			for _, st := range flaky {
+				fmt.Printf("  %s %s %s\n", ui.Warning(ui.ActiveGlyphs.Warning), st.Name,
+					ui.Muted(fmt.Sprintf("%d flips, %d/%d failed", st.Flips, st.Failures, st.Runs)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&runs, "runs", "n", 10, "Number of recent runs to analyze")
+	cmd.Flags().IntVarP(&limit, "limit", "l", 10, "Maximum tests to list per section")
+	return cmd
+}
@@ -0,0 +1,356 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/badie/bdev/internal/core/ignore"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// manifestVersion is bumped when the manifest layout changes
+const manifestVersion = 1
+
+const (
+	archiveExt  = ".tar.gz"
+	manifestExt = ".json"
+	timeLayout  = "20060102-150405"
+)
+
+// File is an archived file with its checksum
+type File struct {
+	Path   string      `json:"path"` // slash-separated, relative to the project
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+	SHA256 string      `json:"sha256,omitempty"`
+	Link   string      `json:"link,omitempty"` // target of a symbolic link
+}
+
+// Manifest describes a backup. It is written next to the archive, with the
+// same name and a .json extension.
+type Manifest struct {
+	Version int       `json:"version"`
+	Name    string    `json:"name"`   // backup name of the project
+	Source  string    `json:"source"` // project directory that was archived
+	Created time.Time `json:"created"`
+	Archive string    `json:"archive"` // archive file name
+	Size    int64     `json:"size"`    // archive size
+	SHA256  string    `json:"sha256"`  // archive checksum
+	Files   []File    `json:"files"`
+
+	dir string
+}
+
+// ArchivePath returns the path of the backup archive
+func (m *Manifest) ArchivePath() string {
+	return filepath.Join(m.dir, m.Archive)
+}
+
+// ManifestPath returns the path of the manifest file
+func (m *Manifest) ManifestPath() string {
+	return strings.TrimSuffix(m.ArchivePath(), archiveExt) + manifestExt
+}
+
+// SourceSize returns the size of the archived files before compression
+func (m *Manifest) SourceSize() int64 {
+	var total int64
+	for _, f := range m.Files {
+		total += f.Size
+	}
+	return total
+}
+
+// Options configures Create
+type Options struct {
+	SkipGit bool      // leave out the .git directory, which is archived by default
+	Now     time.Time // creation time used in the archive name, defaults to now
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Name turns a project label such as clients/acme into a backup name
+func Name(label string) string {
+	return strings.Trim(unsafeName.ReplaceAllString(label, "-"), "-.")
+}
+
+// Create archives dir into destDir as <name>-<time>.tar.gz and writes its
+// manifest. Files ignored by the project .gitignore and dependency
+// directories such as node_modules are left out.
+func Create(dir, destDir, name string, opts Options) (*Manifest, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if destDir, err = filepath.Abs(destDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		Version: manifestVersion,
+		Name:    name,
+		Source:  dir,
+		Created: opts.Now,
+		dir:     destDir,
+	}
+
+	tmp, err := os.CreateTemp(destDir, ".backup-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	archiveHash := sha256.New()
+	if err := writeArchive(io.MultiWriter(tmp, archiveHash), dir, destDir, m, opts); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	m.Size = info.Size()
+	m.SHA256 = hex.EncodeToString(archiveHash.Sum(nil))
+
+	// Backups of the same second get a numbered suffix
+	base := name + "-" + opts.Now.Format(timeLayout)
+	m.Archive = base + archiveExt
+	for i := 2; exists(m.ArchivePath()) || exists(m.ManifestPath()); i++ {
+		m.Archive = fmt.Sprintf("%s-%d%s", base, i, archiveExt)
+	}
+
+	if err := os.Rename(tmp.Name(), m.ArchivePath()); err != nil {
+		return nil, err
+	}
+	if err := m.save(); err != nil {
+		os.Remove(m.ArchivePath())
+		return nil, err
+	}
+	return m, nil
+}
+
+// writeArchive writes the gzipped tar of dir to w, recording every file in
+// m. The destination folder is skipped when it is inside dir.
+func writeArchive(w io.Writer, dir, destDir string, m *Manifest, opts Options) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	matcher := ignore.Load(dir)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if path == destDir {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if skipped(rel, d, matcher, opts) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return addEntry(tw, path, rel, info, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// skipped reports whether a path is left out of the archive. The .git
+// directory is ignored by the default patterns, but it is kept unless
+// SkipGit is set: it holds history that may not be pushed anywhere.
+func skipped(rel string, d fs.DirEntry, matcher *ignore.Matcher, opts Options) bool {
+	if rel == ".git" || strings.HasPrefix(rel, ".git/") {
+		return opts.SkipGit
+	}
+	if d.IsDir() && projects.IsDependencyDir(d.Name()) {
+		return true
+	}
+	return matcher.Match(rel, d.IsDir())
+}
+
+// addEntry writes a directory, regular file or symbolic link to the archive.
+// Other file types, such as sockets, are skipped.
+func addEntry(tw *tar.Writer, path, rel string, info fs.FileInfo, m *Manifest) error {
+	var link string
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		link = target
+	case !info.IsDir() && !info.Mode().IsRegular():
+		return nil
+	}
+
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = rel
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	// Owner names are meaningless on the machine restoring the backup
+	hdr.Uname, hdr.Gname = "", ""
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	switch {
+	case info.IsDir():
+		return nil
+	case link != "":
+		m.Files = append(m.Files, File{Path: rel, Mode: info.Mode(), Link: link})
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	// The header size is fixed, a file growing while archived is cut
+	if _, err := io.CopyN(tw, io.TeeReader(f, h), hdr.Size); err != nil {
+		return fmt.Errorf("%s: %v", rel, err)
+	}
+	m.Files = append(m.Files, File{Path: rel, Size: hdr.Size, Mode: info.Mode(), SHA256: sum(h)})
+	return nil
+}
+
+// save writes the manifest atomically
+func (m *Manifest) save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := m.ManifestPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.ManifestPath())
+}
+
+// Load reads the manifest of a backup, given the archive or manifest path
+func Load(path string) (*Manifest, error) {
+	path = strings.TrimSuffix(path, archiveExt)
+	path = strings.TrimSuffix(path, manifestExt) + manifestExt
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no backup manifest at %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest %s: %v", path, err)
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported backup manifest version %d in %s", m.Version, path)
+	}
+	m.dir = filepath.Dir(path)
+	return &m, nil
+}
+
+// List returns the backups under root, one folder per project, newest
+// first. Files that are not valid manifests are skipped.
+func List(root string) ([]*Manifest, error) {
+	paths, err := filepath.Glob(filepath.Join(root, "*", "*"+manifestExt))
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Manifest
+	for _, path := range paths {
+		if m, err := Load(path); err == nil {
+			list = append(list, m)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Created.After(list[j].Created) })
+	return list, nil
+}
+
+// Find returns the backup named by an archive path, or by an archive name
+// with or without extension among the backups under root
+func Find(root, name string) (*Manifest, error) {
+	if exists(name) {
+		return Load(name)
+	}
+
+	base := strings.TrimSuffix(strings.TrimSuffix(name, archiveExt), manifestExt)
+	list, err := List(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range list {
+		if strings.TrimSuffix(m.Archive, archiveExt) == base {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("backup not found: %s", name)
+}
+
+// Remove deletes the archive and manifest of a backup
+func Remove(m *Manifest) error {
+	if err := os.Remove(m.ArchivePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(m.ManifestPath())
+}
+
+func sum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,341 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func filePaths(m *Manifest) []string {
+	var paths []string
+	for _, f := range m.Files {
+		paths = append(paths, f.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// newProject creates a project with ignored files, dependencies and git history
+func newProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, dir, ".gitignore", "*.log\ndist/\n")
+	writeFile(t, dir, "main.go", "package main\n")
+	writeFile(t, dir, "src/app/handler.go", "package app\n")
+	writeFile(t, dir, "app.log", "noise")
+	writeFile(t, dir, "dist/bundle.js", "built")
+	writeFile(t, dir, "node_modules/react/index.js", "dep")
+	writeFile(t, dir, ".venv/bin/python", "dep")
+	writeFile(t, dir, ".git/HEAD", "ref: refs/heads/main\n")
+	return dir
+}
+
+// ==============================================================
+// Create Tests
+// ==============================================================
+
+func TestCreate(t *testing.T) {
+	dir := newProject(t)
+	dest := filepath.Join(t.TempDir(), "web")
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.Local)
+
+	m, err := Create(dir, dest, "web", Options{Now: now})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	want := []string{".git/HEAD", ".gitignore", "main.go", "src/app/handler.go"}
+	if got := filePaths(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+	if m.Archive != "web-20261018-153000.tar.gz" || !m.Created.Equal(now) {
+		t.Errorf("Archive = %q, Created = %v", m.Archive, m.Created)
+	}
+	if m.SourceSize() != int64(len("*.log\ndist/\n")+len("package main\n")+len("package app\n")+len("ref: refs/heads/main\n")) {
+		t.Errorf("SourceSize() = %d", m.SourceSize())
+	}
+
+	loaded, err := Load(m.ArchivePath())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.SHA256 != m.SHA256 || len(loaded.Files) != len(m.Files) || loaded.ArchivePath() != m.ArchivePath() {
+		t.Errorf("Load() = %+v, want the created manifest", loaded)
+	}
+
+	// A second backup in the same second gets a suffix
+	again, err := Create(dir, dest, "web", Options{Now: now, SkipGit: true})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if again.Archive != "web-20261018-153000-2.tar.gz" {
+		t.Errorf("Archive = %q, want a numbered suffix", again.Archive)
+	}
+	if got := filePaths(again); contains(got, ".git/HEAD") {
+		t.Errorf("files = %v, want no .git with SkipGit", got)
+	}
+}
+
+func TestCreate_DestinationInsideProject(t *testing.T) {
+	dir := newProject(t)
+	m, err := Create(dir, filepath.Join(dir, "backups"), "web", Options{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, path := range filePaths(m) {
+		if strings.HasPrefix(path, "backups/") {
+			t.Errorf("the backup folder was archived: %s", path)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := map[string]string{
+		"web":          "web",
+		"clients/acme": "clients-acme",
+		"my app (old)": "my-app-old",
+		"../x":         "x",
+	}
+	for label, want := range tests {
+		if got := Name(label); got != want {
+			t.Errorf("Name(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
+
+// ==============================================================
+// List and Find Tests
+// ==============================================================
+
+func TestListAndFind(t *testing.T) {
+	root := t.TempDir()
+	dir := newProject(t)
+	old, _ := Create(dir, filepath.Join(root, "web"), "web", Options{Now: time.Now().Add(-time.Hour)})
+	recent, _ := Create(dir, filepath.Join(root, "api"), "api", Options{})
+	writeFile(t, root, "web/notes.json", "{not a manifest")
+
+	list, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Archive != recent.Archive || list[1].Archive != old.Archive {
+		t.Errorf("List() = %v, want the two backups newest first", list)
+	}
+	if missing, err := List(filepath.Join(root, "missing")); err != nil || len(missing) != 0 {
+		t.Errorf("List(missing) = %v, %v", missing, err)
+	}
+
+	for _, name := range []string{old.Archive, strings.TrimSuffix(old.Archive, ".tar.gz"), old.ArchivePath(), old.ManifestPath()} {
+		if m, err := Find(root, name); err != nil || m.Archive != old.Archive {
+			t.Errorf("Find(%q) = %v, %v", name, m, err)
+		}
+	}
+	if _, err := Find(root, "web-19990101-000000"); err == nil {
+		t.Error("Find(unknown) error = nil")
+	}
+
+	if err := Remove(old); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := List(root); len(list) != 1 {
+		t.Errorf("List() after Remove = %d backups, want 1", len(list))
+	}
+}
+
+// ==============================================================
+// Restore Tests
+// ==============================================================
+
+func TestRestore(t *testing.T) {
+	dir := newProject(t)
+	if runtime.GOOS != "windows" {
+		os.Chmod(filepath.Join(dir, "main.go"), 0o755)
+		os.Symlink("src/app", filepath.Join(dir, "app"))
+	}
+	m, err := Create(dir, t.TempDir(), "web", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "restored")
+	if err := Restore(m, dest, false); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "src", "app", "handler.go"))
+	if err != nil || string(data) != "package app\n" {
+		t.Errorf("restored handler.go = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "node_modules")); err == nil {
+		t.Error("node_modules was restored")
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(filepath.Join(dest, "main.go")); err != nil || info.Mode().Perm() != 0o755 {
+			t.Errorf("main.go mode = %v, %v, want 0755", info.Mode(), err)
+		}
+		if link, err := os.Readlink(filepath.Join(dest, "app")); err != nil || link != "src/app" {
+			t.Errorf("app link = %q, %v", link, err)
+		}
+	}
+
+	// A non-empty destination needs overwrite
+	writeFile(t, dest, "main.go", "changed")
+	if err := Restore(m, dest, false); err == nil {
+		t.Error("Restore() into a non-empty directory error = nil")
+	}
+	if err := Restore(m, dest, true); err != nil {
+		t.Fatalf("Restore(overwrite) error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "main.go")); string(data) != "package main\n" {
+		t.Errorf("main.go = %q, want the archived content", data)
+	}
+}
+
+func TestVerify_Corruption(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, m *Manifest)
+	}{
+		{"archive_bytes", func(t *testing.T, m *Manifest) {
+			data, _ := os.ReadFile(m.ArchivePath())
+			data[len(data)/2] ^= 0xff
+			os.WriteFile(m.ArchivePath(), data, 0o644)
+		}},
+		{"file_checksum", func(t *testing.T, m *Manifest) {
+			m.Files[0].SHA256 = strings.Repeat("0", 64)
+		}},
+		{"missing_file", func(t *testing.T, m *Manifest) {
+			m.Files = append(m.Files, File{Path: "deleted.go", SHA256: strings.Repeat("0", 64)})
+		}},
+		{"extra_file", func(t *testing.T, m *Manifest) {
+			m.Files = m.Files[1:]
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Create(newProject(t), t.TempDir(), "web", Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(m); err != nil {
+				t.Fatalf("Verify() before tampering error = %v", err)
+			}
+			tt.tamper(t, m)
+
+			if err := Verify(m); err == nil {
+				t.Error("Verify() error = nil")
+			}
+			dest := filepath.Join(t.TempDir(), "restored")
+			if err := Restore(m, dest, false); err == nil {
+				t.Error("Restore() error = nil")
+			}
+			if _, err := os.Stat(dest); err == nil {
+				t.Error("Restore() wrote files despite the failed verification")
+			}
+		})
+	}
+}
+
+func TestRestore_UnsafePath(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil-20261018-000000.tar.gz")
+
+	f, _ := os.Create(archive)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0o644, Size: 4, Typeflag: tar.TypeReg})
+	tw.Write([]byte("evil"))
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	data, _ := os.ReadFile(archive)
+	h := sha256.Sum256(data)
+	m := &Manifest{
+		Version: manifestVersion, Name: "evil", Archive: filepath.Base(archive), SHA256: hex.EncodeToString(h[:]),
+		Files: []File{{Path: "../evil", Size: 4}},
+		dir:   dir,
+	}
+
+	dest := filepath.Join(dir, "restored")
+	if err := Restore(m, dest, false); err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Errorf("Restore() error = %v, want an unsafe path error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil")); err == nil {
+		t.Error("a file was written outside the destination")
+	}
+}
+
+// ==============================================================
+// Retention Tests
+// ==============================================================
+
+func TestPolicy_Expired(t *testing.T) {
+	at := func(name, day string, hour int) *Manifest {
+		d, _ := time.ParseInLocation("2006-01-02", day, time.Local)
+		created := d.Add(time.Duration(hour) * time.Hour)
+		return &Manifest{Name: name, Archive: name + "-" + created.Format(timeLayout) + archiveExt, Created: created}
+	}
+	list := []*Manifest{
+		at("web", "2026-10-18", 12), // latest, day 1, week 42
+		at("web", "2026-10-18", 9),  // same day
+		at("web", "2026-10-17", 12), // day 2
+		at("web", "2026-10-16", 12), // day 3
+		at("web", "2026-10-12", 12), // days full, week 42 kept
+		at("web", "2026-10-11", 12), // week 41
+		at("web", "2026-10-05", 12), // week 41 kept
+		at("web", "2026-09-20", 12), // week 38
+		at("web", "2026-09-01", 12), // weeks full
+		at("api", "2026-09-01", 12), // only backup of api
+	}
+
+	var got []string
+	for _, m := range (Policy{Daily: 3, Weekly: 3}).Expired(list) {
+		got = append(got, m.Archive)
+	}
+	want := []string{
+		"web-20261018-090000.tar.gz",
+		"web-20261012-120000.tar.gz",
+		"web-20261005-120000.tar.gz",
+		"web-20260901-120000.tar.gz",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expired() = %v, want %v", got, want)
+	}
+
+	if expired := (Policy{}).Expired(list); len(expired) != 0 {
+		t.Errorf("zero Policy expired %d backups, want none", len(expired))
+	}
+	if expired := (Policy{Weekly: 1}).Expired(list); len(expired) != 8 {
+		t.Errorf("Policy{Weekly: 1} expired %d backups, want 8", len(expired))
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Verify checks the archive checksum, then that the archive holds exactly
+// the files of the manifest with their checksums
+func Verify(m *Manifest) error {
+	f, err := os.Open(m.ArchivePath())
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if got := sum(h); got != m.SHA256 {
+		return fmt.Errorf("%s is corrupted: checksum %s, manifest has %s", m.Archive, got, m.SHA256)
+	}
+
+	want := make(map[string]File, len(m.Files))
+	for _, f := range m.Files {
+		want[f.Path] = f
+	}
+	err = walkArchive(m, func(hdr *tar.Header, name string, r io.Reader) error {
+		if hdr.Typeflag == tar.TypeDir {
+			return nil
+		}
+		f, ok := want[name]
+		if !ok {
+			return fmt.Errorf("%s is not in the manifest", name)
+		}
+		delete(want, name)
+
+		if hdr.Typeflag == tar.TypeSymlink {
+			if hdr.Linkname != f.Link {
+				return fmt.Errorf("%s links to %s, manifest has %s", name, hdr.Linkname, f.Link)
+			}
+			return nil
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, r); err != nil {
+			return err
+		}
+		if got := sum(h); got != f.SHA256 {
+			return fmt.Errorf("%s is corrupted: checksum %s, manifest has %s", name, got, f.SHA256)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for name := range want {
+		return fmt.Errorf("%s is missing from %s", name, m.Archive)
+	}
+	return nil
+}
+
+// Restore verifies a backup, then extracts it into dest. An existing dest
+// must be empty unless overwrite is set, in which case archived files
+// replace the ones in dest and other files are left alone.
+func Restore(m *Manifest, dest string, overwrite bool) error {
+	if entries, err := os.ReadDir(dest); err == nil && len(entries) > 0 && !overwrite {
+		return fmt.Errorf("%s is not empty", dest)
+	}
+	if err := Verify(m); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+
+	// Links are created last, so no file is written through one
+	var links []*tar.Header
+	err := walkArchive(m, func(hdr *tar.Header, name string, r io.Reader) error {
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			return os.MkdirAll(target, hdr.FileInfo().Mode().Perm()|0o700)
+		case tar.TypeSymlink:
+			links = append(links, hdr)
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		// A link in dest would redirect the write outside of it
+		if info, err := os.Lstat(target); err == nil && !info.Mode().IsRegular() {
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, hdr.FileInfo().Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, hdr := range links {
+		target := filepath.Join(dest, filepath.FromSlash(path.Clean(hdr.Name)))
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+		if err := os.Symlink(hdr.Linkname, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkArchive calls fn for the directories, regular files and links of the
+// archive, with their cleaned names. Names escaping the archive root fail.
+func walkArchive(m *Manifest, fn func(hdr *tar.Header, name string, r io.Reader) error) error {
+	f, err := os.Open(m.ArchivePath())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", m.Archive, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", m.Archive, err)
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == "." || name == ".." || strings.HasPrefix(name, "../") || strings.Contains(hdr.Name, `\`) {
+			return fmt.Errorf("%s: unsafe path %q", m.Archive, hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir, tar.TypeReg, tar.TypeSymlink:
+			if err := fn(hdr, name, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
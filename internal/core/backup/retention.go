@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Policy decides which backups of a project are kept: the latest backup of
+// each of the last Daily days and of the last Weekly weeks having backups.
+// The latest backup is always kept, and a zero policy keeps everything.
+type Policy struct {
+	Daily  int
+	Weekly int
+}
+
+// Expired returns the backups the policy does not keep, per project name
+func (p Policy) Expired(list []*Manifest) []*Manifest {
+	if p.Daily <= 0 && p.Weekly <= 0 {
+		return nil
+	}
+
+	byName := make(map[string][]*Manifest)
+	for _, m := range list {
+		byName[m.Name] = append(byName[m.Name], m)
+	}
+
+	var expired []*Manifest
+	for _, backups := range byName {
+		sort.SliceStable(backups, func(i, j int) bool { return backups[i].Created.After(backups[j].Created) })
+
+		days := make(map[string]bool)
+		weeks := make(map[string]bool)
+		for i, m := range backups {
+			created := m.Created.Local()
+			day := created.Format("2006-01-02")
+			year, week := created.ISOWeek()
+			weekKey := fmt.Sprintf("%d-W%02d", year, week)
+
+			keep := i == 0
+			if !days[day] && len(days) < p.Daily {
+				days[day] = true
+				keep = true
+			}
+			if !weeks[weekKey] && len(weeks) < p.Weekly {
+				weeks[weekKey] = true
+				keep = true
+			}
+			if !keep {
+				expired = append(expired, m)
+			}
+		}
+	}
+
+	sort.SliceStable(expired, func(i, j int) bool { return expired[i].Created.After(expired[j].Created) })
+	return expired
+}
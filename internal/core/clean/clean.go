@@ -0,0 +1,228 @@
+package clean
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// Artifact is an existing artifact directory of a project
+type Artifact struct {
+	Path    string // absolute path
+	Rel     string // path relative to the project
+	Size    int64
+	ModTime time.Time // newest modification inside the directory
+}
+
+// Result lists the artifacts found in a project
+type Result struct {
+	Project   projects.Project
+	Artifacts []Artifact
+	Recent    bool // an artifact was modified within Options.OlderThan, the project is skipped
+}
+
+// Size returns the total size of the project's artifacts
+func (r Result) Size() int64 {
+	var total int64
+	for _, a := range r.Artifacts {
+		total += a.Size
+	}
+	return total
+}
+
+// Options configures Find
+type Options struct {
+	OlderThan time.Duration // skip projects with an artifact modified more recently, 0 disables
+	Jobs      int           // concurrent size computations, defaults to the number of CPUs
+}
+
+// Find locates the artifacts of each project and of its workspace members, and
+// computes their sizes in parallel. Results are ordered by size, largest first;
+// projects without artifacts are omitted.
+func Find(list []projects.Project, opts Options) []Result {
+	list = projects.Flatten(list)
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	results := make([]Result, len(list))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+
+	for i := range list {
+		results[i].Project = list[i]
+		paths := artifactPaths(&list[i])
+		results[i].Artifacts = make([]Artifact, len(paths))
+
+		for j, path := range paths {
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func(a *Artifact, root, path string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				rel, _ := filepath.Rel(root, path)
+				a.Path = path
+				a.Rel = filepath.ToSlash(rel)
+				a.Size, a.ModTime = usage(path)
+			}(&results[i].Artifacts[j], list[i].Path, path)
+		}
+	}
+	wg.Wait()
+
+	cutoff := time.Now().Add(-opts.OlderThan)
+	found := make([]Result, 0, len(results))
+	for _, r := range results {
+		if len(r.Artifacts) == 0 {
+			continue
+		}
+		if opts.OlderThan > 0 {
+			for _, a := range r.Artifacts {
+				if a.ModTime.After(cutoff) {
+					r.Recent = true
+					break
+				}
+			}
+		}
+		found = append(found, r)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Size() > found[j].Size() })
+	return found
+}
+
+// Remove deletes the artifacts of every result not marked recent and returns the space freed
+func Remove(results []Result) (int64, error) {
+	var freed int64
+	for _, r := range results {
+		if r.Recent {
+			continue
+		}
+		for _, a := range r.Artifacts {
+			if err := os.RemoveAll(a.Path); err != nil {
+				return freed, fmt.Errorf("failed to remove %s: %w", a.Path, err)
+			}
+			freed += a.Size
+		}
+	}
+	return freed, nil
+}
+
+// artifactPaths returns the existing artifact directories of a project
+func artifactPaths(p *projects.Project) []string {
+	var paths []string
+	var recursive []string
+
+	for _, rule := range p.ArtifactRules() {
+		if rule.Recursive {
+			recursive = append(recursive, rule.Name)
+			continue
+		}
+		path := filepath.Join(p.Path, filepath.FromSlash(rule.Name))
+		// Symlinked directories point outside the project, leave them alone
+		if info, err := os.Lstat(path); err == nil && info.IsDir() {
+			paths = append(paths, path)
+		}
+	}
+
+	if len(recursive) == 0 {
+		return paths
+	}
+
+	skip := map[string]bool{".git": true, "node_modules": true, ".venv": true, "venv": true}
+	for _, path := range paths {
+		skip[path] = true
+	}
+	// Workspace members are searched as projects of their own
+	for _, child := range p.Children {
+		skip[child.Path] = true
+	}
+
+	_ = filepath.WalkDir(p.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == p.Path {
+			return nil
+		}
+		if skip[d.Name()] || skip[path] {
+			return filepath.SkipDir
+		}
+		for _, name := range recursive {
+			if d.Name() == name {
+				paths = append(paths, path)
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+
+	return paths
+}
+
+// usage returns the total file size below dir and its newest modification time
+func usage(dir string) (int64, time.Time) {
+	var size int64
+	var newest time.Time
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+
+	return size, newest
+}
+
+// ParseAge parses a duration such as "30d", "2w" or "12h"
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
+
+// FormatSize formats a byte count for display
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
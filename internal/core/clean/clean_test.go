@@ -0,0 +1,227 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Find Tests
+// ==============================================================
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "node_modules/react/index.js", strings.Repeat("x", 1000))
+	writeFile(t, dir, ".next/cache/page.js", strings.Repeat("x", 200))
+	writeFile(t, dir, "src/app.tsx", "export {}")
+
+	results := Find([]projects.Project{{Name: "web", Path: dir, Type: projects.TypeNextJS}}, Options{})
+	if len(results) != 1 {
+		t.Fatalf("Find() = %d results, want 1", len(results))
+	}
+
+	r := results[0]
+	if len(r.Artifacts) != 2 {
+		t.Fatalf("Artifacts = %v, want .next and node_modules", r.Artifacts)
+	}
+	if r.Size() != 1200 {
+		t.Errorf("Size() = %d, want 1200", r.Size())
+	}
+	if r.Recent {
+		t.Error("Recent should be false without OlderThan")
+	}
+}
+
+func TestFind_RecursiveRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app/__pycache__/main.cpython-312.pyc", "x")
+	writeFile(t, dir, "app/api/__pycache__/views.cpython-312.pyc", "x")
+	writeFile(t, dir, ".venv/lib/__pycache__/site.pyc", "x")
+	writeFile(t, dir, "app/main.py", "")
+
+	results := Find([]projects.Project{{Name: "api", Path: dir, Type: projects.TypePython}}, Options{})
+	if len(results) != 1 {
+		t.Fatalf("Find() = %d results, want 1", len(results))
+	}
+
+	var rels []string
+	for _, a := range results[0].Artifacts {
+		rels = append(rels, a.Rel)
+	}
+	if len(rels) != 2 || rels[0] != "app/__pycache__" || rels[1] != "app/api/__pycache__" {
+		t.Errorf("Artifacts = %v, want both app caches and nothing from .venv", rels)
+	}
+}
+
+func TestFind_WorkspaceMembers(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "scripts/__pycache__/release.pyc", "x")
+	writeFile(t, dir, "packages/ui/node_modules/react/index.js", "x")
+	writeFile(t, dir, "packages/ui/dist/index.js", "x")
+	writeFile(t, dir, "services/api/app/__pycache__/main.pyc", "x")
+
+	root := projects.Project{Name: "mono", Path: dir, Type: projects.TypePython, Children: []projects.Project{
+		{Name: "ui", Path: filepath.Join(dir, "packages", "ui"), Type: projects.TypeReact},
+		{Name: "api", Path: filepath.Join(dir, "services", "api"), Type: projects.TypePython},
+	}}
+
+	found := make(map[string][]string)
+	for _, r := range Find([]projects.Project{root}, Options{}) {
+		for _, a := range r.Artifacts {
+			found[r.Project.Name] = append(found[r.Project.Name], a.Rel)
+		}
+		sort.Strings(found[r.Project.Name])
+	}
+
+	// Member caches belong to the member only, not to the root as well
+	want := map[string][]string{
+		"mono": {"scripts/__pycache__"},
+		"ui":   {"dist", "node_modules"},
+		"api":  {"app/__pycache__"},
+	}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("artifacts = %v, want %v", found, want)
+	}
+}
+
+func TestFind_OlderThan(t *testing.T) {
+	old := t.TempDir()
+	writeFile(t, old, "target/debug/app", "binary")
+	past := time.Now().Add(-60 * 24 * time.Hour)
+	for _, p := range []string{"target/debug/app", "target/debug", "target"} {
+		if err := os.Chtimes(filepath.Join(old, p), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fresh := t.TempDir()
+	writeFile(t, fresh, "target/debug/app", "binary")
+
+	results := Find([]projects.Project{
+		{Name: "old", Path: old, Type: projects.TypeRust},
+		{Name: "fresh", Path: fresh, Type: projects.TypeRust},
+	}, Options{OlderThan: 30 * 24 * time.Hour})
+
+	recent := make(map[string]bool)
+	for _, r := range results {
+		recent[r.Project.Name] = r.Recent
+	}
+	if recent["old"] || !recent["fresh"] {
+		t.Errorf("Recent = %v, want only fresh marked", recent)
+	}
+}
+
+func TestFind_NoArtifacts(t *testing.T) {
+	results := Find([]projects.Project{{Name: "svc", Path: t.TempDir(), Type: projects.TypeGo}}, Options{})
+	if len(results) != 0 {
+		t.Errorf("Find() = %v, want no results", results)
+	}
+}
+
+// ==============================================================
+// Remove Tests
+// ==============================================================
+
+func TestRemove(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "vendor/autoload.php", "<?php")
+	writeFile(t, dir, "composer.json", "{}")
+
+	skipped := t.TempDir()
+	writeFile(t, skipped, "vendor/autoload.php", "<?php")
+
+	results := Find([]projects.Project{
+		{Name: "lib", Path: dir, Type: projects.TypePHP},
+		{Name: "active", Path: skipped, Type: projects.TypePHP},
+	}, Options{})
+	for i := range results {
+		results[i].Recent = results[i].Project.Name == "active"
+	}
+
+	freed, err := Remove(results)
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if freed != 5 {
+		t.Errorf("freed = %d, want 5", freed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "vendor")); !os.IsNotExist(err) {
+		t.Error("vendor should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "composer.json")); err != nil {
+		t.Error("composer.json should be kept")
+	}
+	if _, err := os.Stat(filepath.Join(skipped, "vendor")); err != nil {
+		t.Error("recent project should be kept")
+	}
+}
+
+// ==============================================================
+// Formatting Tests
+// ==============================================================
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseAge(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAge(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAge(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{512, "512 B"},
+		{2048, "2.0 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSize(tt.in); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// ==============================================================
+// Test Helpers
+// ==============================================================
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
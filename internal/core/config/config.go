@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -15,7 +17,23 @@ type Config struct {
 	Paths   PathsConfig       `json:"paths" mapstructure:"paths"`
 	AI      AIConfig          `json:"ai" mapstructure:"ai"`
 	Display DisplayConfig     `json:"display" mapstructure:"display"`
+	Backup  BackupConfig      `json:"backup" mapstructure:"backup"`
 	Aliases map[string]string `json:"aliases" mapstructure:"aliases"`
+
+	// Projects holds per-project overrides keyed by project name
+	Projects map[string]ProjectSettings `json:"projects,omitempty" mapstructure:"projects"`
+}
+
+// ProjectSettings contains per-project overrides
+type ProjectSettings struct {
+	PackageManager string `json:"package_manager,omitempty" mapstructure:"package_manager"`
+
+	// Secrets maps environment variable names to vault keys
+	Secrets map[string]string `json:"secrets,omitempty" mapstructure:"secrets"`
+
+	// InjectSecrets injects every Secrets entry on each run, without
+	// --with-secrets, asking for the vault password every time
+	InjectSecrets bool `json:"inject_secrets,omitempty" mapstructure:"inject_secrets"`
 }
 
 // UserConfig contains user preferences
@@ -30,6 +48,30 @@ type PathsConfig struct {
 	Projects string `json:"projects" mapstructure:"projects"`
 	Bdev     string `json:"bdev" mapstructure:"bdev"`
 	Backups  string `json:"backups" mapstructure:"backups"`
+
+	// ScanDepth is how many levels below Projects are searched for projects
+	ScanDepth int `json:"scan_depth" mapstructure:"scan_depth"`
+
+	// Ignore are globs of directories skipped when scanning Projects
+	Ignore []string `json:"ignore,omitempty" mapstructure:"ignore"`
+
+	// Roots are more directories searched for projects besides Projects
+	Roots []RootConfig `json:"roots,omitempty" mapstructure:"roots"`
+
+	// Templates are extra directories searched for `bdev projects new` templates
+	Templates []string `json:"templates,omitempty" mapstructure:"templates"`
+}
+
+// RootConfig is a directory searched for projects
+type RootConfig struct {
+	Path string `json:"path" mapstructure:"path"`
+
+	// ScanDepth defaults to paths.scan_depth
+	ScanDepth int `json:"scan_depth,omitempty" mapstructure:"scan_depth"`
+
+	// Ignore are globs matched against directory names and paths relative
+	// to the root, such as archive or clients/old-*
+	Ignore []string `json:"ignore,omitempty" mapstructure:"ignore"`
 }
 
 // AIConfig contains AI engine settings
@@ -49,6 +91,15 @@ type DisplayConfig struct {
 	DateFormat string `json:"date_format" mapstructure:"date_format"`
 }
 
+// BackupConfig contains the retention of `bdev backup` archives
+type BackupConfig struct {
+	// KeepDaily is how many days keep their latest backup
+	KeepDaily int `json:"keep_daily" mapstructure:"keep_daily"`
+
+	// KeepWeekly is how many weeks keep their latest backup
+	KeepWeekly int `json:"keep_weekly" mapstructure:"keep_weekly"`
+}
+
 // Global config instance
 var globalConfig *Config
 
@@ -66,9 +117,10 @@ func Load() *Config {
 			Shell:  "powershell",
 		},
 		Paths: PathsConfig{
-			Projects: filepath.Join(homeDir(), "Dev", "Projects"),
-			Bdev:     filepath.Join(homeDir(), "Dev", ".bdev"),
-			Backups:  filepath.Join("D:", "Backups"),
+			Projects:  filepath.Join(homeDir(), "Dev", "Projects"),
+			Bdev:      filepath.Join(homeDir(), "Dev", ".bdev"),
+			Backups:   defaultBackupsDir(),
+			ScanDepth: 2,
 		},
 		AI: AIConfig{
 			Enabled:       true,
@@ -83,6 +135,10 @@ func Load() *Config {
 			Theme:      "claude",
 			DateFormat: "relative",
 		},
+		Backup: BackupConfig{
+			KeepDaily:  7,
+			KeepWeekly: 4,
+		},
 		Aliases: map[string]string{
 			"gs": "git status",
 			"gp": "git push",
@@ -108,6 +164,14 @@ func Get() *Config {
 	return globalConfig
 }
 
+// SettingsFor returns the overrides for a project, if any
+func (c *Config) SettingsFor(name string) ProjectSettings {
+	if c.Projects == nil {
+		return ProjectSettings{}
+	}
+	return c.Projects[name]
+}
+
 // HistoryFile returns the path to command history file
 func (c *Config) HistoryFile() string {
 	return filepath.Join(c.Paths.Bdev, "history")
@@ -123,6 +187,61 @@ func (c *Config) AIMemoryFile() string {
 	return filepath.Join(c.Paths.Bdev, "ai_memory.json")
 }
 
+// ProjectIndexFile returns the path to the cached project index
+func (c *Config) ProjectIndexFile() string {
+	return filepath.Join(c.Paths.Bdev, "cache", "projects.json")
+}
+
+// ProjectRoots returns the directories searched for projects: Projects,
+// then the configured roots. A leading ~ in a root path is the home directory.
+func (c *Config) ProjectRoots() []RootConfig {
+	roots := []RootConfig{{Path: c.Paths.Projects, ScanDepth: c.Paths.ScanDepth, Ignore: c.Paths.Ignore}}
+	seen := map[string]bool{filepath.Clean(c.Paths.Projects): true}
+
+	for _, r := range c.Paths.Roots {
+		if r.Path == "~" || strings.HasPrefix(r.Path, "~/") || strings.HasPrefix(r.Path, `~\`) {
+			r.Path = filepath.Join(homeDir(), r.Path[1:])
+		}
+		if r.Path == "" || seen[filepath.Clean(r.Path)] {
+			continue
+		}
+		seen[filepath.Clean(r.Path)] = true
+		if r.ScanDepth <= 0 {
+			r.ScanDepth = c.Paths.ScanDepth
+		}
+		roots = append(roots, r)
+	}
+	return roots
+}
+
+// ProjectRegistryFile returns the path to the projects registered outside
+// the project roots
+func (c *Config) ProjectRegistryFile() string {
+	return filepath.Join(c.Paths.Bdev, "projects-registry.json")
+}
+
+// ProjectMetaFile returns the path to project tags, favorites and notes
+func (c *Config) ProjectMetaFile() string {
+	return filepath.Join(c.Paths.Bdev, "projects-meta.json")
+}
+
+// UsageFile returns the path to project usage counts used by `bdev jump`
+func (c *Config) UsageFile() string {
+	return filepath.Join(c.Paths.Bdev, "usage.json")
+}
+
+// TemplateDirs returns the template directories in search order: the
+// configured ones, then the templates folder of the bdev directory
+func (c *Config) TemplateDirs() []string {
+	dirs := append([]string{}, c.Paths.Templates...)
+	return append(dirs, filepath.Join(c.Paths.Bdev, "templates"))
+}
+
+// ProjectTypesDir returns the directory of user project type definitions
+func (c *Config) ProjectTypesDir() string {
+	return filepath.Join(c.Paths.Bdev, "project-types")
+}
+
 // VaultFile returns the path to secrets vault
 func (c *Config) VaultFile() string {
 	return filepath.Join(c.Paths.Bdev, "vault.enc")
@@ -145,6 +264,15 @@ func (c *Config) Save() error {
 	return os.WriteFile(configPath, data, 0o600)
 }
 
+// defaultBackupsDir is the backup drive on Windows, a folder next to the
+// projects elsewhere
+func defaultBackupsDir() string {
+	if runtime.GOOS == "windows" {
+		return `D:\Backups`
+	}
+	return filepath.Join(homeDir(), "Dev", "Backups")
+}
+
 // homeDir returns the user's home directory
 func homeDir() string {
 	home, err := os.UserHomeDir()
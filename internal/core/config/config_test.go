@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -163,6 +164,60 @@ func TestConfig_Save(t *testing.T) {
 	}
 }
 
+// ==============================================================
+// SettingsFor Tests
+// ==============================================================
+
+func TestConfig_SettingsFor(t *testing.T) {
+	cfg := &Config{
+		Projects: map[string]ProjectSettings{
+			"web": {PackageManager: "pnpm"},
+		},
+	}
+
+	if got := cfg.SettingsFor("web").PackageManager; got != "pnpm" {
+		t.Errorf("SettingsFor(web).PackageManager = %q, want 'pnpm'", got)
+	}
+	if got := cfg.SettingsFor("api").PackageManager; got != "" {
+		t.Errorf("SettingsFor(api).PackageManager = %q, want empty", got)
+	}
+
+	empty := &Config{}
+	if got := empty.SettingsFor("web"); got.PackageManager != "" {
+		t.Errorf("SettingsFor() on nil map = %+v, want zero value", got)
+	}
+}
+
+// ==============================================================
+// ProjectRoots Tests
+// ==============================================================
+
+func TestConfig_ProjectRoots(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	cfg := &Config{
+		Paths: PathsConfig{
+			Projects:  "/dev/projects",
+			ScanDepth: 2,
+			Ignore:    []string{"archive"},
+			Roots: []RootConfig{
+				{Path: "~/src", ScanDepth: 1},
+				{Path: "/mnt/clients", Ignore: []string{"old-*"}},
+				{Path: "/dev/projects/"},
+				{Path: ""},
+			},
+		},
+	}
+
+	want := []RootConfig{
+		{Path: "/dev/projects", ScanDepth: 2, Ignore: []string{"archive"}},
+		{Path: filepath.Join(home, "src"), ScanDepth: 1},
+		{Path: "/mnt/clients", ScanDepth: 2, Ignore: []string{"old-*"}},
+	}
+	if got := cfg.ProjectRoots(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProjectRoots() = %+v, want %+v", got, want)
+	}
+}
+
 // ==============================================================
 // EnsureDirectories Tests
 // ==============================================================
@@ -0,0 +1,246 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// Binary is the docker CLI, looked up on PATH
+const Binary = "docker"
+
+// StateNotCreated is the state of a declared service without a container
+const StateNotCreated = "not created"
+
+// Compose runs docker compose for a project
+type Compose struct {
+	Dir      string
+	File     string // Compose file name, relative to Dir
+	Services []projects.Service
+
+	Stdout io.Writer
+	Stderr io.Writer
+	Stdin  io.Reader
+}
+
+// ServiceStatus is the state of a service and its container
+type ServiceStatus struct {
+	Service   string   `json:"service"`
+	Container string   `json:"container,omitempty"`
+	Image     string   `json:"image,omitempty"`
+	State     string   `json:"state"`            // running, exited, ... or "not created"
+	Status    string   `json:"status,omitempty"` // e.g. "Up 2 minutes"
+	Health    string   `json:"health,omitempty"`
+	Ports     []string `json:"ports,omitempty"`
+}
+
+// Running reports whether the container is running
+func (s ServiceStatus) Running() bool {
+	return s.State == "running"
+}
+
+// New returns the Compose setup of a project
+func New(p *projects.Project) (*Compose, error) {
+	c := p.Containers
+	if c == nil || c.ComposeFile == "" {
+		return nil, fmt.Errorf("no compose file in %s (looked for %s)", p.Name, strings.Join(projects.ComposeFileNames, ", "))
+	}
+	return &Compose{
+		Dir:      p.Path,
+		File:     c.ComposeFile,
+		Services: c.Services,
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		Stdin:    os.Stdin,
+	}, nil
+}
+
+// Available returns an error if the docker CLI is not installed
+func Available() error {
+	if _, err := exec.LookPath(Binary); err != nil {
+		return fmt.Errorf("docker is not installed or not on PATH")
+	}
+	return nil
+}
+
+// Up creates and starts the services, all of them if none is given
+func (c *Compose) Up(detach, build bool, services ...string) error {
+	args := []string{"up"}
+	if detach {
+		args = append(args, "--detach")
+	}
+	if build {
+		args = append(args, "--build")
+	}
+	return c.run(append(args, services...)...)
+}
+
+// Down stops and removes the containers, and the volumes if asked
+func (c *Compose) Down(volumes bool) error {
+	args := []string{"down"}
+	if volumes {
+		args = append(args, "--volumes")
+	}
+	return c.run(args...)
+}
+
+// Logs prints the service logs. tail limits the lines per service, 0 for all.
+func (c *Compose) Logs(follow bool, tail int, services ...string) error {
+	args := []string{"logs"}
+	if follow {
+		args = append(args, "--follow")
+	}
+	if tail > 0 {
+		args = append(args, "--tail", strconv.Itoa(tail))
+	}
+	return c.run(append(args, services...)...)
+}
+
+// PS returns the state of every declared service, including the ones
+// without a container, followed by containers of undeclared services
+func (c *Compose) PS() ([]ServiceStatus, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := c.command("ps", "--all", "--format", "json")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("docker compose ps failed: %s", msg)
+		}
+		return nil, fmt.Errorf("docker compose ps failed: %v", err)
+	}
+
+	containers, err := parsePS(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return mergeStatus(c.Services, containers), nil
+}
+
+// command runs docker compose in Dir. The file is only passed when Compose
+// would not find it by itself, since -f turns off the override file merge.
+func (c *Compose) command(args ...string) *exec.Cmd {
+	base := []string{"compose"}
+	if !slices.Contains(projects.ComposeFileNames, c.File) {
+		base = append(base, "-f", c.File)
+	}
+	cmd := exec.Command(Binary, append(base, args...)...)
+	cmd.Dir = c.Dir
+	return cmd
+}
+
+func (c *Compose) run(args ...string) error {
+	cmd := c.command(args...)
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	cmd.Stdin = c.Stdin
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker compose %s failed: %v", args[0], err)
+	}
+	return nil
+}
+
+// psEntry is a container as printed by docker compose ps --format json
+type psEntry struct {
+	Name       string `json:"Name"`
+	Service    string `json:"Service"`
+	Image      string `json:"Image"`
+	State      string `json:"State"`
+	Status     string `json:"Status"`
+	Health     string `json:"Health"`
+	Publishers []struct {
+		URL           string `json:"URL"`
+		TargetPort    int    `json:"TargetPort"`
+		PublishedPort int    `json:"PublishedPort"`
+		Protocol      string `json:"Protocol"`
+	} `json:"Publishers"`
+}
+
+// parsePS reads docker compose ps output: a JSON array before Compose 2.21,
+// one JSON object per line since
+func parsePS(data []byte) ([]psEntry, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var entries []psEntry
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("unexpected docker compose ps output: %v", err)
+		}
+		return entries, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var e psEntry
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("unexpected docker compose ps output: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// mergeStatus lists the declared services with their containers
+func mergeStatus(services []projects.Service, containers []psEntry) []ServiceStatus {
+	byService := make(map[string][]psEntry)
+	for _, e := range containers {
+		byService[e.Service] = append(byService[e.Service], e)
+	}
+
+	var statuses []ServiceStatus
+	for _, s := range services {
+		entries := byService[s.Name]
+		delete(byService, s.Name)
+		if len(entries) == 0 {
+			statuses = append(statuses, ServiceStatus{Service: s.Name, Image: s.Image, State: StateNotCreated})
+			continue
+		}
+		for _, e := range entries {
+			statuses = append(statuses, status(e))
+		}
+	}
+
+	// Services missing from the parsed file, e.g. from an override file
+	var extra []ServiceStatus
+	for _, entries := range byService {
+		for _, e := range entries {
+			extra = append(extra, status(e))
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i].Container < extra[j].Container })
+	return append(statuses, extra...)
+}
+
+func status(e psEntry) ServiceStatus {
+	s := ServiceStatus{
+		Service:   e.Service,
+		Container: e.Name,
+		Image:     e.Image,
+		State:     e.State,
+		Status:    e.Status,
+		Health:    e.Health,
+	}
+
+	// IPv4 and IPv6 bindings of the same port are listed once
+	seen := make(map[string]bool)
+	for _, p := range e.Publishers {
+		port := projects.ServicePort{Published: p.PublishedPort, Target: p.TargetPort, Protocol: p.Protocol}.String()
+		if !seen[port] {
+			seen[port] = true
+			s.Ports = append(s.Ports, port)
+		}
+	}
+	return s
+}
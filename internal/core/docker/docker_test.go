@@ -0,0 +1,201 @@
+package docker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Compose CLI Tests
+// ==============================================================
+
+func TestCompose_Commands(t *testing.T) {
+	log := fakeDocker(t, "", 0)
+	c := testCompose(t)
+
+	steps := []struct {
+		run  func() error
+		want string
+	}{
+		{func() error { return c.Up(true, false) }, "compose up --detach"},
+		{func() error { return c.Up(false, true, "web") }, "compose up --build web"},
+		{func() error { return c.Logs(true, 50, "db") }, "compose logs --follow --tail 50 db"},
+		{func() error { return c.Logs(false, 0) }, "compose logs"},
+		{func() error { return c.Down(true) }, "compose down --volumes"},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: error = %v", step.want, err)
+		}
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(steps) {
+		t.Fatalf("docker called %d times, want %d:\n%s", len(lines), len(steps), data)
+	}
+	for i, step := range steps {
+		if lines[i] != step.want {
+			t.Errorf("call %d = %q, want %q", i, lines[i], step.want)
+		}
+	}
+	if dir := strings.TrimSpace(readFile(t, filepath.Join(filepath.Dir(log), "cwd"))); dir != c.Dir {
+		t.Errorf("ran in %q, want %q", dir, c.Dir)
+	}
+}
+
+func TestCompose_CustomFile(t *testing.T) {
+	log := fakeDocker(t, "", 0)
+	c := testCompose(t)
+	c.File = "compose.dev.yaml"
+
+	if err := c.Down(false); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(readFile(t, log)); got != "compose -f compose.dev.yaml down" {
+		t.Errorf("call = %q, want the file passed with -f", got)
+	}
+}
+
+func TestCompose_Failure(t *testing.T) {
+	fakeDocker(t, "", 1)
+	c := testCompose(t)
+
+	err := c.Up(true, false)
+	if err == nil || !strings.Contains(err.Error(), "docker compose up failed") {
+		t.Errorf("Up() error = %v", err)
+	}
+	if _, err := c.PS(); err == nil || !strings.Contains(err.Error(), "compose failed") {
+		t.Errorf("PS() error = %v, want the docker error message", err)
+	}
+}
+
+func TestCompose_PS(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{
+			name: "json_lines",
+			output: `{"Name":"app-web-1","Service":"web","Image":"app-web","State":"running","Status":"Up 2 minutes","Health":"","Publishers":[{"URL":"0.0.0.0","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"},{"URL":"::","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"}]}
+{"Name":"app-debug-1","Service":"debug","Image":"busybox","State":"exited","Status":"Exited (0) 1 hour ago","Publishers":null}`,
+		},
+		{
+			name:   "json_array",
+			output: `[{"Name":"app-web-1","Service":"web","Image":"app-web","State":"running","Status":"Up 2 minutes","Publishers":[{"URL":"0.0.0.0","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"}]},{"Name":"app-debug-1","Service":"debug","Image":"busybox","State":"exited","Status":"Exited (0) 1 hour ago"}]`,
+		},
+	}
+
+	want := []ServiceStatus{
+		{Service: "db", Image: "postgres:16", State: StateNotCreated},
+		{Service: "web", Container: "app-web-1", Image: "app-web", State: "running", Status: "Up 2 minutes", Ports: []string{"8080->80"}},
+		{Service: "debug", Container: "app-debug-1", Image: "busybox", State: "exited", Status: "Exited (0) 1 hour ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDocker(t, tt.output, 0)
+			c := testCompose(t)
+
+			got, err := c.PS()
+			if err != nil {
+				t.Fatalf("PS() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("PS() =\n%+v\nwant\n%+v", got, want)
+			}
+			if !got[1].Running() || got[2].Running() {
+				t.Error("Running() does not match the state")
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(&projects.Project{Name: "api"}); err == nil {
+		t.Error("New() expected error without a compose file")
+	}
+
+	p := &projects.Project{Path: "/p/api", Containers: &projects.Containers{ComposeFile: "docker-compose.yml"}}
+	c, err := New(p)
+	if err != nil || c.Dir != "/p/api" || c.File != "docker-compose.yml" {
+		t.Errorf("New() = %+v, %v", c, err)
+	}
+}
+
+// ==============================================================
+// Test Helpers
+// ==============================================================
+
+// fakeDocker puts a docker script on PATH that records its arguments,
+// prints output and exits with code. It returns the path of the log.
+func fakeDocker(t *testing.T, output string, code int) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker is a shell script")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+	writeFile(t, dir, "output", output)
+	script := "#!/bin/sh\n" +
+		"echo \"$*\" >> '" + log + "'\n" +
+		"pwd > '" + filepath.Join(dir, "cwd") + "'\n" +
+		"case \"$*\" in *\" ps \"*) cat '" + filepath.Join(dir, "output") + "' ;; esac\n" +
+		"[ " + strconv.Itoa(code) + " -eq 0 ] || { echo 'compose failed' >&2; exit " + strconv.Itoa(code) + "; }\n"
+	writeFile(t, dir, Binary, script)
+	if err := os.Chmod(filepath.Join(dir, Binary), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+// testCompose returns a Compose for a project with web and db services
+func testCompose(t *testing.T) *Compose {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	return &Compose{
+		Dir:  dir,
+		File: "compose.yaml",
+		Services: []projects.Service{
+			{Name: "db", Image: "postgres:16"},
+			{Name: "web", Build: "."},
+		},
+		Stdout: &out,
+		Stderr: &out,
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
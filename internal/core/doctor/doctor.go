@@ -0,0 +1,304 @@
+package doctor
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/badie/bdev/internal/ai/engine"
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// Status is the outcome of a check
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusWarn
+	StatusFail
+)
+
+// Check is a single doctor finding
+type Check struct {
+	Name    string
+	Status  Status
+	Want    string // required version, if pinned
+	Have    string // installed version
+	Source  string // file the requirement came from
+	Message string
+	Hint    string // how to fix a failure
+}
+
+// installHints are shown when a tool is missing or at the wrong version
+var installHints = map[string]string{
+	"node":   "install with nvm (https://github.com/nvm-sh/nvm) or from https://nodejs.org",
+	"npm":    "npm install -g npm@<version>",
+	"pnpm":   "corepack enable pnpm, or npm install -g pnpm",
+	"yarn":   "corepack enable yarn, or npm install -g yarn",
+	"go":     "install from https://go.dev/dl/",
+	"python": "install with pyenv (https://github.com/pyenv/pyenv) or from https://python.org",
+	"rust":   "install rustup from https://rustup.rs, then rustup toolchain install <version>",
+	"git":    "install from https://git-scm.com/downloads",
+	"ollama": "install from https://ollama.com and run 'ollama serve'",
+}
+
+// versionCommands are the commands that print a tool's version. Tools not
+// listed are queried with --version.
+var versionCommands = map[string][]string{
+	"go":     {"go", "version"},
+	"python": {"python3", "--version"},
+	"rust":   {"rustc", "--version"},
+	"java":   {"java", "-version"},
+}
+
+var (
+	lookPath   = exec.LookPath
+	runVersion = func(bin string, args ...string) (string, error) {
+		out, err := exec.Command(bin, args...).CombinedOutput()
+		return string(out), err
+	}
+)
+
+var (
+	versionPattern = regexp.MustCompile(`\d+(\.\d+)+|\d+`)
+	operatorSpace  = regexp.MustCompile(`([<>=^~]+)\s+`)
+)
+
+// InstalledVersion returns the version of a tool found on PATH
+func InstalledVersion(tool string) (string, error) {
+	cmd, ok := versionCommands[tool]
+	if !ok {
+		cmd = []string{tool, "--version"}
+	}
+
+	bin := cmd[0]
+	if _, err := lookPath(bin); err != nil {
+		// Windows and some distributions only ship "python"
+		if tool != "python" {
+			return "", fmt.Errorf("%s not found on PATH", bin)
+		}
+		bin = "python"
+		if _, err := lookPath(bin); err != nil {
+			return "", fmt.Errorf("python not found on PATH")
+		}
+	}
+
+	out, err := runVersion(bin, cmd[1:]...)
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %v", bin, strings.Join(cmd[1:], " "), err)
+	}
+	version := versionPattern.FindString(out)
+	if version == "" {
+		return "", fmt.Errorf("could not read %s version", tool)
+	}
+	return version, nil
+}
+
+// CheckProject compares the project's version pins with the installed tools
+func CheckProject(p *projects.Project) []Check {
+	pins := p.VersionPins()
+	checks := make([]Check, 0, len(pins))
+
+	for _, pin := range pins {
+		c := Check{Name: pin.Tool, Want: pin.Constraint, Source: pin.Source}
+
+		have, err := InstalledVersion(pin.Tool)
+		if err != nil {
+			c.Status = StatusFail
+			c.Message = err.Error()
+			c.Hint = installHints[pin.Tool]
+			checks = append(checks, c)
+			continue
+		}
+		c.Have = have
+
+		ok, err := Satisfies(have, pin.Constraint)
+		switch {
+		case err != nil:
+			c.Status = StatusWarn
+			c.Message = err.Error()
+		case !ok:
+			c.Status = StatusFail
+			c.Message = fmt.Sprintf("%s %s installed, %s requires %s", pin.Tool, have, pin.Source, pin.Constraint)
+			c.Hint = installHints[pin.Tool]
+		default:
+			c.Message = fmt.Sprintf("%s %s", pin.Tool, have)
+		}
+		checks = append(checks, c)
+	}
+
+	return checks
+}
+
+// CheckPrerequisites checks the tools bdev itself relies on: git, the Ollama
+// endpoint and the configured editor
+func CheckPrerequisites(cfg *config.Config) []Check {
+	var checks []Check
+
+	git := Check{Name: "git"}
+	if have, err := InstalledVersion("git"); err != nil {
+		git.Status = StatusFail
+		git.Message = err.Error()
+		git.Hint = installHints["git"]
+	} else {
+		git.Have = have
+		git.Message = "git " + have
+	}
+	checks = append(checks, git)
+
+	if cfg.AI.Enabled {
+		ollama := Check{Name: "ollama", Message: "reachable at " + cfg.AI.BaseURL}
+		client := engine.New(engine.Config{BaseURL: cfg.AI.BaseURL, Timeout: 2 * time.Second})
+		if !client.IsAvailable() {
+			// AI features are optional, everything else still works
+			ollama.Status = StatusWarn
+			ollama.Message = "not reachable at " + cfg.AI.BaseURL
+			ollama.Hint = installHints["ollama"]
+		}
+		checks = append(checks, ollama)
+	}
+
+	if fields := strings.Fields(cfg.User.Editor); len(fields) > 0 {
+		editor := Check{Name: "editor", Message: fields[0]}
+		if path, err := lookPath(fields[0]); err != nil {
+			editor.Status = StatusWarn
+			editor.Message = fmt.Sprintf("%s not found on PATH", fields[0])
+			editor.Hint = "install it or set user.editor in the bdev config"
+		} else {
+			editor.Message = path
+		}
+		checks = append(checks, editor)
+	}
+
+	return checks
+}
+
+// Satisfies reports whether version meets a constraint. Constraints are exact
+// or partial versions (20, 3.12), npm-style ranges (>=18 <21, ^1.2, ~1.2.3,
+// 16 || 18) or release channels such as stable and lts/*, which any version meets.
+func Satisfies(version, constraint string) (bool, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	for _, alt := range strings.Split(constraint, "||") {
+		ok, err := satisfiesAll(v, strings.TrimSpace(alt))
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// caretLock returns how many leading components ^want keeps fixed: up to
+// the first non-zero one, so ^1.2 is 1.x, ^0.2.3 is 0.2.x and ^0.0.3 is 0.0.3
+func caretLock(want []int) int {
+	for i, n := range want {
+		if n != 0 {
+			return i + 1
+		}
+	}
+	return len(want)
+}
+
+// satisfiesAll checks a space-separated list of comparators
+func satisfiesAll(v []int, constraint string) (bool, error) {
+	switch constraint {
+	case "", "*", "x", "latest", "stable", "beta", "nightly", "system", "node", "lts/*":
+		return true, nil
+	}
+	if strings.HasPrefix(constraint, "lts/") || strings.HasPrefix(constraint, "nightly-") {
+		return true, nil
+	}
+
+	// "1.2 - 2.3" is an inclusive range
+	if lo, hi, ok := strings.Cut(constraint, " - "); ok {
+		constraint = ">=" + strings.TrimSpace(lo) + " <=" + strings.TrimSpace(hi)
+	}
+	// Allow a space between an operator and its version, as in ">= 18"
+	constraint = operatorSpace.ReplaceAllString(constraint, "$1")
+
+	for _, comp := range strings.Fields(constraint) {
+		rest := strings.TrimLeft(comp, "<>=^~")
+		op := comp[:len(comp)-len(rest)]
+		want, err := parseVersion(rest)
+		if err != nil {
+			return false, err
+		}
+
+		ok := false
+		switch op {
+		case ">=":
+			ok = compare(v, want) >= 0
+		case ">":
+			ok = compare(v, want) > 0
+		case "<=":
+			ok = compare(v, want) <= 0
+		case "<":
+			ok = compare(v, want) < 0
+		case "", "=":
+			ok = compare(v, want) == 0
+		case "^":
+			ok = compare(v, want) >= 0 && compare(v, want[:caretLock(want)]) == 0
+		case "~":
+			ok = compare(v, want) >= 0 && compare(v, want[:min(2, len(want))]) == 0
+		default:
+			return false, fmt.Errorf("unsupported version constraint %q", comp)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseVersion splits a version into numeric parts, stopping at a wildcard
+func parseVersion(s string) ([]int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	// Drop pre-release and build suffixes
+	if i := strings.IndexAny(s, "-+"); i > 0 {
+		s = s[:i]
+	}
+
+	var parts []int
+	for _, p := range strings.Split(s, ".") {
+		if p == "x" || p == "X" || p == "*" {
+			break
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("invalid version %q", s)
+	}
+	return parts, nil
+}
+
+// compare compares v to want over the parts present in want, so 20.11.1
+// equals 20 and 1.22.3 equals 1.22
+func compare(v, want []int) int {
+	for i, w := range want {
+		have := 0
+		if i < len(v) {
+			have = v[i]
+		}
+		if have != w {
+			if have < w {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,212 @@
+package doctor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Satisfies Tests
+// ==============================================================
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"20.11.0", "20", true},
+		{"20.11.0", "20.11.0", true},
+		{"20.11.0", "18", false},
+		{"3.12.1", "3.12", true},
+		{"3.11.9", "3.12", false},
+		{"1.22.3", ">=1.22", true},
+		{"1.21.0", ">=1.22", false},
+		{"20.5.0", ">=18 <21", true},
+		{"21.0.0", ">=18 <21", false},
+		{"18.19.0", ">= 18", true},
+		{"20.0.0", "<=20", true},
+		{"20.1.0", ">20", false},
+		{"8.15.1", "^8", true},
+		{"9.0.0", "^8.1", false},
+		{"0.2.5", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.9.1", "^0.2", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"0.0.9", "^0.0", true},
+		{"0.1.0", "^0.0", false},
+		{"0.5.0", "^0", true},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"16.20.0", "14 || 16", true},
+		{"18.0.0", "14 || 16", false},
+		{"18.2.0", "18.x", true},
+		{"2.1.0", "1.5 - 2.3", true},
+		{"1.75.0", "stable", true},
+		{"20.11.0", "lts/*", true},
+		{"20.11.0", "lts/iron", true},
+		{"20.11.0", "v20.11.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			got, err := Satisfies(tt.version, tt.constraint)
+			if err != nil {
+				t.Fatalf("Satisfies() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSatisfies_Invalid(t *testing.T) {
+	if _, err := Satisfies("20.0.0", "temurin-21"); err == nil {
+		t.Error("Satisfies() should fail on a non-numeric constraint")
+	}
+}
+
+// ==============================================================
+// Check Tests
+// ==============================================================
+
+func TestInstalledVersion(t *testing.T) {
+	withTools(t, map[string]string{
+		"go":     "go version go1.22.3 linux/amd64",
+		"rustc":  "rustc 1.75.0 (82e1608df 2023-12-21)",
+		"python": "Python 3.12.1",
+		"node":   "v20.11.0",
+	})
+
+	tests := []struct {
+		tool    string
+		want    string
+		wantErr bool
+	}{
+		{"go", "1.22.3", false},
+		{"rust", "1.75.0", false},
+		{"python", "3.12.1", false}, // falls back from python3
+		{"node", "20.11.0", false},
+		{"ruby", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			got, err := InstalledVersion(tt.tool)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InstalledVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("InstalledVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckProject(t *testing.T) {
+	withTools(t, map[string]string{
+		"node":  "v18.19.0",
+		"go":    "go version go1.22.3 linux/amd64",
+		"rustc": "rustc 1.75.0",
+	})
+
+	dir := t.TempDir()
+	writeFile(t, dir, ".nvmrc", "20\n")
+	writeFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, dir, ".python-version", "3.12\n")
+
+	checks := CheckProject(&projects.Project{Path: dir})
+	status := make(map[string]Status)
+	for _, c := range checks {
+		status[c.Name] = c.Status
+	}
+
+	if status["node"] != StatusFail {
+		t.Errorf("node status = %v, want fail (18 installed, 20 pinned)", status["node"])
+	}
+	if status["go"] != StatusOK {
+		t.Errorf("go status = %v, want ok", status["go"])
+	}
+	if status["python"] != StatusFail {
+		t.Errorf("python status = %v, want fail (not installed)", status["python"])
+	}
+	for _, c := range checks {
+		if c.Status == StatusFail && c.Hint == "" {
+			t.Errorf("%s failure should include an install hint", c.Name)
+		}
+	}
+}
+
+func TestCheckPrerequisites(t *testing.T) {
+	withTools(t, map[string]string{"git": "git version 2.43.0"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models": []}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		User: config.UserConfig{Editor: "no-such-editor --wait"},
+		AI:   config.AIConfig{Enabled: true, BaseURL: server.URL},
+	}
+
+	checks := CheckPrerequisites(cfg)
+	if len(checks) != 3 {
+		t.Fatalf("CheckPrerequisites() = %d checks, want 3", len(checks))
+	}
+	if checks[0].Name != "git" || checks[0].Status != StatusOK || checks[0].Have != "2.43.0" {
+		t.Errorf("git check = %+v", checks[0])
+	}
+	if checks[1].Name != "ollama" || checks[1].Status != StatusOK {
+		t.Errorf("ollama check = %+v", checks[1])
+	}
+	if checks[2].Name != "editor" || checks[2].Status != StatusWarn || !strings.Contains(checks[2].Message, "no-such-editor") {
+		t.Errorf("editor check = %+v", checks[2])
+	}
+
+	server.Close()
+	if checks := CheckPrerequisites(cfg); checks[1].Status != StatusWarn {
+		t.Errorf("ollama check = %+v, want warning when unreachable", checks[1])
+	}
+}
+
+// ==============================================================
+// Test Helpers
+// ==============================================================
+
+// withTools fakes the tools on PATH and the version output they print
+func withTools(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	origLook, origRun := lookPath, runVersion
+	lookPath = func(name string) (string, error) {
+		if _, ok := outputs[name]; ok {
+			return "/usr/bin/" + name, nil
+		}
+		return "", fmt.Errorf("%s not found", name)
+	}
+	runVersion = func(bin string, args ...string) (string, error) {
+		return outputs[bin], nil
+	}
+	t.Cleanup(func() { lookPath, runVersion = origLook, origRun })
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Repository represents a git repository
@@ -69,6 +71,30 @@ func Open(path string) (*Repository, error) {
 	return &Repository{Path: absPath}, nil
 }
 
+// Init creates a git repository at path
+func Init(path string) (*Repository, error) {
+	r := &Repository{Path: path}
+	if _, err := r.run("init"); err != nil {
+		return nil, err
+	}
+	return Open(path)
+}
+
+// Clone clones url into dest. A depth of 0 clones the full history.
+func Clone(url, dest string, depth int) error {
+	args := []string{"clone", "--quiet"}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	args = append(args, url, dest)
+
+	cmd := exec.Command("git", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 // OpenCurrent opens the git repository in current directory
 func OpenCurrent() (*Repository, error) {
 	cwd, err := os.Getwd()
@@ -284,6 +310,15 @@ func (r *Repository) DiffStat(staged bool) (string, error) {
 	return r.run(args...)
 }
 
+// Root returns the top-level directory of the working tree
+func (r *Repository) Root() string {
+	root, err := r.run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return r.Path
+	}
+	return filepath.Clean(root)
+}
+
 // Reset resets changes
 func (r *Repository) Reset(hard bool, ref string) error {
 	args := []string{"reset"}
@@ -321,6 +356,19 @@ func (r *Repository) LastCommit() (*Commit, error) {
 	return &commits[0], nil
 }
 
+// LastCommitTime returns when the last commit was made
+func (r *Repository) LastCommitTime() (time.Time, error) {
+	out, err := r.run("log", "-1", "--format=%ct")
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, err := strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("no commits")
+	}
+	return time.Unix(sec, 0), nil
+}
+
 // ============================================================
 // Parser Functions
 // ============================================================
@@ -428,6 +476,35 @@ func ParseLog(output string) []Commit {
 	return commits
 }
 
+// ansiPattern matches the color escapes in `git diff --color=always` output
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// ParseDiffFiles returns the files touched by a diff, relative to the repository root
+func ParseDiffFiles(output string) []string {
+	files := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(output, "\n") {
+		line = ansiPattern.ReplaceAllString(line, "")
+		if !strings.HasPrefix(line, "diff --git a/") {
+			continue
+		}
+
+		// "diff --git a/old b/new" - the new path is what exists now
+		idx := strings.LastIndex(line, " b/")
+		if idx < 0 {
+			continue
+		}
+		path := line[idx+3:]
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	return files
+}
+
 // ParseBranches parses git branch output
 func ParseBranches(output string) []BranchInfo {
 	branches := make([]BranchInfo, 0)
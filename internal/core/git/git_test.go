@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // ==============================================================
@@ -262,6 +263,53 @@ hash2|shorthash2|Author Name|author@example.com|yesterday|Commit message 2`,
 	}
 }
 
+func TestParseDiffFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "plain_diff",
+			input: `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-package old
++package main
+diff --git a/pkg/util.go b/pkg/util.go
+deleted file mode 100644`,
+			want: []string{"main.go", "pkg/util.go"},
+		},
+		{
+			name:  "colored_diff",
+			input: "\x1b[1mdiff --git a/src/app.ts b/src/app.ts\x1b[m\n\x1b[1m--- a/src/app.ts\x1b[m\n",
+			want:  []string{"src/app.ts"},
+		},
+		{
+			name:  "rename",
+			input: "diff --git a/old.py b/new.py\nsimilarity index 100%\n",
+			want:  []string{"new.py"},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseDiffFiles(tt.input)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDiffFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseBranches(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -747,3 +795,70 @@ func TestRepository_Remote_Integration(t *testing.T) {
 		t.Errorf("Remote() = %q, want 'https://github.com/test/repo.git'", remote)
 	}
 }
+
+func TestInit(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := Init(dir)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if repo.Path != dir {
+		t.Errorf("repo.Path = %q, want %q", repo.Path, dir)
+	}
+	if !IsRepo(dir) {
+		t.Error("IsRepo() = false after Init()")
+	}
+}
+
+func TestClone_Integration(t *testing.T) {
+	src := createTestRepo(t)
+	createFile(t, src, "README.md", "# template\n")
+	runGit(t, src, "add", ".")
+	runGit(t, src, "commit", "-m", "first")
+
+	// Clone from a bare repository, like a template shared on disk
+	bare := filepath.Join(t.TempDir(), "template.git")
+	runGit(t, src, "clone", "--bare", src, bare)
+
+	t.Run("shallow", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "clone")
+		if err := Clone("file://"+filepath.ToSlash(bare), dest, 1); err != nil {
+			t.Fatalf("Clone() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dest, "README.md")); err != nil {
+			t.Errorf("README.md not cloned: %v", err)
+		}
+	})
+
+	t.Run("missing_repo", func(t *testing.T) {
+		err := Clone(filepath.Join(t.TempDir(), "missing.git"), filepath.Join(t.TempDir(), "clone"), 0)
+		if err == nil || !strings.Contains(err.Error(), "git clone failed") {
+			t.Errorf("Clone() error = %v, want git clone failed", err)
+		}
+	})
+}
+
+func TestRepository_LastCommitTime_Integration(t *testing.T) {
+	dir := createTestRepo(t)
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if _, err := repo.LastCommitTime(); err == nil {
+		t.Error("LastCommitTime() expected error without commits")
+	}
+
+	createFile(t, dir, "file.txt", "content")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "first")
+
+	last, err := repo.LastCommitTime()
+	if err != nil {
+		t.Fatalf("LastCommitTime() error = %v", err)
+	}
+	if age := time.Since(last); age < 0 || age > time.Minute {
+		t.Errorf("LastCommitTime() = %v, want about now", last)
+	}
+}
@@ -0,0 +1,358 @@
+package health
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/badie/bdev/internal/core/git"
+	"github.com/badie/bdev/internal/core/ignore"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// Check names, used as Finding.Check
+const (
+	CheckGit      = "git"
+	CheckActivity = "activity"
+	CheckReadme   = "readme"
+	CheckLicense  = "license"
+	CheckTests    = "tests"
+	CheckLockfile = "lockfile"
+	CheckCI       = "ci"
+)
+
+// Finding is a problem found in a project with how to fix it
+type Finding struct {
+	Check   string `json:"check"`
+	Message string `json:"message"`
+	Action  string `json:"action"`
+	Penalty int    `json:"penalty"`
+}
+
+// Report is the health of a project. Score starts at 100 and every finding
+// subtracts its penalty.
+type Report struct {
+	Project    string     `json:"project"`
+	Path       string     `json:"path"`
+	Score      int        `json:"score"`
+	LastCommit *time.Time `json:"last_commit,omitempty"`
+	Findings   []Finding  `json:"findings"`
+}
+
+// Penalties of each finding
+const (
+	penaltyNoGit       = 20
+	penaltyUncommitted = 10
+	penaltyUnpushed    = 10
+	penaltyNoUpstream  = 5
+	penaltyNoReadme    = 10
+	penaltyNoLicense   = 5
+	penaltyNoTests     = 15
+	penaltyLockfile    = 10
+	penaltyNoCI        = 10
+)
+
+// stale maps last commit ages to penalties, oldest first
+var stale = []struct {
+	age     time.Duration
+	penalty int
+}{
+	{365 * 24 * time.Hour, 20},
+	{180 * 24 * time.Hour, 10},
+	{90 * 24 * time.Hour, 5},
+}
+
+// now is swapped in tests
+var now = time.Now
+
+// Check computes the health report of a project from local signals only
+func Check(p *projects.Project) *Report {
+	r := &Report{Project: p.Name, Path: p.Path, Findings: []Finding{}}
+
+	r.checkGit(p.Path)
+	r.checkDocs(p.Path)
+	r.checkTests(p.Path)
+	r.checkLockfile(p.Path)
+	r.checkCI(p.Path)
+
+	r.Score = 100
+	for _, f := range r.Findings {
+		r.Score -= f.Penalty
+	}
+	if r.Score < 0 {
+		r.Score = 0
+	}
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		return r.Findings[i].Penalty > r.Findings[j].Penalty
+	})
+	return r
+}
+
+func (r *Report) add(check string, penalty int, message, action string) {
+	r.Findings = append(r.Findings, Finding{Check: check, Message: message, Action: action, Penalty: penalty})
+}
+
+// ============================================================
+// Git
+// ============================================================
+
+func (r *Report) checkGit(dir string) {
+	repo, err := git.Open(dir)
+	if err != nil {
+		r.add(CheckGit, penaltyNoGit, "not under version control", "git init && git add . && git commit")
+		return
+	}
+
+	status, err := repo.Status()
+	if err != nil {
+		return
+	}
+	changes := len(status.Staged) + len(status.Modified) + len(status.Untracked) + len(status.Deleted)
+	if changes > 0 {
+		r.add(CheckGit, penaltyUncommitted, fmt.Sprintf("%d uncommitted change(s)", changes), "commit or stash them")
+	}
+	if status.Ahead > 0 {
+		r.add(CheckGit, penaltyUnpushed, fmt.Sprintf("%d commit(s) not pushed", status.Ahead), "git push")
+	}
+
+	last, err := repo.LastCommitTime()
+	if err != nil {
+		// A repository without commits has nothing to push or age
+		return
+	}
+	r.LastCommit = &last
+
+	if status.Remote == "" {
+		r.add(CheckGit, penaltyNoUpstream, fmt.Sprintf("branch %s has no upstream", status.Branch), "git push -u origin "+status.Branch)
+	}
+
+	age := now().Sub(last)
+	for _, s := range stale {
+		if age >= s.age {
+			r.add(CheckActivity, s.penalty, "last commit "+FormatAge(age)+" ago", "archive the project or bring it up to date")
+			break
+		}
+	}
+}
+
+// FormatAge renders a duration in the largest whole unit (3 months, 2 days)
+func FormatAge(d time.Duration) string {
+	day := 24 * time.Hour
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{365 * day, "year"},
+		{30 * day, "month"},
+		{7 * day, "week"},
+		{day, "day"},
+		{time.Hour, "hour"},
+	}
+	for _, u := range units {
+		if n := int(d / u.size); n >= 1 {
+			if n == 1 {
+				return "1 " + u.name
+			}
+			return fmt.Sprintf("%d %ss", n, u.name)
+		}
+	}
+	return "less than an hour"
+}
+
+// ============================================================
+// Docs
+// ============================================================
+
+func (r *Report) checkDocs(dir string) {
+	if !hasFile(dir, "readme") {
+		r.add(CheckReadme, penaltyNoReadme, "no README", "add a README.md describing setup and usage")
+	}
+	if !hasFile(dir, "license", "licence", "copying") {
+		r.add(CheckLicense, penaltyNoLicense, "no LICENSE", "add a LICENSE file")
+	}
+}
+
+// hasFile reports whether dir has a file whose name, without extension and
+// ignoring case, is one of names
+func hasFile(dir string, names ...string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		base := strings.ToLower(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+		for _, n := range names {
+			if base == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ============================================================
+// Tests
+// ============================================================
+
+// testDirs are directories that hold tests by convention
+var testDirs = map[string]bool{
+	"test": true, "tests": true, "__tests__": true, "spec": true, "e2e": true,
+}
+
+// testFilePattern matches test files of the supported ecosystems
+var testFilePattern = regexp.MustCompile(`(_test\.(go|py|exs)|\.(test|spec)\.[cm]?[jt]sx?|^test_.*\.py|Test\.php|Tests?\.(java|kt|cs)|_spec\.rb)$`)
+
+// maxTestWalk bounds how many entries are visited looking for tests
+const maxTestWalk = 5000
+
+func (r *Report) checkTests(dir string) {
+	if !hasTests(dir) {
+		r.add(CheckTests, penaltyNoTests, "no tests found", "add tests and run them with bdev test")
+	}
+}
+
+// hasTests looks for a test directory or test file, skipping ignored paths.
+// Rust tests live inside sources, so .rs files are searched for #[test].
+func hasTests(root string) bool {
+	matcher := ignore.Load(root)
+	found := false
+	visited := 0
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if visited++; visited > maxTestWalk {
+			return filepath.SkipAll
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+
+		if matcher.Match(filepath.ToSlash(rel), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if testDirs[d.Name()] {
+				found = true
+				return filepath.SkipAll
+			}
+			if d.Name() == "target" || d.Name() == "vendor" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if testFilePattern.MatchString(d.Name()) || (filepath.Ext(path) == ".rs" && containsTest(path)) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+
+	return found
+}
+
+func containsTest(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), "#[test]")
+}
+
+// ============================================================
+// Lockfiles
+// ============================================================
+
+// lockPairs maps manifests to the lockfiles that pin them
+var lockPairs = []struct {
+	manifest string
+	locks    []string
+}{
+	{"package.json", []string{"pnpm-lock.yaml", "yarn.lock", "bun.lockb", "bun.lock", "package-lock.json"}},
+	{"composer.json", []string{"composer.lock"}},
+	{"Cargo.toml", []string{"Cargo.lock"}},
+	{"go.mod", []string{"go.sum"}},
+	{"Gemfile", []string{"Gemfile.lock"}},
+	{"Pipfile", []string{"Pipfile.lock"}},
+}
+
+// driftTolerance absorbs manifest and lockfile writes of the same install
+const driftTolerance = time.Minute
+
+func (r *Report) checkLockfile(dir string) {
+	for _, pair := range lockPairs {
+		manifest, err := os.Stat(filepath.Join(dir, pair.manifest))
+		if err != nil {
+			continue
+		}
+
+		var lock os.FileInfo
+		var lockName string
+		for _, name := range pair.locks {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				lock, lockName = info, name
+				break
+			}
+		}
+
+		if lock == nil {
+			if hasDependencies(filepath.Join(dir, pair.manifest)) {
+				r.add(CheckLockfile, penaltyLockfile, "no lockfile for "+pair.manifest, "install dependencies and commit the lockfile")
+			}
+			continue
+		}
+		if manifest.ModTime().Sub(lock.ModTime()) > driftTolerance {
+			r.add(CheckLockfile, penaltyLockfile, fmt.Sprintf("%s changed after %s", pair.manifest, lockName), "reinstall dependencies to update "+lockName)
+		}
+	}
+}
+
+// dependencyMarkers are the manifest keys that declare dependencies
+var dependencyMarkers = []string{`"dependencies"`, `"devDependencies"`, `"require"`, "[dependencies]", "require ", "gem ", "[packages]"}
+
+// hasDependencies reports whether a manifest declares any dependency. A
+// manifest without dependencies needs no lockfile.
+func hasDependencies(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	content := string(data)
+	for _, m := range dependencyMarkers {
+		if strings.Contains(content, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================
+// CI
+// ============================================================
+
+// ciConfigs are the files and directories of common CI services
+var ciConfigs = []string{
+	".github/workflows", ".gitlab-ci.yml", ".circleci/config.yml", ".travis.yml",
+	"azure-pipelines.yml", "bitbucket-pipelines.yml", "Jenkinsfile", ".woodpecker.yml", ".woodpecker",
+	".drone.yml",
+}
+
+func (r *Report) checkCI(dir string) {
+	for _, c := range ciConfigs {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(c))); err == nil {
+			return
+		}
+	}
+	r.add(CheckCI, penaltyNoCI, "no CI configuration", "add a workflow under .github/workflows")
+}
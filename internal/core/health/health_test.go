@@ -0,0 +1,236 @@
+package health
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// writeFile creates a file with content, creating parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+// runGit runs a git command in dir
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// checks returns the findings of a report keyed by check name
+func checks(r *Report) map[string][]Finding {
+	m := make(map[string][]Finding)
+	for _, f := range r.Findings {
+		m[f.Check] = append(m[f.Check], f)
+	}
+	return m
+}
+
+// ==============================================================
+// Report Tests
+// ==============================================================
+
+func TestCheck_HealthyProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "README.md"), "# app\n")
+	writeFile(t, filepath.Join(dir, "LICENSE"), "MIT\n")
+	writeFile(t, filepath.Join(dir, "go.mod"), "module app\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "main_test.go"), "package main\n")
+	writeFile(t, filepath.Join(dir, ".github", "workflows", "ci.yml"), "on: push\n")
+
+	// A bare repository as upstream, so nothing is unpushed
+	remote := filepath.Join(t.TempDir(), "app.git")
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "init")
+	runGit(t, dir, "init", "--bare", remote)
+	runGit(t, dir, "remote", "add", "origin", remote)
+	runGit(t, dir, "push", "-u", "origin", "main")
+
+	r := Check(&projects.Project{Name: "app", Path: dir})
+
+	if r.Score != 100 || len(r.Findings) != 0 {
+		t.Errorf("Check() = %d with %+v, want 100 and no findings", r.Score, r.Findings)
+	}
+	if r.LastCommit == nil {
+		t.Error("LastCommit = nil, want the commit time")
+	}
+}
+
+func TestCheck_NeglectedProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "package.json"), `{"name": "web", "dependencies": {"react": "^18.0.0"}}`)
+	writeFile(t, filepath.Join(dir, "index.js"), "console.log('hi')\n")
+
+	r := Check(&projects.Project{Name: "web", Path: dir})
+	found := checks(r)
+
+	for _, name := range []string{CheckGit, CheckReadme, CheckLicense, CheckTests, CheckLockfile, CheckCI} {
+		if len(found[name]) == 0 {
+			t.Errorf("missing %s finding in %+v", name, r.Findings)
+		}
+	}
+	if found[CheckLockfile][0].Message != "no lockfile for package.json" {
+		t.Errorf("lockfile finding = %q", found[CheckLockfile][0].Message)
+	}
+
+	want := 100 - penaltyNoGit - penaltyNoReadme - penaltyNoLicense - penaltyNoTests - penaltyLockfile - penaltyNoCI
+	if r.Score != want {
+		t.Errorf("Score = %d, want %d", r.Score, want)
+	}
+	for i := 1; i < len(r.Findings); i++ {
+		if r.Findings[i].Penalty > r.Findings[i-1].Penalty {
+			t.Errorf("findings not sorted by penalty: %+v", r.Findings)
+		}
+	}
+	for _, f := range r.Findings {
+		if f.Action == "" {
+			t.Errorf("finding %q has no action", f.Message)
+		}
+	}
+}
+
+func TestCheck_GitSignals(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "README.md"), "# app\n")
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "init")
+	writeFile(t, filepath.Join(dir, "README.md"), "# app, edited\n")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "todo\n")
+
+	defer func(orig func() time.Time) { now = orig }(now)
+	now = func() time.Time { return time.Now().Add(200 * 24 * time.Hour) }
+
+	r := Check(&projects.Project{Name: "app", Path: dir})
+	found := checks(r)
+
+	var messages []string
+	for _, f := range found[CheckGit] {
+		messages = append(messages, f.Message)
+	}
+	got := strings.Join(messages, "; ")
+	if !strings.Contains(got, "2 uncommitted change(s)") || !strings.Contains(got, "branch main has no upstream") {
+		t.Errorf("git findings = %q", got)
+	}
+
+	if len(found[CheckActivity]) != 1 || found[CheckActivity][0].Penalty != 10 {
+		t.Fatalf("activity findings = %+v, want one for a 6 month old commit", found[CheckActivity])
+	}
+	if msg := found[CheckActivity][0].Message; msg != "last commit 6 months ago" {
+		t.Errorf("activity message = %q", msg)
+	}
+}
+
+// ==============================================================
+// Signal Tests
+// ==============================================================
+
+func TestHasTests(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{"go_test_file", map[string]string{"pkg/a_test.go": ""}, true},
+		{"jest_spec", map[string]string{"src/app.spec.ts": ""}, true},
+		{"pytest_file", map[string]string{"pkg/test_cli.py": ""}, true},
+		{"tests_dir", map[string]string{"tests/.gitkeep": ""}, true},
+		{"rust_inline", map[string]string{"src/lib.rs": "#[cfg(test)]\nmod t {\n#[test]\nfn ok() {}\n}\n"}, true},
+		{"ignored_dependency_tests", map[string]string{"node_modules/x/x.test.js": "", "index.js": ""}, false},
+		{"no_tests", map[string]string{"main.go": "package main\n", "latest.txt": ""}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(dir, filepath.FromSlash(name)), content)
+			}
+			if got := hasTests(dir); got != tt.want {
+				t.Errorf("hasTests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLockfile(t *testing.T) {
+	t.Run("drift", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "composer.json"), `{"require": {"php": "^8.2"}}`)
+		writeFile(t, filepath.Join(dir, "composer.lock"), "{}")
+		old := time.Now().Add(-time.Hour)
+		os.Chtimes(filepath.Join(dir, "composer.lock"), old, old)
+
+		r := &Report{}
+		r.checkLockfile(dir)
+		if len(r.Findings) != 1 || r.Findings[0].Message != "composer.json changed after composer.lock" {
+			t.Errorf("findings = %+v", r.Findings)
+		}
+	})
+
+	t.Run("in_sync", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "package.json"), `{"dependencies": {"vue": "^3.0.0"}}`)
+		writeFile(t, filepath.Join(dir, "pnpm-lock.yaml"), "lockfileVersion: 9\n")
+
+		r := &Report{}
+		r.checkLockfile(dir)
+		if len(r.Findings) != 0 {
+			t.Errorf("findings = %+v, want none", r.Findings)
+		}
+	})
+
+	t.Run("no_dependencies", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "go.mod"), "module tool\n\ngo 1.22\n")
+
+		r := &Report{}
+		r.checkLockfile(dir)
+		if len(r.Findings) != 0 {
+			t.Errorf("findings = %+v, want none without dependencies", r.Findings)
+		}
+	})
+}
+
+func TestFormatAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Minute, "less than an hour"},
+		{5 * time.Hour, "5 hours"},
+		{day, "1 day"},
+		{10 * day, "1 week"},
+		{95 * day, "3 months"},
+		{800 * day, "2 years"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAge(tt.d); got != tt.want {
+			t.Errorf("FormatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
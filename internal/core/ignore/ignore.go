@@ -0,0 +1,176 @@
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultPatterns are ignored in every project regardless of .gitignore
+var DefaultPatterns = []string{".git/", "node_modules/", "__pycache__/", ".DS_Store"}
+
+// Rule represents a single .gitignore pattern
+type Rule struct {
+	Pattern  string
+	Negate   bool
+	DirOnly  bool
+	Anchored bool
+}
+
+// Matcher evaluates paths against an ordered list of gitignore rules
+type Matcher struct {
+	rules []Rule
+}
+
+// New creates a matcher from gitignore-style pattern lines
+func New(lines ...string) *Matcher {
+	m := &Matcher{}
+	m.Add(lines...)
+	return m
+}
+
+// Load creates a matcher from the defaults plus the .gitignore in root, if present
+func Load(root string) *Matcher {
+	m := New(DefaultPatterns...)
+
+	f, err := os.Open(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return m
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m.Add(scanner.Text())
+	}
+	return m
+}
+
+// Add parses and appends gitignore-style pattern lines
+func (m *Matcher) Add(lines ...string) {
+	for _, line := range lines {
+		if rule, ok := ParseRule(line); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+// ParseRule parses a single gitignore line
+func ParseRule(line string) (Rule, bool) {
+	line = strings.TrimRight(line, "\r")
+	line = strings.TrimRight(line, " ")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return Rule{}, false
+	}
+
+	var rule Rule
+	if strings.HasPrefix(line, "!") {
+		rule.Negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.DirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	if strings.HasPrefix(line, "/") {
+		rule.Anchored = true
+		line = strings.TrimPrefix(line, "/")
+	} else if strings.Contains(line, "/") && !strings.HasPrefix(line, "**/") {
+		rule.Anchored = true
+	}
+
+	if line == "" {
+		return Rule{}, false
+	}
+
+	rule.Pattern = line
+	return rule, true
+}
+
+// Match reports whether a slash-separated path relative to the root is ignored.
+// A path is also ignored when any of its parent directories is.
+func (m *Matcher) Match(rel string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+
+	rel = strings.Trim(filepath.ToSlash(rel), "/")
+	if rel == "" || rel == "." {
+		return false
+	}
+
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.matchOne(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matchOne(rel, isDir)
+}
+
+// matchOne evaluates the rules against a single path, last match wins
+func (m *Matcher) matchOne(rel string, isDir bool) bool {
+	ignored := false
+	base := path.Base(rel)
+
+	for _, r := range m.rules {
+		if r.DirOnly && !isDir {
+			continue
+		}
+
+		var matched bool
+		if r.Anchored {
+			matched = MatchGlob(r.Pattern, rel)
+		} else {
+			matched = MatchGlob(r.Pattern, base) || MatchGlob(r.Pattern, rel)
+		}
+
+		if matched {
+			ignored = !r.Negate
+		}
+	}
+	return ignored
+}
+
+// MatchGlob matches a slash-separated path against a glob pattern where
+// "**" matches any number of path segments
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
@@ -0,0 +1,151 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ==============================================================
+// MatchGlob Tests
+// ==============================================================
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "internal/core/runner.go", true},
+		{"**/*.go", "internal/core/runner.py", false},
+		{"src/**", "src/app/page.tsx", true},
+		{"src/**/*.ts", "src/index.ts", true},
+		{"src/**/*.ts", "lib/index.ts", false},
+		{"build", "build", true},
+		{"[abc].txt", "b.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.name, func(t *testing.T) {
+			if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// ParseRule Tests
+// ==============================================================
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   Rule
+		wantOK bool
+	}{
+		{"", Rule{}, false},
+		{"# comment", Rule{}, false},
+		{"*.log", Rule{Pattern: "*.log"}, true},
+		{"dist/", Rule{Pattern: "dist", DirOnly: true}, true},
+		{"/build", Rule{Pattern: "build", Anchored: true}, true},
+		{"docs/*.md", Rule{Pattern: "docs/*.md", Anchored: true}, true},
+		{"!keep.log", Rule{Pattern: "keep.log", Negate: true}, true},
+		{`\#file`, Rule{Pattern: "#file"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := ParseRule(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRule(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRule(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// Matcher Tests
+// ==============================================================
+
+func TestMatcher_Match(t *testing.T) {
+	m := New(
+		"*.log",
+		"!important.log",
+		"dist/",
+		"/coverage",
+		"docs/generated/**",
+		"node_modules/",
+	)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"app.log", false, true},
+		{"logs/debug.log", false, true},
+		{"important.log", false, false},
+		{"dist", true, true},
+		{"dist", false, false},
+		{"dist/bundle.js", false, true},
+		{"coverage", true, true},
+		{"src/coverage", true, false},
+		{"docs/generated/api.md", false, true},
+		{"docs/guide.md", false, false},
+		{"web/node_modules/react/index.js", false, true},
+		{"main.go", false, false},
+		{"", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := m.Match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Nil(t *testing.T) {
+	var m *Matcher
+	if m.Match("anything", false) {
+		t.Error("nil Matcher should not ignore anything")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("with_gitignore", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.tmp\n.env\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		m := Load(dir)
+		if !m.Match("scratch.tmp", false) {
+			t.Error("*.tmp from .gitignore should be ignored")
+		}
+		if !m.Match(".env", false) {
+			t.Error(".env from .gitignore should be ignored")
+		}
+		if !m.Match(".git/HEAD", false) {
+			t.Error(".git should be ignored by default")
+		}
+		if m.Match("main.go", false) {
+			t.Error("main.go should not be ignored")
+		}
+	})
+
+	t.Run("without_gitignore", func(t *testing.T) {
+		m := Load(t.TempDir())
+		if !m.Match("node_modules", true) {
+			t.Error("node_modules should be ignored by default")
+		}
+	})
+}
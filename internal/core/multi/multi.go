@@ -13,6 +13,9 @@ import (
 type Executor struct {
 	Projects []projects.Project
 	MaxJobs  int
+
+	// graph is set by Order to run projects after their dependencies
+	graph *projects.Graph
 }
 
 // Result represents a single execution result
@@ -29,6 +32,7 @@ type Filter struct {
 	Names    []string
 	Types    []projects.ProjectType
 	Patterns []string
+	Tags     []string // projects carrying any of the tags
 }
 
 // New creates a new executor
@@ -41,7 +45,7 @@ func New(allProjects []projects.Project) *Executor {
 
 // Filter applies a filter to the project list
 func (e *Executor) Filter(f Filter) {
-	if len(f.Names) == 0 && len(f.Types) == 0 && len(f.Patterns) == 0 {
+	if len(f.Names) == 0 && len(f.Types) == 0 && len(f.Patterns) == 0 && len(f.Tags) == 0 {
 		return
 	}
 
@@ -70,6 +74,16 @@ func (e *Executor) Filter(f Filter) {
 			}
 		}
 
+		// Check tags
+		if !match && len(f.Tags) > 0 {
+			for _, t := range f.Tags {
+				if p.HasTag(t) {
+					match = true
+					break
+				}
+			}
+		}
+
 		// If no filters matched but filters were present, skip
 		// Simplistic logic: OR within categories, AND logic is tricky.
 		// Let's implement OR logic for now: if any criteria matches, include.
@@ -84,7 +98,63 @@ func (e *Executor) Filter(f Filter) {
 
 // Execute runs a command on all selected projects
 func (e *Executor) Execute(ctx context.Context, command string, args []string) <-chan Result {
+	return e.ExecuteFunc(ctx, func(ctx context.Context, proj projects.Project) (string, error) {
+		cmd := exec.CommandContext(ctx, command, args...)
+		cmd.Dir = proj.Path
+
+		// Capture output
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	})
+}
+
+// Order makes the executor run projects in dependency order: a project
+// starts once the projects it depends on in g finished, and is skipped if
+// one of them failed. Projects without dependencies between them still run
+// in parallel. It fails if the selected projects depend on each other in a cycle.
+func (e *Executor) Order(g *projects.Graph) error {
+	e.graph = g
+	if _, _, err := e.plan(); err != nil {
+		e.graph = nil
+		return err
+	}
+	return nil
+}
+
+// plan groups the selected projects into levels that run one after the
+// other, and returns the dependencies between them, see Graph.Lift
+func (e *Executor) plan() ([][]projects.Project, []projects.Dependency, error) {
+	paths := make([]string, len(e.Projects))
+	byPath := make(map[string]projects.Project, len(e.Projects))
+	for i, p := range e.Projects {
+		paths[i] = p.Path
+		byPath[p.Path] = p
+	}
+
+	levels, err := e.graph.Levels(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	planned := make([][]projects.Project, len(levels))
+	for i, level := range levels {
+		for _, path := range level {
+			planned[i] = append(planned[i], byPath[path])
+		}
+	}
+	return planned, e.graph.Lift(paths), nil
+}
+
+// ExecuteFunc runs fn for all selected projects, at most MaxJobs at a time
+func (e *Executor) ExecuteFunc(ctx context.Context, fn func(context.Context, projects.Project) (string, error)) <-chan Result {
 	results := make(chan Result, len(e.Projects))
+	if e.graph != nil {
+		// Projects may have been removed since Order, which cannot add a cycle
+		if levels, deps, err := e.plan(); err == nil {
+			go e.executeOrdered(ctx, levels, deps, fn, results)
+			return results
+		}
+	}
 	sem := make(chan struct{}, e.MaxJobs) // Semaphore for concurrency limitation
 
 	go func() {
@@ -100,17 +170,11 @@ func (e *Executor) Execute(ctx context.Context, command string, args []string) <
 				defer func() { <-sem }() // Release
 
 				start := time.Now()
-
-				// Create command
-				cmd := exec.CommandContext(ctx, command, args...)
-				cmd.Dir = proj.Path
-
-				// Capture output
-				output, err := cmd.CombinedOutput()
+				output, err := fn(ctx, proj)
 
 				results <- Result{
 					Project:  proj,
-					Output:   string(output),
+					Output:   output,
 					Error:    err,
 					Duration: time.Since(start),
 				}
@@ -123,6 +187,65 @@ func (e *Executor) Execute(ctx context.Context, command string, args []string) <
 	return results
 }
 
+// executeOrdered runs the levels one after the other, skipping the projects
+// whose dependencies in deps failed or were skipped
+func (e *Executor) executeOrdered(ctx context.Context, levels [][]projects.Project, deps []projects.Dependency, fn func(context.Context, projects.Project) (string, error), results chan<- Result) {
+	defer close(results)
+	sem := make(chan struct{}, e.MaxJobs)
+
+	var mu sync.Mutex
+	failed := make(map[string]bool) // failed or skipped projects
+
+	for _, level := range levels {
+		var wg sync.WaitGroup
+		for _, p := range level {
+			mu.Lock()
+			dep := e.failedDependency(p, deps, failed)
+			if dep != "" {
+				failed[p.Path] = true
+			}
+			mu.Unlock()
+			if dep != "" {
+				results <- Result{Project: p, Output: "dependency " + dep + " failed", Skipped: true}
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(proj projects.Project) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				start := time.Now()
+				output, err := fn(ctx, proj)
+				if err != nil {
+					mu.Lock()
+					failed[proj.Path] = true
+					mu.Unlock()
+				}
+				results <- Result{Project: proj, Output: output, Error: err, Duration: time.Since(start)}
+			}(p)
+		}
+		wg.Wait()
+	}
+}
+
+// failedDependency returns the name of a selected project p depends on,
+// directly or through unselected ones, that failed, or ""
+func (e *Executor) failedDependency(p projects.Project, deps []projects.Dependency, failed map[string]bool) string {
+	for _, d := range deps {
+		if d.From != p.Path || !failed[d.To] {
+			continue
+		}
+		for _, dep := range e.Projects {
+			if dep.Path == d.To {
+				return dep.Name
+			}
+		}
+	}
+	return ""
+}
+
 // ExecuteShell runs a shell command string on all projects
 func (e *Executor) ExecuteShell(ctx context.Context, shellCmd string) <-chan Result {
 	// Simple wrapper, handling windows/unix shell differences could be done here if needed
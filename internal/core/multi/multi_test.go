@@ -0,0 +1,59 @@
+package multi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Ordered Execution Tests
+// ==============================================================
+
+func TestExecuteFunc_SkipsAfterFailedDependency(t *testing.T) {
+	// app depends on lib through shared, which is not selected
+	g := &projects.Graph{Edges: []projects.Dependency{
+		{From: "/p/app", To: "/p/shared", Kind: projects.DepNpmWorkspace},
+		{From: "/p/shared", To: "/p/lib", Kind: projects.DepNpmWorkspace},
+	}}
+
+	tests := []struct {
+		name        string
+		libFails    bool
+		wantSkipped bool
+	}{
+		{"dependency_failed", true, true},
+		{"dependency_passed", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New([]projects.Project{{Name: "app", Path: "/p/app"}, {Name: "lib", Path: "/p/lib"}})
+			if err := e.Order(g); err != nil {
+				t.Fatalf("Order() error = %v", err)
+			}
+
+			ran := make(map[string]bool)
+			results := make(map[string]Result)
+			for r := range e.ExecuteFunc(context.Background(), func(ctx context.Context, p projects.Project) (string, error) {
+				ran[p.Name] = true
+				if p.Name == "lib" && tt.libFails {
+					return "", errors.New("build failed")
+				}
+				return "", nil
+			}) {
+				results[r.Project.Name] = r
+			}
+
+			app := results["app"]
+			if app.Skipped != tt.wantSkipped || ran["app"] == tt.wantSkipped {
+				t.Errorf("app Skipped = %v, ran = %v, want skipped %v", app.Skipped, ran["app"], tt.wantSkipped)
+			}
+			if tt.wantSkipped && app.Output != "dependency lib failed" {
+				t.Errorf("app Output = %q, want the failed dependency", app.Output)
+			}
+		})
+	}
+}
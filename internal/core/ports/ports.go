@@ -0,0 +1,141 @@
+package ports
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// DevPorts are ports commonly used by development servers
+var DevPorts = []int{3000, 3001, 4200, 4321, 5000, 5173, 5174, 8000, 8080, 8888, 9000}
+
+// Listener is a TCP port in the LISTEN state
+type Listener struct {
+	Port    int    `json:"port"`
+	Addr    string `json:"addr"`
+	PID     int    `json:"pid,omitempty"` // 0 if the owner is not visible to this user
+	Process string `json:"process,omitempty"`
+	Cwd     string `json:"cwd,omitempty"`
+	Project string `json:"project,omitempty"` // bdev project the process runs in
+}
+
+// List returns the listening TCP ports, one entry per port and process, sorted by port
+func List() ([]Listener, error) {
+	listeners, err := list()
+	if err != nil {
+		return nil, err
+	}
+	return dedupe(listeners), nil
+}
+
+// Who returns the listeners on a port
+func Who(port int) ([]Listener, error) {
+	all, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var found []Listener
+	for _, l := range all {
+		if l.Port == port {
+			found = append(found, l)
+		}
+	}
+	return found, nil
+}
+
+// IsFree reports whether a port can be bound on all interfaces
+func IsFree(port int) bool {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
+
+// NextFree returns the first free port at or after start, or 0 if none is found
+func NextFree(start int) int {
+	for port := start; port < start+100 && port <= 65535; port++ {
+		if IsFree(port) {
+			return port
+		}
+	}
+	return 0
+}
+
+// Kill stops the process with the given PID
+func Kill(pid int) error {
+	if pid <= 0 {
+		return fmt.Errorf("unknown process (try again with elevated privileges)")
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		return proc.Kill()
+	}
+	return proc.Signal(syscall.SIGTERM)
+}
+
+// Attribute sets the Project of each listener whose working directory is
+// inside one of the projects, preferring the most specific path
+func Attribute(listeners []Listener, list []projects.Project) {
+	for i := range listeners {
+		cwd := listeners[i].Cwd
+		if cwd == "" {
+			continue
+		}
+
+		best := -1
+		for j, p := range list {
+			if !projects.Within(cwd, p.Path) {
+				continue
+			}
+			if best < 0 || len(p.Path) > len(list[best].Path) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			listeners[i].Project = list[best].Name
+		}
+	}
+}
+
+// IsDevPort reports whether a port is commonly used by development servers
+func IsDevPort(port int) bool {
+	for _, p := range DevPorts {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupe merges IPv4 and IPv6 sockets of the same process and port
+func dedupe(listeners []Listener) []Listener {
+	type key struct{ port, pid int }
+	seen := make(map[key]int)
+	var out []Listener
+
+	for _, l := range listeners {
+		k := key{l.Port, l.PID}
+		if i, ok := seen[k]; ok {
+			if !strings.Contains(out[i].Addr, l.Addr) {
+				out[i].Addr += ", " + l.Addr
+			}
+			continue
+		}
+		seen[k] = len(out)
+		out = append(out, l)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Port < out[j].Port })
+	return out
+}
@@ -0,0 +1,147 @@
+//go:build linux
+
+package ports
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procRoot is the proc filesystem, replaced in tests
+var procRoot = "/proc"
+
+// tcpListen is the socket state for LISTEN in /proc/net/tcp
+const tcpListen = "0A"
+
+// socket is a listening socket read from /proc/net/tcp{,6}
+type socket struct {
+	addr  string
+	port  int
+	inode string
+}
+
+func list() ([]Listener, error) {
+	var sockets []socket
+	for _, name := range []string{"tcp", "tcp6"} {
+		f, err := os.Open(filepath.Join(procRoot, "net", name))
+		if err != nil {
+			// IPv6 may be disabled
+			if name == "tcp6" && os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		found, err := parseProcNetTCP(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse /proc/net/%s: %w", name, err)
+		}
+		sockets = append(sockets, found...)
+	}
+
+	owners := socketOwners()
+
+	listeners := make([]Listener, 0, len(sockets))
+	for _, s := range sockets {
+		l := Listener{Port: s.port, Addr: s.addr}
+		if pid, ok := owners[s.inode]; ok {
+			l.PID = pid
+			l.Process = readProc(pid, "comm")
+			l.Cwd, _ = os.Readlink(filepath.Join(procRoot, strconv.Itoa(pid), "cwd"))
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// parseProcNetTCP returns the listening sockets in a /proc/net/tcp or tcp6 table
+func parseProcNetTCP(r io.Reader) ([]socket, error) {
+	var sockets []socket
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // header
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+
+		host, portHex, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		if err != nil {
+			return nil, err
+		}
+		ip, err := parseHexIP(host)
+		if err != nil {
+			return nil, err
+		}
+
+		sockets = append(sockets, socket{addr: ip.String(), port: int(port), inode: fields[9]})
+	}
+	return sockets, scanner.Err()
+}
+
+// parseHexIP decodes an address from /proc/net/tcp, stored as 32-bit words
+// in host byte order (little-endian on all platforms bdev supports)
+func parseHexIP(s string) (net.IP, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || (len(b) != 4 && len(b) != 16) {
+		return nil, fmt.Errorf("invalid address %q", s)
+	}
+	ip := make(net.IP, len(b))
+	for i := 0; i < len(b); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+	return ip, nil
+}
+
+// socketOwners maps socket inodes to the PID holding them. Processes of other
+// users are not readable without privileges and are left out.
+func socketOwners() map[string]int {
+	owners := make(map[string]int)
+
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return owners
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(procRoot, e.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			if inode, ok := strings.CutPrefix(target, "socket:["); ok {
+				owners[strings.TrimSuffix(inode, "]")] = pid
+			}
+		}
+	}
+	return owners
+}
+
+// readProc reads a single-line /proc/<pid> file such as comm
+func readProc(pid int, name string) string {
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,131 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41234 1 0000000000000000 100 0 0 10 0
+   1: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41250 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:0BB8 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 41300 1 0000000000000000 20 4 30 10 -1
+`
+
+const procNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:1538 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41400 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000000000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41251 1 0000000000000000 100 0 0 10 0
+`
+
+// ==============================================================
+// parseProcNetTCP Tests
+// ==============================================================
+
+func TestParseProcNetTCP(t *testing.T) {
+	sockets, err := parseProcNetTCP(strings.NewReader(procNetTCP))
+	if err != nil {
+		t.Fatalf("parseProcNetTCP() error = %v", err)
+	}
+
+	want := []socket{
+		{addr: "127.0.0.1", port: 3000, inode: "41234"},
+		{addr: "0.0.0.0", port: 8080, inode: "41250"},
+	}
+	if len(sockets) != len(want) {
+		t.Fatalf("parseProcNetTCP() = %+v, want %+v", sockets, want)
+	}
+	for i := range want {
+		if sockets[i] != want[i] {
+			t.Errorf("socket %d = %+v, want %+v", i, sockets[i], want[i])
+		}
+	}
+}
+
+func TestParseProcNetTCP_IPv6(t *testing.T) {
+	sockets, err := parseProcNetTCP(strings.NewReader(procNetTCP6))
+	if err != nil {
+		t.Fatalf("parseProcNetTCP() error = %v", err)
+	}
+
+	want := []socket{
+		{addr: "::1", port: 5432, inode: "41400"},
+		{addr: "::", port: 8080, inode: "41251"},
+	}
+	if len(sockets) != len(want) {
+		t.Fatalf("parseProcNetTCP() = %+v, want %+v", sockets, want)
+	}
+	for i := range want {
+		if sockets[i] != want[i] {
+			t.Errorf("socket %d = %+v, want %+v", i, sockets[i], want[i])
+		}
+	}
+}
+
+func TestParseHexIP_Invalid(t *testing.T) {
+	for _, s := range []string{"zz", "0100", "0100007F00"} {
+		if _, err := parseHexIP(s); err == nil {
+			t.Errorf("parseHexIP(%q) should fail", s)
+		}
+	}
+}
+
+// ==============================================================
+// list Tests
+// ==============================================================
+
+func TestList_FakeProc(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "net", "tcp"), procNetTCP)
+	writeFile(t, filepath.Join(root, "net", "tcp6"), procNetTCP6)
+
+	// PID 42 runs a dev server holding both 8080 sockets
+	writeFile(t, filepath.Join(root, "42", "comm"), "node\n")
+	symlink(t, "/home/dev/projects/shop", filepath.Join(root, "42", "cwd"))
+	symlink(t, "socket:[41250]", filepath.Join(root, "42", "fd", "3"))
+	symlink(t, "socket:[41251]", filepath.Join(root, "42", "fd", "4"))
+	symlink(t, "/dev/null", filepath.Join(root, "42", "fd", "0"))
+
+	old := procRoot
+	procRoot = root
+	defer func() { procRoot = old }()
+
+	got, err := List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := []Listener{
+		{Port: 3000, Addr: "127.0.0.1"},
+		{Port: 5432, Addr: "::1"},
+		{Port: 8080, Addr: "0.0.0.0, ::", PID: 42, Process: "node", Cwd: "/home/dev/projects/shop"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("List()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func symlink(t *testing.T, target, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,115 @@
+//go:build !linux
+
+package ports
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// list uses lsof on macOS and BSDs and netstat on Windows, which have no /proc
+func list() ([]Listener, error) {
+	if runtime.GOOS == "windows" {
+		out, err := exec.Command("netstat", "-ano", "-p", "TCP").Output()
+		if err != nil {
+			return nil, fmt.Errorf("netstat failed: %w", err)
+		}
+		return parseNetstat(string(out)), nil
+	}
+
+	out, err := exec.Command("lsof", "-nP", "-iTCP", "-sTCP:LISTEN", "-F", "pcn").Output()
+	if err != nil {
+		// lsof exits 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lsof failed (is it installed?): %w", err)
+	}
+
+	listeners := parseLsof(string(out))
+	for i := range listeners {
+		listeners[i].Cwd = processCwd(listeners[i].PID)
+	}
+	return listeners, nil
+}
+
+// parseLsof parses `lsof -F pcn` field output
+func parseLsof(out string) []Listener {
+	var listeners []Listener
+	var pid int
+	var command string
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		value := line[1:]
+		switch line[0] {
+		case 'p':
+			pid, _ = strconv.Atoi(value)
+		case 'c':
+			command = value
+		case 'n':
+			addr, port, ok := splitHostPort(value)
+			if ok {
+				listeners = append(listeners, Listener{Port: port, Addr: addr, PID: pid, Process: command})
+			}
+		}
+	}
+	return listeners
+}
+
+// parseNetstat parses the LISTENING rows of `netstat -ano`
+func parseNetstat(out string) []Listener {
+	var listeners []Listener
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "TCP" || fields[3] != "LISTENING" {
+			continue
+		}
+		addr, port, ok := splitHostPort(fields[1])
+		if !ok {
+			continue
+		}
+		pid, _ := strconv.Atoi(fields[4])
+		listeners = append(listeners, Listener{Port: port, Addr: addr, PID: pid})
+	}
+	return listeners
+}
+
+// processCwd returns the working directory of a process using lsof
+func processCwd(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	out, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-d", "cwd", "-F", "n").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "n") {
+			return line[1:]
+		}
+	}
+	return ""
+}
+
+// splitHostPort splits "127.0.0.1:3000", "[::1]:3000" or "*:3000"
+func splitHostPort(s string) (string, int, bool) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.Trim(s[:i], "[]"), port, true
+}
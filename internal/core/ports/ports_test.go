@@ -0,0 +1,104 @@
+package ports
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Attribute Tests
+// ==============================================================
+
+func TestAttribute(t *testing.T) {
+	list := []projects.Project{
+		{Name: "shop", Path: "/home/dev/projects/shop"},
+		{Name: "shop-web", Path: "/home/dev/projects/shop/web"},
+		{Name: "blog", Path: "/home/dev/projects/blog"},
+	}
+	listeners := []Listener{
+		{Port: 3000, Cwd: "/home/dev/projects/shop/web/src"},
+		{Port: 8000, Cwd: "/home/dev/projects/shop"},
+		{Port: 4000, Cwd: "/home/dev/projects/blogger"},
+		{Port: 22},
+	}
+
+	Attribute(listeners, list)
+
+	want := []string{"shop-web", "shop", "", ""}
+	for i, l := range listeners {
+		if l.Project != want[i] {
+			t.Errorf("listener %d Project = %q, want %q", l.Port, l.Project, want[i])
+		}
+	}
+}
+
+// ==============================================================
+// dedupe Tests
+// ==============================================================
+
+func TestDedupe(t *testing.T) {
+	got := dedupe([]Listener{
+		{Port: 8080, Addr: "0.0.0.0", PID: 10},
+		{Port: 3000, Addr: "127.0.0.1", PID: 20},
+		{Port: 8080, Addr: "::", PID: 10},
+		{Port: 3000, Addr: "127.0.0.1", PID: 20},
+		{Port: 3000, Addr: "::1", PID: 30},
+	})
+
+	want := []Listener{
+		{Port: 3000, Addr: "127.0.0.1", PID: 20},
+		{Port: 3000, Addr: "::1", PID: 30},
+		{Port: 8080, Addr: "0.0.0.0, ::", PID: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupe() = %+v, want %+v", got, want)
+	}
+}
+
+// ==============================================================
+// IsFree / NextFree Tests
+// ==============================================================
+
+func TestIsFree(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	if IsFree(port) {
+		t.Errorf("IsFree(%d) = true for a bound port", port)
+	}
+	if next := NextFree(port); next == port || next == 0 {
+		t.Errorf("NextFree(%d) = %d, want another port", port, next)
+	}
+}
+
+func TestIsDevPort(t *testing.T) {
+	tests := []struct {
+		port int
+		want bool
+	}{
+		{3000, true},
+		{5173, true},
+		{8080, true},
+		{22, false},
+		{5432, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsDevPort(tt.port); got != tt.want {
+			t.Errorf("IsDevPort(%d) = %v, want %v", tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestKill_UnknownProcess(t *testing.T) {
+	if err := Kill(0); err == nil {
+		t.Error("Kill(0) should fail")
+	}
+}
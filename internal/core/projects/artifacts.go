@@ -0,0 +1,38 @@
+package projects
+
+import "gopkg.in/yaml.v3"
+
+// ArtifactRule names a generated directory that can be deleted and rebuilt.
+// A type definition lists it by name, or with conditions restricting it.
+type ArtifactRule struct {
+	Condition `yaml:",inline"`
+
+	Name      string `yaml:"name" json:"name"`                               // path relative to the project, slash-separated
+	Recursive bool   `yaml:"recursive,omitempty" json:"recursive,omitempty"` // match a directory with this name anywhere in the project
+}
+
+func (a *ArtifactRule) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*a = ArtifactRule{Name: node.Value}
+		return nil
+	}
+	type plain ArtifactRule
+	return node.Decode((*plain)(a))
+}
+
+// ArtifactRules returns the build outputs and dependency caches of the project type
+func (p *Project) ArtifactRules() []ArtifactRule {
+	def := LookupType(p.Type)
+	if def == nil {
+		return nil
+	}
+
+	var rules []ArtifactRule
+	pr := newProbe(p.Path)
+	for _, a := range def.Artifacts {
+		if a.holds(p, pr, onPath) {
+			rules = append(rules, a)
+		}
+	}
+	return rules
+}
@@ -0,0 +1,51 @@
+package projects
+
+import "testing"
+
+// ==============================================================
+// Artifact Rule Tests
+// ==============================================================
+
+func TestProject_ArtifactRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		project Project
+		want    []string
+	}{
+		{"nextjs", nil, Project{Type: TypeNextJS}, []string{".next", "out", "node_modules"}},
+		{"rust", nil, Project{Type: TypeRust}, []string{"target"}},
+		{"python", nil, Project{Type: TypePython}, []string{"__pycache__", ".pytest_cache", "dist"}},
+		{"laravel", nil, Project{Type: TypeLaravel}, []string{"vendor", "node_modules"}},
+		{"maven", map[string]string{"pom.xml": "<project/>"}, Project{Type: TypeJava}, []string{"target"}},
+		{"gradle", map[string]string{"build.gradle": ""}, Project{Type: TypeJava}, []string{"build", ".gradle"}},
+		{"cmake", map[string]string{"CMakeLists.txt": ""}, Project{Type: TypeCpp}, []string{"build"}},
+		{"makefile", map[string]string{"Makefile": ""}, Project{Type: TypeCpp}, nil},
+		{"go", nil, Project{Type: TypeGo}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				createFile(t, dir, name, content)
+			}
+			tt.project.Path = dir
+
+			rules := tt.project.ArtifactRules()
+			if tt.want == nil && len(rules) != 0 {
+				t.Errorf("ArtifactRules() = %v, want none", rules)
+			}
+
+			names := make(map[string]bool)
+			for _, r := range rules {
+				names[r.Name] = true
+			}
+			for _, want := range tt.want {
+				if !names[want] {
+					t.Errorf("ArtifactRules() = %v, missing %q", rules, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,469 @@
+package projects
+
+import (
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/badie/bdev/internal/core/ignore"
+)
+
+// CommandKinds are the commands a type definition can have
+var CommandKinds = []string{"start", "test", "build", "install", "lint", "lint_fix", "format", "format_check"}
+
+// TypeCommands are the default commands of a project type. Each lists the
+// ways to run it, the first option whose conditions hold is used.
+type TypeCommands struct {
+	Start       CommandOptions `yaml:"start,omitempty" json:"start,omitempty"`
+	Test        CommandOptions `yaml:"test,omitempty" json:"test,omitempty"`
+	Build       CommandOptions `yaml:"build,omitempty" json:"build,omitempty"`
+	Install     CommandOptions `yaml:"install,omitempty" json:"install,omitempty"`
+	Lint        CommandOptions `yaml:"lint,omitempty" json:"lint,omitempty"`
+	LintFix     CommandOptions `yaml:"lint_fix,omitempty" json:"lint_fix,omitempty"`
+	Format      CommandOptions `yaml:"format,omitempty" json:"format,omitempty"`
+	FormatCheck CommandOptions `yaml:"format_check,omitempty" json:"format_check,omitempty"`
+}
+
+// Options returns the options of a command kind, see CommandKinds
+func (c *TypeCommands) Options(kind string) CommandOptions {
+	switch kind {
+	case "start":
+		return c.Start
+	case "test":
+		return c.Test
+	case "build":
+		return c.Build
+	case "install":
+		return c.Install
+	case "lint":
+		return c.Lint
+	case "lint_fix":
+		return c.LintFix
+	case "format":
+		return c.Format
+	case "format_check":
+		return c.FormatCheck
+	}
+	return nil
+}
+
+func (c *TypeCommands) compile() error {
+	for _, kind := range CommandKinds {
+		for i := range c.Options(kind) {
+			if err := c.Options(kind)[i].compile(); err != nil {
+				return fmt.Errorf("%s command %d: %v", kind, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CommandOption is one way to run a type command. It runs a command line
+// (split into arguments, or run by the shell when it uses shell syntax), a
+// tool through the package manager (npx, poetry run...), a package script
+// when the project has it, or the package manager install.
+type CommandOption struct {
+	Condition `yaml:",inline"`
+
+	Run     string `yaml:"run,omitempty" json:"run,omitempty"`
+	Exec    string `yaml:"exec,omitempty" json:"exec,omitempty"`
+	Script  string `yaml:"script,omitempty" json:"script,omitempty"`
+	Install bool   `yaml:"install,omitempty" json:"install,omitempty"`
+
+	// Args are passed on to the script
+	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
+
+	// Wrapper is a project file run instead of the program of Run when it
+	// exists, such as mvnw or vendor/bin/pint
+	Wrapper string `yaml:"wrapper,omitempty" json:"wrapper,omitempty"`
+
+	// Tool is checked for before linting or formatting, instead of the program
+	Tool string `yaml:"tool,omitempty" json:"tool,omitempty"`
+
+	// FailOnOutput treats any output as failure (gofmt -l lists unformatted files)
+	FailOnOutput bool `yaml:"fail_on_output,omitempty" json:"fail_on_output,omitempty"`
+
+	// Sources appends the matching project files to the command
+	Sources *SourceFiles `yaml:"sources,omitempty" json:"sources,omitempty"`
+
+	// Port is how a start command is given the port to listen on: PortEnv,
+	// PortServerEnv, PortFlag, PortArg or PortAuto. Run lines can use
+	// {{port}} instead.
+	Port string `yaml:"port,omitempty" json:"port,omitempty"`
+}
+
+// Ways a start command is given its port, see CommandOption.Port
+const (
+	PortEnv       = "env"         // reads PORT
+	PortServerEnv = "server_port" // reads SERVER_PORT, like artisan serve and Spring Boot
+	PortFlag      = "flag"        // takes --port
+	PortArg       = "arg"         // takes the port as last argument
+	PortAuto      = "auto"        // found from the dev server the script runs
+
+	portInline = "inline" // the run line has {{port}}
+)
+
+// CommandOptions are the options of a command. A single command line is
+// accepted for a run option without conditions.
+type CommandOptions []CommandOption
+
+func (o *CommandOptions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*o = CommandOptions{{Run: node.Value}}
+		return nil
+	}
+	var list []CommandOption
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*o = list
+	return nil
+}
+
+func (o *CommandOption) compile() error {
+	verbs := 0
+	for _, set := range []bool{o.Run != "", o.Exec != "", o.Script != "", o.Install} {
+		if set {
+			verbs++
+		}
+	}
+	if verbs != 1 {
+		return fmt.Errorf("needs one of run, exec, script or install")
+	}
+	if len(o.Args) > 0 && o.Script == "" {
+		return fmt.Errorf("args are only passed to scripts")
+	}
+	if o.Wrapper != "" && o.Run == "" {
+		return fmt.Errorf("wrapper needs a run command")
+	}
+	switch o.Port {
+	case "", PortEnv, PortServerEnv, PortFlag, PortArg:
+	case PortAuto:
+		if o.Script == "" {
+			return fmt.Errorf("port auto needs a script")
+		}
+	default:
+		return fmt.Errorf("unknown port %q (use %s, %s, %s, %s or %s)", o.Port, PortEnv, PortServerEnv, PortFlag, PortArg, PortAuto)
+	}
+	if o.Sources != nil {
+		if err := o.Sources.compile(); err != nil {
+			return err
+		}
+	}
+	return o.Condition.compile()
+}
+
+// PortOption is a default port, used when its conditions hold
+type PortOption struct {
+	Condition `yaml:",inline"`
+	Port      int `yaml:"port" json:"port"`
+}
+
+// PortOptions are the default ports of a type, the first one that holds
+// wins. A single number is accepted for a port without conditions.
+type PortOptions []PortOption
+
+func (o *PortOptions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var port int
+		if err := node.Decode(&port); err != nil {
+			return err
+		}
+		*o = PortOptions{{Port: port}}
+		return nil
+	}
+	var list []PortOption
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*o = list
+	return nil
+}
+
+// SourceFiles selects the project files handed to a command, such as the
+// sources clang-format rewrites. Ignored paths are skipped.
+type SourceFiles struct {
+	Extensions []string `yaml:"extensions" json:"extensions"`
+	Exclude    []string `yaml:"exclude,omitempty" json:"exclude,omitempty"` // directory names or globs
+}
+
+func (s *SourceFiles) compile() error {
+	if len(s.Extensions) == 0 {
+		return fmt.Errorf("sources need extensions")
+	}
+	for i, ext := range s.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			s.Extensions[i] = "." + ext
+		}
+	}
+	return nil
+}
+
+// list returns the matching files under root, relative to it
+func (s *SourceFiles) list(root string) []string {
+	var files []string
+	matcher := ignore.Load(root)
+
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return nil
+		}
+
+		excluded := d.IsDir() && anyOf(s.Exclude, func(glob string) bool {
+			ok, _ := path.Match(glob, d.Name())
+			return ok
+		})
+		if excluded || matcher.Match(filepath.ToSlash(rel), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && slices.Contains(s.Extensions, filepath.Ext(p)) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+
+	return files
+}
+
+// ============================================================
+// Resolution
+// ============================================================
+
+// Command is a type command resolved for a project
+type Command struct {
+	Bin  string
+	Args []string
+
+	// Tool is checked for before running, empty means Bin itself
+	Tool string
+
+	// FailOnOutput treats any output as failure
+	FailOnOutput bool
+
+	// Port is how the command is given a port, empty when it cannot be
+	Port string
+}
+
+// TypeCommand resolves the command of kind (see CommandKinds) from the
+// project type definition, or returns nil when no option applies. hasTool
+// reports whether a tool is installed, nil looks it up on PATH.
+func (p *Project) TypeCommand(kind string, hasTool func(string) bool) *Command {
+	return p.typeCommand(kind, 0, hasTool)
+}
+
+// typeCommandLine returns the type command of kind, or "" if there is none
+func (p *Project) typeCommandLine(kind string) (string, []string) {
+	if c := p.TypeCommand(kind, nil); c != nil {
+		return c.Bin, c.Args
+	}
+	return "", nil
+}
+
+// typeCommand resolves a command, made to listen on port when it is set
+func (p *Project) typeCommand(kind string, port int, hasTool func(string) bool) *Command {
+	def := LookupType(p.Type)
+	if def == nil {
+		return nil
+	}
+	if hasTool == nil {
+		hasTool = onPath
+	}
+
+	options := def.Commands.Options(kind)
+	// Linters without a fix mode run as they are
+	if kind == "lint_fix" && len(options) == 0 {
+		options = def.Commands.Lint
+	}
+
+	pr := newProbe(p.Path)
+	for i := range options {
+		o := &options[i]
+		if (o.Script != "" && !p.hasScript(o.Script)) || !o.holds(p, pr, hasTool) {
+			continue
+		}
+		if c := o.command(p, port); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// command builds the command of the option, or nil when it has no source
+// files to work on
+func (o *CommandOption) command(p *Project, port int) *Command {
+	pm := p.GetPackageManager()
+	c := &Command{Tool: o.Tool, FailOnOutput: o.FailOnOutput, Port: o.Port}
+	n := strconv.Itoa(port)
+
+	switch {
+	case o.Script != "":
+		c.Bin, c.Args = pm.RunScript(o.Script)
+		if c.Port == PortAuto {
+			c.Port = scriptPort(p.Scripts[o.Script])
+		}
+		extra := o.Args
+		if port > 0 && c.Port == PortFlag {
+			extra = append(slices.Clone(extra), "--port", n)
+		}
+		if len(extra) > 0 {
+			c.Args = append(c.Args, pm.ScriptArgs(extra...)...)
+		}
+
+	case o.Exec != "":
+		fields := strings.Fields(o.Exec)
+		var args []string
+		if len(fields) > 1 {
+			args = fields[1:]
+		}
+		c.Bin, c.Args = pm.Exec(fields[0], args...)
+		if c.Tool == "" {
+			c.Tool = fields[0]
+		}
+
+	case o.Install:
+		c.Bin, c.Args = pm.Install()
+
+	default:
+		listen := port
+		if strings.Contains(o.Run, "{{port}}") {
+			c.Port = portInline
+			if listen == 0 {
+				listen = p.DefaultPort()
+			}
+		}
+		c.Bin, c.Args = commandLine(o.Run, map[string]string{
+			"name":  p.Name,
+			"image": strings.ToLower(p.Name),
+			"port":  strconv.Itoa(listen),
+		})
+		if o.Wrapper != "" && c.Bin == filepath.FromSlash(strings.Fields(o.Run)[0]) {
+			if w := p.wrapper(o.Wrapper); w != "" {
+				c.Bin = w
+			}
+		}
+	}
+
+	if port > 0 && o.Script == "" {
+		switch c.Port {
+		case PortFlag:
+			c.Args = append(c.Args, "--port", n)
+		case PortArg:
+			c.Args = append(c.Args, n)
+		}
+	}
+	if o.Sources != nil {
+		files := o.Sources.list(p.Path)
+		if len(files) == 0 {
+			return nil
+		}
+		c.Args = append(c.Args, files...)
+	}
+	return c
+}
+
+// portFlagTools are dev servers that take --port and ignore PORT
+var portFlagTools = []string{"vite", "astro", "ng serve"}
+
+// portEnvTools are dev servers that read PORT
+var portEnvTools = []string{"next", "nuxt", "react-scripts", "vue-cli-service"}
+
+// scriptPort returns how the dev server a script runs is given its port
+func scriptPort(script string) string {
+	switch {
+	case containsAny(script, portFlagTools):
+		return PortFlag
+	case containsAny(script, portEnvTools):
+		return PortEnv
+	}
+	return ""
+}
+
+// wrapper returns the command of a project-local wrapper such as mvnw or
+// vendor/bin/pint, or "" if the project has none
+func (p *Project) wrapper(name string) string {
+	candidates := []string{name}
+	if runtime.GOOS == "windows" {
+		candidates = []string{name + ".cmd", name + ".bat", name}
+	}
+	for _, c := range candidates {
+		if !p.hasFile(c) {
+			continue
+		}
+		rel := filepath.FromSlash(c)
+		if !strings.ContainsRune(rel, filepath.Separator) {
+			rel = "." + string(filepath.Separator) + rel
+		}
+		return rel
+	}
+	return ""
+}
+
+func (p *Project) hasScript(name string) bool {
+	_, ok := p.Scripts[name]
+	return ok
+}
+
+func onPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// shellSyntax are the characters that make a command run through the shell
+const shellSyntax = "|&;<>$`\"'*?(){}\\"
+
+// placeholder matches {{name}}, {{image}} and {{port}} in command lines
+var placeholder = regexp.MustCompile(`\{\{(name|image|port)\}\}`)
+
+// commandLine splits a definition command into arguments, leaving it to
+// the shell when it uses pipes, redirections, variables or quotes.
+// Placeholders are replaced by vars, quoted for the shell.
+func commandLine(line string, vars map[string]string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", nil
+	}
+	expand := func(s string, quote func(string) string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			return quote(vars[placeholder.FindStringSubmatch(m)[1]])
+		})
+	}
+
+	if strings.ContainsAny(placeholder.ReplaceAllString(line, ""), shellSyntax) {
+		return ShellCommand(expand(line, ShellQuote))
+	}
+
+	fields := strings.Fields(line)
+	for i, f := range fields {
+		fields[i] = expand(f, func(s string) string { return s })
+	}
+	fields[0] = filepath.FromSlash(fields[0])
+	if len(fields) == 1 {
+		return fields[0], nil
+	}
+	return fields[0], fields[1:]
+}
@@ -0,0 +1,284 @@
+package projects
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeFileNames are the Compose files looked up, in the order docker
+// compose prefers them
+var ComposeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// Containers is the Docker setup of a project
+type Containers struct {
+	Dockerfile  bool      `json:"dockerfile,omitempty"`
+	ComposeFile string    `json:"compose_file,omitempty"` // file name, relative to the project
+	Services    []Service `json:"services,omitempty"`
+}
+
+// Service is a service declared in the Compose file
+type Service struct {
+	Name  string        `json:"name"`
+	Image string        `json:"image,omitempty"`
+	Build string        `json:"build,omitempty"` // build context, when the image is built locally
+	Ports []ServicePort `json:"ports,omitempty"`
+}
+
+// ServicePort is a container port, published on the host unless Published is 0
+type ServicePort struct {
+	Published int    `json:"published,omitempty"`
+	Target    int    `json:"target"`
+	Protocol  string `json:"protocol,omitempty"` // "tcp" unless set
+}
+
+// String formats the port like docker ps, e.g. 8080->80/tcp
+func (p ServicePort) String() string {
+	s := strconv.Itoa(p.Target)
+	if p.Published != 0 {
+		s = strconv.Itoa(p.Published) + "->" + s
+	}
+	if p.Protocol != "" && p.Protocol != "tcp" {
+		s += "/" + p.Protocol
+	}
+	return s
+}
+
+// Images returns the distinct images of the services, not counting the ones
+// built locally without an image name
+func (c *Containers) Images() []string {
+	seen := make(map[string]bool)
+	var images []string
+	for _, s := range c.Services {
+		if s.Image != "" && !seen[s.Image] {
+			seen[s.Image] = true
+			images = append(images, s.Image)
+		}
+	}
+	return images
+}
+
+// PublishedPorts returns the host ports published by the services
+func (c *Containers) PublishedPorts() []int {
+	var ports []int
+	for _, s := range c.Services {
+		for _, p := range s.Ports {
+			if p.Published != 0 {
+				ports = append(ports, p.Published)
+			}
+		}
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// FindComposeFile returns the name of the Compose file in dir, or ""
+func FindComposeFile(dir string) string {
+	for _, name := range ComposeFileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// DetectContainers returns the Docker setup of dir, or nil if it has neither
+// a Dockerfile nor a Compose file. A Compose file that cannot be parsed is
+// still reported, without services.
+func DetectContainers(dir string) *Containers {
+	c := &Containers{ComposeFile: FindComposeFile(dir)}
+	if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); err == nil {
+		c.Dockerfile = true
+	}
+	if c.ComposeFile == "" {
+		if !c.Dockerfile {
+			return nil
+		}
+		return c
+	}
+
+	c.Services, _ = ParseCompose(filepath.Join(dir, c.ComposeFile))
+	return c
+}
+
+// composeFile is the part of a Compose file describing services
+type composeFile struct {
+	Services map[string]struct {
+		Image string    `yaml:"image"`
+		Build yaml.Node `yaml:"build"`
+		Ports []any     `yaml:"ports"`
+	} `yaml:"services"`
+}
+
+// ParseCompose reads the services of a Compose file, sorted by name.
+// ${VAR} and ${VAR:-default} are interpolated from the environment.
+func ParseCompose(path string) ([]Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file composeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", filepath.Base(path), err)
+	}
+
+	services := make([]Service, 0, len(file.Services))
+	for name, s := range file.Services {
+		service := Service{Name: name, Image: interpolate(s.Image)}
+
+		// build is a context path, or a mapping with a context key
+		switch s.Build.Kind {
+		case yaml.ScalarNode:
+			service.Build = interpolate(s.Build.Value)
+		case yaml.MappingNode:
+			var build struct {
+				Context string `yaml:"context"`
+			}
+			s.Build.Decode(&build)
+			service.Build = interpolate(build.Context)
+			if service.Build == "" {
+				service.Build = "."
+			}
+		}
+
+		for _, raw := range s.Ports {
+			ports, err := parseComposePort(raw)
+			if err != nil {
+				return nil, fmt.Errorf("service %s: %v", name, err)
+			}
+			service.Ports = append(service.Ports, ports...)
+		}
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+// parseComposePort parses a port in the short syntax
+// ([host_ip:][published:]target[/protocol], with ranges) or the long syntax
+func parseComposePort(raw any) ([]ServicePort, error) {
+	switch v := raw.(type) {
+	case int:
+		return []ServicePort{{Target: v}}, nil
+	case string:
+		return parseShortPort(interpolate(v))
+	case map[string]any:
+		port := ServicePort{Protocol: fmt.Sprint(orDefault(v["protocol"], ""))}
+		target, err := strconv.Atoi(interpolate(fmt.Sprint(orDefault(v["target"], ""))))
+		if err != nil {
+			return nil, fmt.Errorf("invalid port target %v", v["target"])
+		}
+		port.Target = target
+		if published := interpolate(fmt.Sprint(orDefault(v["published"], ""))); published != "" {
+			ports, err := expandRange(published, []int{target})
+			if err != nil {
+				return nil, err
+			}
+			port.Published = ports[0].Published
+		}
+		return []ServicePort{port}, nil
+	}
+	return nil, fmt.Errorf("invalid port %v", raw)
+}
+
+func parseShortPort(s string) ([]ServicePort, error) {
+	protocol := ""
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s, protocol = s[:i], s[i+1:]
+	}
+
+	// The host IP may be an IPv6 address in brackets
+	parts := strings.Split(s, ":")
+	if i := strings.LastIndex(s, "]:"); i >= 0 {
+		parts = strings.Split(s[i+2:], ":")
+	}
+
+	targets, err := portRange(parts[len(parts)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", s)
+	}
+
+	var ports []ServicePort
+	if len(parts) == 1 || parts[len(parts)-2] == "" {
+		for _, t := range targets {
+			ports = append(ports, ServicePort{Target: t})
+		}
+	} else {
+		ports, err = expandRange(parts[len(parts)-2], targets)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", s)
+		}
+	}
+
+	for i := range ports {
+		ports[i].Protocol = protocol
+	}
+	return ports, nil
+}
+
+// expandRange pairs published ports with targets. A single published port
+// range can be larger than the targets, as docker picks one of them.
+func expandRange(published string, targets []int) ([]ServicePort, error) {
+	hosts, err := portRange(published)
+	if err != nil {
+		return nil, err
+	}
+	ports := make([]ServicePort, len(targets))
+	for i, t := range targets {
+		ports[i] = ServicePort{Published: hosts[min(i, len(hosts)-1)], Target: t}
+	}
+	return ports, nil
+}
+
+// portRange parses 8080 or 8080-8082
+func portRange(s string) ([]int, error) {
+	lo, hi, isRange := strings.Cut(s, "-")
+	start, err := strconv.Atoi(lo)
+	if err != nil {
+		return nil, err
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(hi); err != nil || end < start {
+			return nil, fmt.Errorf("invalid range %q", s)
+		}
+	}
+	ports := make([]int, 0, end-start+1)
+	for p := start; p <= end; p++ {
+		ports = append(ports, p)
+	}
+	return ports, nil
+}
+
+func orDefault(v, def any) any {
+	if v == nil {
+		return def
+	}
+	return v
+}
+
+var composeVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::?-([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// interpolate expands ${VAR}, ${VAR:-default}, ${VAR-default} and $VAR
+func interpolate(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	return composeVar.ReplaceAllStringFunc(s, func(m string) string {
+		sub := composeVar.FindStringSubmatch(m)
+		if sub[3] != "" {
+			return os.Getenv(sub[3])
+		}
+		if v := os.Getenv(sub[1]); v != "" {
+			return v
+		}
+		return sub[2]
+	})
+}
@@ -0,0 +1,150 @@
+package projects
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// ==============================================================
+// Compose Parsing Tests
+// ==============================================================
+
+func TestParseCompose(t *testing.T) {
+	t.Setenv("WEB_PORT", "8081")
+
+	dir := t.TempDir()
+	createFile(t, dir, "compose.yaml", `services:
+  web:
+    build: ./web
+    ports:
+      - "${WEB_PORT:-8080}:80"
+      - "127.0.0.1:9229:9229"
+  db:
+    image: postgres:16
+    ports:
+      - 5432
+  cache:
+    image: redis:${REDIS_TAG:-7}
+    build:
+      dockerfile: cache.Dockerfile
+    ports:
+      - target: 6379
+        published: "6380"
+      - "[::1]:9000-9001:9000-9001/udp"
+`)
+
+	services, err := ParseCompose(filepath.Join(dir, "compose.yaml"))
+	if err != nil {
+		t.Fatalf("ParseCompose() error = %v", err)
+	}
+
+	want := []Service{
+		{Name: "cache", Image: "redis:7", Build: ".", Ports: []ServicePort{
+			{Published: 6380, Target: 6379},
+			{Published: 9000, Target: 9000, Protocol: "udp"},
+			{Published: 9001, Target: 9001, Protocol: "udp"},
+		}},
+		{Name: "db", Image: "postgres:16", Ports: []ServicePort{{Target: 5432}}},
+		{Name: "web", Build: "./web", Ports: []ServicePort{
+			{Published: 8081, Target: 80},
+			{Published: 9229, Target: 9229},
+		}},
+	}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("ParseCompose() =\n%+v\nwant\n%+v", services, want)
+	}
+}
+
+func TestParseCompose_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"yaml", "services: [\n"},
+		{"port", "services:\n  web:\n    ports: [\"http:80\"]\n"},
+		{"range", "services:\n  web:\n    ports: [\"90-80:80\"]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			createFile(t, dir, "docker-compose.yml", tt.content)
+			if _, err := ParseCompose(filepath.Join(dir, "docker-compose.yml")); err == nil {
+				t.Error("ParseCompose() expected error")
+			}
+		})
+	}
+}
+
+func TestServicePort_String(t *testing.T) {
+	tests := []struct {
+		port ServicePort
+		want string
+	}{
+		{ServicePort{Published: 8080, Target: 80}, "8080->80"},
+		{ServicePort{Published: 53, Target: 53, Protocol: "udp"}, "53->53/udp"},
+		{ServicePort{Target: 5432, Protocol: "tcp"}, "5432"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.port.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// ==============================================================
+// Container Detection Tests
+// ==============================================================
+
+func TestDetectContainers(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		if c := DetectContainers(t.TempDir()); c != nil {
+			t.Errorf("DetectContainers() = %+v, want nil", c)
+		}
+	})
+
+	t.Run("dockerfile_only", func(t *testing.T) {
+		dir := t.TempDir()
+		createFile(t, dir, "Dockerfile", "FROM alpine\n")
+		c := DetectContainers(dir)
+		if c == nil || !c.Dockerfile || c.ComposeFile != "" {
+			t.Errorf("DetectContainers() = %+v", c)
+		}
+	})
+
+	t.Run("compose_preference", func(t *testing.T) {
+		dir := t.TempDir()
+		createFile(t, dir, "docker-compose.yml", "services:\n  old:\n    image: x\n")
+		createFile(t, dir, "compose.yaml", `services:
+  api:
+    image: acme/api
+    ports: ["3000:3000"]
+  worker:
+    image: acme/api
+  db:
+    image: postgres
+    ports: ["5432:5432"]
+`)
+		c := DetectContainers(dir)
+		if c == nil || c.ComposeFile != "compose.yaml" || len(c.Services) != 3 {
+			t.Fatalf("DetectContainers() = %+v", c)
+		}
+		if got := c.Images(); !reflect.DeepEqual(got, []string{"acme/api", "postgres"}) {
+			t.Errorf("Images() = %v", got)
+		}
+		if got := c.PublishedPorts(); !reflect.DeepEqual(got, []int{3000, 5432}) {
+			t.Errorf("PublishedPorts() = %v", got)
+		}
+	})
+
+	t.Run("broken_compose", func(t *testing.T) {
+		dir := t.TempDir()
+		createFile(t, dir, "compose.yml", "services: [\n")
+		c := DetectContainers(dir)
+		if c == nil || c.ComposeFile != "compose.yml" || len(c.Services) != 0 {
+			t.Errorf("DetectContainers() = %+v, want the file without services", c)
+		}
+	})
+}
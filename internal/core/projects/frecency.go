@@ -0,0 +1,202 @@
+package projects
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// UseRecord counts how often and how recently a project was used
+type UseRecord struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// Usage holds the use records of projects keyed by path
+type Usage struct {
+	Projects map[string]*UseRecord `json:"projects"`
+
+	path string
+}
+
+// LoadUsage reads the usage file, returning empty usage if it does not exist
+func LoadUsage(path string) (*Usage, error) {
+	u := &Usage{Projects: make(map[string]*UseRecord), path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return u, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, u); err != nil {
+		return nil, err
+	}
+	if u.Projects == nil {
+		u.Projects = make(map[string]*UseRecord)
+	}
+	return u, nil
+}
+
+// Save writes the usage file
+func (u *Usage) Save() error {
+	if err := os.MkdirAll(filepath.Dir(u.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(u.path, data, 0o644)
+}
+
+// Record marks a project as used now
+func (u *Usage) Record(path string) {
+	rec, ok := u.Projects[path]
+	if !ok {
+		rec = &UseRecord{}
+		u.Projects[path] = rec
+	}
+	rec.Count++
+	rec.Last = time.Now()
+}
+
+// RecordUse marks a project as used in the usage file. Failures are ignored,
+// usage only affects ranking.
+func RecordUse(usageFile, path string) {
+	u, err := LoadUsage(usageFile)
+	if err != nil {
+		return
+	}
+	u.Record(path)
+	_ = u.Save()
+}
+
+// Frecency scores a project by use count, weighted by how recently it was used
+func (u *Usage) Frecency(path string, now time.Time) float64 {
+	rec, ok := u.Projects[path]
+	if !ok {
+		return 0
+	}
+
+	age := now.Sub(rec.Last)
+	weight := 0.25
+	switch {
+	case age < time.Hour:
+		weight = 4
+	case age < 24*time.Hour:
+		weight = 2
+	case age < 7*24*time.Hour:
+		weight = 1
+	case age < 30*24*time.Hour:
+		weight = 0.5
+	}
+	return float64(rec.Count) * weight
+}
+
+// Match is a project ranked against a query
+type Match struct {
+	Project Project
+	Score   float64
+}
+
+// Rank returns the projects matching query, best first. The fuzzy score is
+// boosted by frecency, so frequently used projects win close matches. An
+// empty query ranks every used project by frecency alone.
+func Rank(list []Project, query string, usage *Usage) []Match {
+	now := time.Now()
+	var matches []Match
+
+	for _, p := range list {
+		frecency := 0.0
+		if usage != nil {
+			frecency = usage.Frecency(p.Path, now)
+		}
+
+		if query == "" {
+			if frecency > 0 {
+				matches = append(matches, Match{Project: p, Score: frecency})
+			}
+			continue
+		}
+
+		score, ok := FuzzyScore(query, p.Name)
+		if !ok {
+			continue
+		}
+		matches = append(matches, Match{Project: p, Score: float64(score) + 10*math.Log2(1+frecency)})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Project.Name < matches[j].Project.Name
+	})
+	return matches
+}
+
+// Fuzzy scoring weights
+const (
+	scoreChar        = 1
+	scoreConsecutive = 5
+	scoreWordStart   = 8
+	scorePrefix      = 15
+	scoreExact       = 50
+	penaltyGap       = 1
+)
+
+// FuzzyScore reports whether the characters of query appear in target in
+// order, ignoring case, and scores the match: consecutive characters, word
+// starts (after -, _, ., / or a space), prefixes and exact matches score
+// higher, and skipped characters lower the score. "bdv" matches "bdev-cli".
+func FuzzyScore(query, target string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+	if len(q) == 0 {
+		return 0, true
+	}
+	if string(q) == string(t) {
+		return scoreExact + len(q)*scoreConsecutive, true
+	}
+
+	score := 0
+	qi := 0
+	last := -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		score += scoreChar
+		switch {
+		case last >= 0 && ti == last+1:
+			score += scoreConsecutive
+		case ti == 0 || isWordSeparator(t[ti-1]):
+			score += scoreWordStart
+		}
+		if last >= 0 {
+			score -= (ti - last - 1) * penaltyGap
+		}
+		last = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+
+	if strings.HasPrefix(string(t), string(q)) {
+		score += scorePrefix
+	}
+	return score, true
+}
+
+func isWordSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || r == '/' || unicode.IsSpace(r)
+}
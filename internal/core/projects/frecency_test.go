@@ -0,0 +1,126 @@
+package projects
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ==============================================================
+// FuzzyScore Tests
+// ==============================================================
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query  string
+		target string
+		match  bool
+	}{
+		{"bdv", "bdev-cli", true},
+		{"BDEV", "bdev-cli", true},
+		{"cli", "bdev-cli", true},
+		{"", "anything", true},
+		{"vdb", "bdev-cli", false},
+		{"bdevx", "bdev-cli", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := FuzzyScore(tt.query, tt.target); ok != tt.match {
+			t.Errorf("FuzzyScore(%q, %q) match = %v, want %v", tt.query, tt.target, ok, tt.match)
+		}
+	}
+}
+
+func TestFuzzyScore_Ordering(t *testing.T) {
+	// Each query should score the first target above the second
+	tests := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		{"api", "api", "api-gateway"},            // exact
+		{"api", "api-gateway", "shop-api"},       // prefix
+		{"sa", "shop-api", "mesa-tools"},         // word starts
+		{"bdev", "bdev-cli", "b-d-e-v"},          // consecutive
+		{"web", "web", "my-website-builder-old"}, // gaps
+	}
+
+	for _, tt := range tests {
+		better, _ := FuzzyScore(tt.query, tt.better)
+		worse, _ := FuzzyScore(tt.query, tt.worse)
+		if better <= worse {
+			t.Errorf("FuzzyScore(%q): %q = %d, want above %q = %d", tt.query, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+// ==============================================================
+// Rank / Usage Tests
+// ==============================================================
+
+func TestRank_Frecency(t *testing.T) {
+	list := []Project{
+		{Name: "shop-api", Path: "/p/shop-api"},
+		{Name: "shop-admin", Path: "/p/shop-admin"},
+		{Name: "blog", Path: "/p/blog"},
+	}
+	usage := &Usage{Projects: map[string]*UseRecord{
+		"/p/shop-admin": {Count: 20, Last: time.Now()},
+		"/p/blog":       {Count: 1, Last: time.Now().Add(-60 * 24 * time.Hour)},
+	}}
+
+	matches := Rank(list, "shop", usage)
+	if len(matches) != 2 {
+		t.Fatalf("Rank() = %d matches, want 2", len(matches))
+	}
+	if matches[0].Project.Name != "shop-admin" {
+		t.Errorf("Rank()[0] = %s, want the frequently used shop-admin", matches[0].Project.Name)
+	}
+
+	// Without a query, only used projects are ranked
+	recent := Rank(list, "", usage)
+	if len(recent) != 2 || recent[0].Project.Name != "shop-admin" || recent[1].Project.Name != "blog" {
+		t.Errorf("Rank(\"\") = %v, want shop-admin then blog", recent)
+	}
+
+	if matches := Rank(list, "shop", nil); len(matches) != 2 {
+		t.Errorf("Rank() without usage = %d matches, want 2", len(matches))
+	}
+}
+
+func TestUsage_Frecency(t *testing.T) {
+	now := time.Now()
+	usage := &Usage{Projects: map[string]*UseRecord{
+		"/recent": {Count: 2, Last: now.Add(-10 * time.Minute)},
+		"/old":    {Count: 8, Last: now.Add(-90 * 24 * time.Hour)},
+	}}
+
+	if got := usage.Frecency("/recent", now); got != 8 {
+		t.Errorf("Frecency(recent) = %v, want 8", got)
+	}
+	if got := usage.Frecency("/old", now); got != 2 {
+		t.Errorf("Frecency(old) = %v, want 2", got)
+	}
+	if got := usage.Frecency("/unknown", now); got != 0 {
+		t.Errorf("Frecency(unknown) = %v, want 0", got)
+	}
+}
+
+func TestRecordUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	RecordUse(path, "/p/app")
+	RecordUse(path, "/p/app")
+	RecordUse(path, "/p/lib")
+
+	usage, err := LoadUsage(path)
+	if err != nil {
+		t.Fatalf("LoadUsage() error = %v", err)
+	}
+	if rec := usage.Projects["/p/app"]; rec == nil || rec.Count != 2 || time.Since(rec.Last) > time.Minute {
+		t.Errorf("app record = %+v, want 2 recent uses", rec)
+	}
+	if rec := usage.Projects["/p/lib"]; rec == nil || rec.Count != 1 {
+		t.Errorf("lib record = %+v, want 1 use", rec)
+	}
+}
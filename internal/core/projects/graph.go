@@ -0,0 +1,542 @@
+package projects
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// Dependency kinds, one per manifest syntax
+const (
+	DepGoReplace    = "go-replace"    // replace ... => ../path in go.mod
+	DepNpmFile      = "npm-file"      // file:, link: or portal: in package.json
+	DepNpmWorkspace = "npm-workspace" // workspace: in package.json
+	DepCargoPath    = "cargo-path"    // { path = "../crate" } in Cargo.toml
+	DepComposerPath = "composer-path" // path repository in composer.json
+)
+
+// Dependency is an edge of the project graph: From depends on To
+type Dependency struct {
+	From string `json:"from"` // project path
+	To   string `json:"to"`   // project path
+	Kind string `json:"kind"`
+	Spec string `json:"spec"` // the dependency as written in the manifest
+}
+
+// Graph holds the local dependencies between projects
+type Graph struct {
+	Nodes []Project    `json:"nodes"`
+	Edges []Dependency `json:"edges"`
+
+	byPath map[string]int
+}
+
+// localDep is a dependency read from a manifest, before it is resolved to a
+// project. It points at a directory, or at a package name for workspace deps.
+type localDep struct {
+	kind string
+	spec string
+	dir  string
+	name string
+}
+
+// BuildGraph reads the manifests of the projects and their workspace members
+// and links the ones depending on each other. Dependencies outside the
+// projects are ignored.
+func BuildGraph(list []Project) *Graph {
+	g := &Graph{Nodes: Flatten(list), byPath: make(map[string]int)}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].Path < g.Nodes[j].Path })
+	for i := range g.Nodes {
+		g.Nodes[i].Children = nil
+		g.byPath[g.Nodes[i].Path] = i
+	}
+
+	// workspace: names resolve among the members of the same workspace only,
+	// unrelated monorepos often have packages with the same name
+	workspaces := make(map[string]map[string]string) // workspace root -> package name -> path
+	rootOf := make(map[string]string)
+	for _, p := range list {
+		if len(p.Children) == 0 {
+			continue
+		}
+		names := make(map[string]string)
+		for _, n := range Flatten([]Project{p}) {
+			rootOf[n.Path] = p.Path
+			if name := npmName(n.Path); name != "" {
+				names[name] = n.Path
+			}
+		}
+		workspaces[p.Path] = names
+	}
+
+	seen := make(map[[2]string]bool)
+	for _, n := range g.Nodes {
+		for _, d := range localDeps(n.Path) {
+			to := ""
+			if d.name != "" {
+				to = workspaces[rootOf[n.Path]][d.name]
+			} else {
+				to = g.owner(d.dir)
+			}
+			key := [2]string{n.Path, to}
+			if to == "" || to == n.Path || seen[key] {
+				continue
+			}
+			seen[key] = true
+			g.Edges = append(g.Edges, Dependency{From: n.Path, To: to, Kind: d.kind, Spec: d.spec})
+		}
+	}
+
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// owner returns the path of the innermost project containing dir
+func (g *Graph) owner(dir string) string {
+	best := ""
+	for _, n := range g.Nodes {
+		if Within(dir, n.Path) && len(n.Path) > len(best) {
+			best = n.Path
+		}
+	}
+	return best
+}
+
+// Node returns the project at path, or nil if it is not in the graph
+func (g *Graph) Node(path string) *Project {
+	if i, ok := g.byPath[path]; ok {
+		return &g.Nodes[i]
+	}
+	return nil
+}
+
+// DependenciesOf returns the edges leaving a project
+func (g *Graph) DependenciesOf(path string) []Dependency {
+	var deps []Dependency
+	for _, e := range g.Edges {
+		if e.From == path {
+			deps = append(deps, e)
+		}
+	}
+	return deps
+}
+
+// Dependents returns the projects depending on path directly, and the ones
+// depending on it only through other projects
+func (g *Graph) Dependents(path string) (direct, transitive []string) {
+	seen := map[string]bool{path: true}
+	queue := []string{path}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range g.Edges {
+			if e.To != current || seen[e.From] {
+				continue
+			}
+			seen[e.From] = true
+			queue = append(queue, e.From)
+			if current == path {
+				direct = append(direct, e.From)
+			} else {
+				transitive = append(transitive, e.From)
+			}
+		}
+	}
+	return direct, transitive
+}
+
+// Levels orders paths so every project comes after its dependencies.
+// Projects in the same level do not depend on each other and can run in
+// parallel. Dependencies through projects outside paths count, and an edge
+// of a workspace member counts for the selected project containing it.
+func (g *Graph) Levels(paths []string) ([][]string, error) {
+	edges := g.Lift(paths)
+
+	pending := make(map[string]int) // number of unfinished dependencies
+	for _, p := range paths {
+		pending[p] = 0
+	}
+	for _, e := range edges {
+		pending[e.From]++
+	}
+
+	var levels [][]string
+	for len(pending) > 0 {
+		var level []string
+		for p, n := range pending {
+			if n == 0 {
+				level = append(level, p)
+			}
+		}
+		if len(level) == 0 {
+			var cycle []string
+			for p := range pending {
+				cycle = append(cycle, filepath.Base(p))
+			}
+			sort.Strings(cycle)
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+		}
+
+		sort.Strings(level)
+		for _, p := range level {
+			delete(pending, p)
+		}
+		for _, e := range edges {
+			if _, waiting := pending[e.From]; waiting && contains(level, e.To) {
+				pending[e.From]--
+			}
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// Lift maps the edges onto the innermost of paths containing each end,
+// dropping edges that stay within one of them. Edges through projects
+// outside paths are followed, so a selected project still comes after the
+// ones it depends on through projects that are not selected.
+func (g *Graph) Lift(paths []string) []Dependency {
+	owner := func(p string) string {
+		best := ""
+		for _, sel := range paths {
+			if Within(p, sel) && len(sel) > len(best) {
+				best = sel
+			}
+		}
+		return best
+	}
+
+	out := make(map[string][]Dependency)
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e)
+	}
+
+	seen := make(map[[2]string]bool)
+	var lifted []Dependency
+	for _, e := range g.Edges {
+		from := owner(e.From)
+		if from == "" {
+			continue
+		}
+		// Walk the unselected projects reached from the edge until a selected one
+		visited := make(map[string]bool)
+		queue := []Dependency{e}
+		for len(queue) > 0 {
+			d := queue[0]
+			queue = queue[1:]
+			if visited[d.To] {
+				continue
+			}
+			visited[d.To] = true
+
+			to := owner(d.To)
+			if to == "" {
+				queue = append(queue, out[d.To]...)
+				continue
+			}
+			key := [2]string{from, to}
+			if from == to || seen[key] {
+				continue
+			}
+			seen[key] = true
+			lifted = append(lifted, Dependency{From: from, To: to, Kind: d.Kind, Spec: d.Spec})
+		}
+	}
+	return lifted
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================
+// Manifest parsers
+// ============================================================
+
+// localDeps returns the local dependencies declared by the manifests in dir
+func localDeps(dir string) []localDep {
+	var deps []localDep
+	deps = append(deps, goReplaces(dir)...)
+	deps = append(deps, npmLocalDeps(dir)...)
+	deps = append(deps, cargoPathDeps(dir)...)
+	deps = append(deps, composerPathRepos(dir)...)
+	return deps
+}
+
+// resolveLocal resolves a manifest path relative to dir
+func resolveLocal(dir, path string) string {
+	path = filepath.FromSlash(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// isLocalPath reports whether a go.mod replacement is a directory rather
+// than a module path
+func isLocalPath(s string) bool {
+	return strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") || filepath.IsAbs(s) ||
+		strings.HasPrefix(s, `.\`) || strings.HasPrefix(s, `..\`)
+}
+
+// goReplaces reads the replace directives of go.mod pointing at directories
+func goReplaces(dir string) []localDep {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var deps []localDep
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		var directive string
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+			directive = line
+		case line == "replace (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "replace "):
+			directive = strings.TrimPrefix(line, "replace ")
+		default:
+			continue
+		}
+
+		_, target, ok := strings.Cut(directive, "=>")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(target)
+		if len(fields) == 0 || !isLocalPath(fields[0]) {
+			continue
+		}
+		deps = append(deps, localDep{kind: DepGoReplace, spec: strings.TrimSpace(directive), dir: resolveLocal(dir, fields[0])})
+	}
+	return deps
+}
+
+// npmPackage is the part of package.json describing dependencies
+type npmPackage struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+func readNpmPackage(dir string) *npmPackage {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg npmPackage
+	if json.Unmarshal(data, &pkg) != nil {
+		return nil
+	}
+	return &pkg
+}
+
+// npmName returns the package name in package.json, or ""
+func npmName(dir string) string {
+	if pkg := readNpmPackage(dir); pkg != nil {
+		return pkg.Name
+	}
+	return ""
+}
+
+// npmLocalDeps reads file:, link:, portal: and workspace: dependencies
+func npmLocalDeps(dir string) []localDep {
+	pkg := readNpmPackage(dir)
+	if pkg == nil {
+		return nil
+	}
+
+	var deps []localDep
+	for _, group := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies, pkg.OptionalDependencies} {
+		names := make([]string, 0, len(group))
+		for name := range group {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			version := group[name]
+			spec := name + "@" + version
+			if strings.HasPrefix(version, "workspace:") {
+				deps = append(deps, localDep{kind: DepNpmWorkspace, spec: spec, name: name})
+				continue
+			}
+			for _, prefix := range []string{"file:", "link:", "portal:"} {
+				if path, ok := strings.CutPrefix(version, prefix); ok && !strings.HasSuffix(path, ".tgz") {
+					deps = append(deps, localDep{kind: DepNpmFile, spec: spec, dir: resolveLocal(dir, path)})
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// cargoPathDeps reads dependencies with a path in Cargo.toml
+func cargoPathDeps(dir string) []localDep {
+	data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return nil
+	}
+	var cargo struct {
+		Dependencies      map[string]any `toml:"dependencies"`
+		DevDependencies   map[string]any `toml:"dev-dependencies"`
+		BuildDependencies map[string]any `toml:"build-dependencies"`
+		Workspace         struct {
+			Dependencies map[string]any `toml:"dependencies"`
+		} `toml:"workspace"`
+	}
+	if toml.Unmarshal(data, &cargo) != nil {
+		return nil
+	}
+
+	var deps []localDep
+	for _, group := range []map[string]any{cargo.Dependencies, cargo.DevDependencies, cargo.BuildDependencies, cargo.Workspace.Dependencies} {
+		names := make([]string, 0, len(group))
+		for name := range group {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			table, ok := group[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			if path, ok := table["path"].(string); ok {
+				deps = append(deps, localDep{kind: DepCargoPath, spec: fmt.Sprintf("%s = { path = %q }", name, path), dir: resolveLocal(dir, path)})
+			}
+		}
+	}
+	return deps
+}
+
+// composerPathRepos reads path repositories of composer.json. A repository
+// URL may be a glob such as ../packages/*.
+func composerPathRepos(dir string) []localDep {
+	data, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	if err != nil {
+		return nil
+	}
+	var composer struct {
+		Repositories json.RawMessage   `json:"repositories"`
+		Require      map[string]string `json:"require"`
+		RequireDev   map[string]string `json:"require-dev"`
+	}
+	if json.Unmarshal(data, &composer) != nil {
+		return nil
+	}
+
+	// repositories is a list, or an object keyed by repository name
+	type repo struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	}
+	var repos []repo
+	if json.Unmarshal(composer.Repositories, &repos) != nil {
+		var named map[string]repo
+		if json.Unmarshal(composer.Repositories, &named) != nil {
+			return nil
+		}
+		for _, r := range named {
+			repos = append(repos, r)
+		}
+	}
+
+	var deps []localDep
+	for _, r := range repos {
+		if r.Type != "path" || r.URL == "" {
+			continue
+		}
+		matches, _ := filepath.Glob(resolveLocal(dir, r.URL))
+		sort.Strings(matches)
+		for _, m := range matches {
+			// Only packages the project actually requires are dependencies
+			name := composerName(m)
+			if name != "" && composer.Require[name] == "" && composer.RequireDev[name] == "" {
+				continue
+			}
+			deps = append(deps, localDep{kind: DepComposerPath, spec: r.URL, dir: m})
+		}
+	}
+	return deps
+}
+
+// composerName returns the package name in composer.json, or ""
+func composerName(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	if err != nil {
+		return ""
+	}
+	var composer struct {
+		Name string `json:"name"`
+	}
+	json.Unmarshal(data, &composer)
+	return composer.Name
+}
+
+// ============================================================
+// Rendering
+// ============================================================
+
+// DOT renders the edges as a Graphviz digraph, naming projects with label
+func (g *Graph) DOT(label func(path string) string) string {
+	var b strings.Builder
+	b.WriteString("digraph projects {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", label(e.From), label(e.To), e.Kind)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the edges as a Mermaid flowchart, naming projects with label
+func (g *Graph) Mermaid(label func(path string) string) string {
+	ids := make(map[string]string)
+	id := func(path string) string {
+		if v, ok := ids[path]; ok {
+			return v
+		}
+		v := fmt.Sprintf("n%d", len(ids))
+		ids[path] = v
+		return fmt.Sprintf("%s[\"%s\"]", v, strings.ReplaceAll(label(path), `"`, "#quot;"))
+	}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", id(e.From), e.Kind, id(e.To))
+	}
+	return b.String()
+}
@@ -0,0 +1,252 @@
+package projects
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ==============================================================
+// Manifest Parser Tests
+// ==============================================================
+
+func TestLocalDeps(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []localDep
+	}{
+		{
+			name: "go_replace_single_and_block",
+			files: map[string]string{"app/go.mod": `module app
+
+replace example.com/lib => ../lib // local copy
+
+replace (
+	example.com/util v1.0.0 => ./internal/util
+	example.com/fork => github.com/me/fork v1.2.0
+)
+`},
+			want: []localDep{
+				{kind: DepGoReplace, spec: "example.com/lib => ../lib", dir: "lib"},
+				{kind: DepGoReplace, spec: "example.com/util v1.0.0 => ./internal/util", dir: "app/internal/util"},
+			},
+		},
+		{
+			name: "npm_file_and_workspace",
+			files: map[string]string{"app/package.json": `{
+  "dependencies": {"ui": "file:../ui", "react": "^18.0.0", "packed": "file:../packed.tgz"},
+  "devDependencies": {"@acme/config": "workspace:*"}
+}`},
+			want: []localDep{
+				{kind: DepNpmFile, spec: "ui@file:../ui", dir: "ui"},
+				{kind: DepNpmWorkspace, spec: "@acme/config@workspace:*", name: "@acme/config"},
+			},
+		},
+		{
+			name: "cargo_path",
+			files: map[string]string{"app/Cargo.toml": `[package]
+name = "app"
+
+[dependencies]
+serde = "1"
+core = { path = "../core" }
+
+[dev-dependencies]
+fixtures = { path = "tests/fixtures", version = "0.1" }
+`},
+			want: []localDep{
+				{kind: DepCargoPath, spec: `core = { path = "../core" }`, dir: "core"},
+				{kind: DepCargoPath, spec: `fixtures = { path = "tests/fixtures" }`, dir: "app/tests/fixtures"},
+			},
+		},
+		{
+			name: "composer_path_glob_required_only",
+			files: map[string]string{
+				"app/composer.json": `{
+  "repositories": [{"type": "path", "url": "../packages/*"}, {"type": "vcs", "url": "https://example.com/x.git"}],
+  "require": {"acme/auth": "@dev"}
+}`,
+				"packages/auth/composer.json":    `{"name": "acme/auth"}`,
+				"packages/billing/composer.json": `{"name": "acme/billing"}`,
+			},
+			want: []localDep{
+				{kind: DepComposerPath, spec: "../packages/*", dir: "packages/auth"},
+			},
+		},
+		{
+			name:  "no_manifest",
+			files: map[string]string{"app/README.md": "# app\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				createFile(t, root, name, content)
+			}
+
+			got := localDeps(filepath.Join(root, "app"))
+			for i := range got {
+				if got[i].dir != "" {
+					rel, _ := filepath.Rel(root, got[i].dir)
+					got[i].dir = filepath.ToSlash(rel)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("localDeps() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// Graph Tests
+// ==============================================================
+
+// testGraph builds web -> api -> lib, tools -> lib and a workspace member of
+// web depending on a sibling package by name
+func testGraph(t *testing.T) (*Graph, string) {
+	t.Helper()
+	root := t.TempDir()
+	createFile(t, root, "lib/go.mod", "module lib\n")
+	createFile(t, root, "api/go.mod", "module api\n\nreplace lib => ../lib\n")
+	createFile(t, root, "tools/go.mod", "module tools\n\nreplace lib => ../lib/sub\n")
+	createFile(t, root, "web/package.json", `{"dependencies": {"api-client": "file:../api/client"}}`)
+	createFile(t, root, "web/packages/ui/package.json", `{"name": "@web/ui", "dependencies": {"@web/theme": "workspace:^"}}`)
+	createFile(t, root, "web/packages/theme/package.json", `{"name": "@web/theme"}`)
+
+	list := []Project{
+		{Name: "lib", Path: filepath.Join(root, "lib")},
+		{Name: "api", Path: filepath.Join(root, "api")},
+		{Name: "tools", Path: filepath.Join(root, "tools")},
+		{Name: "web", Path: filepath.Join(root, "web"), Children: []Project{
+			{Name: "ui", Path: filepath.Join(root, "web/packages/ui")},
+			{Name: "theme", Path: filepath.Join(root, "web/packages/theme")},
+		}},
+	}
+	return BuildGraph(list), root
+}
+
+func TestBuildGraph(t *testing.T) {
+	g, root := testGraph(t)
+	rel := func(path string) string {
+		r, _ := filepath.Rel(root, path)
+		return filepath.ToSlash(r)
+	}
+
+	var got []string
+	for _, e := range g.Edges {
+		got = append(got, rel(e.From)+" -> "+rel(e.To)+" ("+e.Kind+")")
+	}
+	want := []string{
+		"api -> lib (go-replace)",
+		"tools -> lib (go-replace)",
+		"web -> api (npm-file)",
+		"web/packages/ui -> web/packages/theme (npm-workspace)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+
+	if len(g.Nodes) != 6 {
+		t.Errorf("len(Nodes) = %d, want 6 including workspace members", len(g.Nodes))
+	}
+	if g.Node(filepath.Join(root, "web/packages/ui")) == nil {
+		t.Error("Node() did not find a workspace member")
+	}
+	if deps := g.DependenciesOf(filepath.Join(root, "web")); len(deps) != 1 || deps[0].Spec != "api-client@file:../api/client" {
+		t.Errorf("DependenciesOf(web) = %+v", deps)
+	}
+}
+
+func TestBuildGraph_WorkspaceNamesStayInTheirWorkspace(t *testing.T) {
+	root := t.TempDir()
+	for _, repo := range []string{"shop", "blog"} {
+		createFile(t, root, repo+"/package.json", `{"workspaces": ["packages/*"]}`)
+		createFile(t, root, repo+"/packages/app/package.json", `{"name": "app-`+repo+`", "dependencies": {"ui": "workspace:*"}}`)
+		createFile(t, root, repo+"/packages/ui/package.json", `{"name": "ui"}`)
+	}
+	member := func(repo, name string) Project {
+		return Project{Name: name, Path: filepath.Join(root, repo, "packages", name)}
+	}
+	list := []Project{
+		{Name: "shop", Path: filepath.Join(root, "shop"), Children: []Project{member("shop", "app"), member("shop", "ui")}},
+		{Name: "blog", Path: filepath.Join(root, "blog"), Children: []Project{member("blog", "app"), member("blog", "ui")}},
+	}
+
+	g := BuildGraph(list)
+	var got []string
+	for _, e := range g.Edges {
+		from, _ := filepath.Rel(root, e.From)
+		to, _ := filepath.Rel(root, e.To)
+		got = append(got, filepath.ToSlash(from)+" -> "+filepath.ToSlash(to))
+	}
+	want := []string{"blog/packages/app -> blog/packages/ui", "shop/packages/app -> shop/packages/ui"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_Dependents(t *testing.T) {
+	g, root := testGraph(t)
+
+	direct, transitive := g.Dependents(filepath.Join(root, "lib"))
+	wantDirect := []string{filepath.Join(root, "api"), filepath.Join(root, "tools")}
+	if !reflect.DeepEqual(direct, wantDirect) {
+		t.Errorf("direct = %v, want %v", direct, wantDirect)
+	}
+	if want := []string{filepath.Join(root, "web")}; !reflect.DeepEqual(transitive, want) {
+		t.Errorf("transitive = %v, want %v", transitive, want)
+	}
+
+	if direct, _ := g.Dependents(filepath.Join(root, "web")); len(direct) != 0 {
+		t.Errorf("Dependents(web) = %v, want none", direct)
+	}
+}
+
+func TestGraph_Levels(t *testing.T) {
+	g, root := testGraph(t)
+	path := func(name string) string { return filepath.Join(root, name) }
+
+	levels, err := g.Levels([]string{path("web"), path("tools"), path("api"), path("lib")})
+	if err != nil {
+		t.Fatalf("Levels() error = %v", err)
+	}
+	want := [][]string{{path("lib")}, {path("api"), path("tools")}, {path("web")}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("Levels() = %v, want %v", levels, want)
+	}
+
+	// Without api, web still waits for lib through it
+	levels, _ = g.Levels([]string{path("web"), path("lib")})
+	if want := [][]string{{path("lib")}, {path("web")}}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("Levels(web, lib) = %v, want %v", levels, want)
+	}
+	levels, _ = g.Levels([]string{path("web"), path("tools")})
+	if len(levels) != 1 {
+		t.Errorf("Levels(web, tools) = %v, want a single level", levels)
+	}
+
+	g.Edges = append(g.Edges, Dependency{From: path("lib"), To: path("web"), Kind: DepGoReplace})
+	if _, err := g.Levels([]string{path("web"), path("api"), path("lib")}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Levels() error = %v, want a cycle error", err)
+	}
+}
+
+func TestGraph_Render(t *testing.T) {
+	g := &Graph{Edges: []Dependency{{From: "/p/web", To: "/p/api", Kind: DepNpmFile}}}
+	label := filepath.Base
+
+	dot := g.DOT(label)
+	if !strings.HasPrefix(dot, "digraph projects {") || !strings.Contains(dot, `"web" -> "api" [label="npm-file"];`) {
+		t.Errorf("DOT() = %q", dot)
+	}
+
+	mermaid := g.Mermaid(label)
+	if !strings.HasPrefix(mermaid, "graph LR\n") || !strings.Contains(mermaid, `n0["web"] -->|npm-file| n1["api"]`) {
+		t.Errorf("Mermaid() = %q", mermaid)
+	}
+}
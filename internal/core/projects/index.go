@@ -0,0 +1,416 @@
+package projects
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/badie/bdev/internal/core/config"
+)
+
+// indexVersion is bumped when the cached Project layout changes
+const indexVersion = 5
+
+// markerFiles are checked for changes besides the project directory itself.
+// Editing a file in place does not touch the directory mtime.
+var markerFiles = []string{
+	"package.json", "pnpm-workspace.yaml", "composer.json",
+	"go.mod", "go.work", "Cargo.toml",
+	"pyproject.toml", "requirements.txt", "manage.py",
+	"pom.xml", "build.gradle", "build.gradle.kts", "CMakeLists.txt",
+	".bdev.yml", ".bdev.yaml", "bdev.toml",
+	"Dockerfile", "compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml",
+	filepath.Join(".git", "HEAD"), filepath.Join(".git", "config"),
+}
+
+// ScanOptions configures ScanWith
+type ScanOptions struct {
+	MaxDepth  int    // levels below the root searched for projects, defaults to DefaultScanDepth
+	IndexFile string // index reused between scans, "" analyzes every project
+	MetaFile  string // tags, favorites and notes applied to the projects, "" skips them
+	Refresh   bool   // ignore cached entries and rebuild the index
+	Jobs      int    // concurrent analyses, defaults to the number of CPUs
+
+	// PackageManagers overrides the detected package manager by project name
+	PackageManagers map[string]string
+}
+
+// index is the on-disk cache of analyzed projects, keyed by path
+type index struct {
+	Version int                    `json:"version"`
+	Types   string                 `json:"types"` // fingerprint of the type definitions used
+	Entries map[string]*indexEntry `json:"entries"`
+}
+
+// indexEntry is a cached project with the fingerprints it was analyzed at,
+// one per directory (the project and its workspace members)
+type indexEntry struct {
+	Project Project          `json:"project"`
+	Stamps  map[string]int64 `json:"stamps"`
+}
+
+// Scan finds projects under rootDir, up to paths.scan_depth levels deep,
+// reusing the project index for directories that did not change
+func Scan(rootDir string) ([]Project, error) {
+	return ScanWith(rootDir, DefaultScanOptions())
+}
+
+// Refresh rescans rootDir, analyzing every project again and rewriting the index
+func Refresh(rootDir string) ([]Project, error) {
+	opts := DefaultScanOptions()
+	opts.Refresh = true
+	return ScanWith(rootDir, opts)
+}
+
+// DefaultScanOptions returns the scan depth, index and metadata files and the
+// package manager overrides from the config
+func DefaultScanOptions() ScanOptions {
+	cfg := config.Get()
+	opts := ScanOptions{
+		MaxDepth:        cfg.Paths.ScanDepth,
+		IndexFile:       cfg.ProjectIndexFile(),
+		MetaFile:        cfg.ProjectMetaFile(),
+		PackageManagers: make(map[string]string),
+	}
+	for name, settings := range cfg.Projects {
+		if settings.PackageManager != "" {
+			opts.PackageManagers[name] = settings.PackageManager
+		}
+	}
+	return opts
+}
+
+// ScanDepth finds projects under rootDir without using the index
+func ScanDepth(rootDir string, maxDepth int) ([]Project, error) {
+	return ScanWith(rootDir, ScanOptions{MaxDepth: maxDepth})
+}
+
+// ScanWith finds projects under rootDir. Directories that are not projects
+// themselves (such as clients/) are descended into up to MaxDepth levels,
+// and scanning stops at the first project root. Workspace members are
+// attached to their root as Children rather than listed separately.
+// Top-level directories without any project are listed as unknown projects.
+//
+// Projects are analyzed in parallel. With an IndexFile, projects whose
+// directory and marker files are unchanged are read from the index instead.
+func ScanWith(rootDir string, opts ScanOptions) ([]Project, error) {
+	if _, err := os.ReadDir(rootDir); err != nil {
+		return nil, err
+	}
+	return scanRoots([]Root{{Path: rootDir}}, nil, opts), nil
+}
+
+// scanRoots finds the projects of every root, plus the given project
+// directories, analyzing each directory once. Roots that cannot be read are
+// skipped and their index entries kept.
+func scanRoots(roots []Root, extra []string, opts ScanOptions) []Project {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultScanDepth
+	}
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	var dirs, scanned []string
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		depth := root.ScanDepth
+		if depth <= 0 {
+			depth = opts.MaxDepth
+		}
+		found, err := discover(root.Path, depth, root.Ignore)
+		if err != nil {
+			continue
+		}
+		scanned = append(scanned, root.Path)
+		for _, dir := range found {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	for _, dir := range extra {
+		if seen[dir] || withinAny(dir, dirs) {
+			continue
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	idx := newIndex()
+	if opts.IndexFile != "" && !opts.Refresh {
+		idx = loadIndex(opts.IndexFile)
+	}
+
+	entries := make([]*indexEntry, len(dirs))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+
+	for i, dir := range dirs {
+		if e := idx.Entries[dir]; e != nil && e.fresh() {
+			// Project files are not cached, they may carry secrets in env
+			e.Project.File, _ = LoadProjectFile(dir)
+			for j := range e.Project.Children {
+				e.Project.Children[j].File, _ = LoadProjectFile(e.Project.Children[j].Path)
+			}
+			entries[i] = e
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, dir string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			entries[i] = analyzeEntry(dir)
+		}(i, dir)
+	}
+	wg.Wait()
+
+	projects := make([]Project, 0, len(entries))
+	for _, e := range entries {
+		if e != nil {
+			p := e.Project
+			p.Children = append([]Project(nil), p.Children...)
+			projects = append(projects, p)
+		}
+	}
+
+	if opts.IndexFile != "" {
+		// Keep entries of other roots and of roots that could not be read,
+		// drop the ones that disappeared from the scanned ones
+		for path := range idx.Entries {
+			if withinAny(path, scanned) || contains(extra, path) {
+				delete(idx.Entries, path)
+			}
+		}
+		for _, e := range entries {
+			if e != nil {
+				idx.Entries[e.Project.Path] = e
+			}
+		}
+		// The index is only a cache, a failed write costs a slower next scan
+		_ = idx.save(opts.IndexFile)
+	}
+
+	// Overrides and metadata are applied after saving, they are not part of
+	// the index and take effect without a refresh
+	if len(opts.PackageManagers) > 0 {
+		for i := range projects {
+			projects[i].overridePackageManagers(opts.PackageManagers)
+		}
+	}
+	if opts.MetaFile != "" {
+		if meta, err := LoadMeta(opts.MetaFile); err == nil && meta.Apply(projects) {
+			_ = meta.Save()
+		}
+	}
+
+	// Favorites first, then by last modified (most recent first)
+	sort.SliceStable(projects, func(i, j int) bool {
+		if projects[i].Favorite != projects[j].Favorite {
+			return projects[i].Favorite
+		}
+		return projects[i].LastModified.After(projects[j].LastModified)
+	})
+
+	return projects
+}
+
+// overridePackageManagers applies the overrides to the project and its members
+func (p *Project) overridePackageManagers(overrides map[string]string) {
+	p.OverridePackageManager(overrides[p.Name])
+	for i := range p.Children {
+		p.Children[i].overridePackageManagers(overrides)
+	}
+}
+
+// discover returns the project directories under rootDir, skipping the
+// directories matching an ignore glob
+func discover(rootDir string, maxDepth int, ignore []string) ([]string, error) {
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if !scannable(entry) {
+			continue
+		}
+		path := filepath.Join(rootDir, entry.Name())
+		if ignored(rootDir, path, ignore) {
+			continue
+		}
+		if found := discoverDir(rootDir, path, 1, maxDepth, ignore); len(found) > 0 {
+			dirs = append(dirs, found...)
+		} else {
+			dirs = append(dirs, path)
+		}
+	}
+	return dirs, nil
+}
+
+// discoverDir returns dir if it is a project root, or the project roots below it
+func discoverDir(rootDir, dir string, depth, maxDepth int, ignore []string) []string {
+	if IsProjectRoot(dir) {
+		return []string{dir}
+	}
+	if depth >= maxDepth {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if scannable(entry) && !ignored(rootDir, path, ignore) {
+			dirs = append(dirs, discoverDir(rootDir, path, depth+1, maxDepth, ignore)...)
+		}
+	}
+	return dirs
+}
+
+// ignored reports whether the name of dir, or its path relative to rootDir,
+// matches any of the globs
+func ignored(rootDir, dir string, globs []string) bool {
+	if len(globs) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(rootDir, dir)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, glob := range globs {
+		glob = strings.Trim(filepath.ToSlash(glob), "/")
+		if ok, _ := path.Match(glob, filepath.Base(dir)); ok {
+			return true
+		}
+		if ok, _ := path.Match(glob, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeEntry analyzes a project and its workspace members
+func analyzeEntry(dir string) *indexEntry {
+	project := Analyze(dir)
+	if project == nil {
+		return nil
+	}
+	if IsProjectRoot(dir) {
+		project.loadWorkspace()
+	}
+
+	e := &indexEntry{Project: *project, Stamps: map[string]int64{dir: fingerprint(dir)}}
+	for _, c := range project.Children {
+		e.Stamps[c.Path] = fingerprint(c.Path)
+	}
+	return e
+}
+
+// fresh reports whether none of the entry's directories changed since it was analyzed
+func (e *indexEntry) fresh() bool {
+	if len(e.Stamps) == 0 {
+		return false
+	}
+	for dir, stamp := range e.Stamps {
+		if fingerprint(dir) != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// fingerprint is the latest modification time of a directory and its
+// marker files, or 0 if the directory is gone
+func fingerprint(dir string) int64 {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return 0
+	}
+	latest := info.ModTime().UnixNano()
+	for _, name := range markerFiles {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			latest = max(latest, info.ModTime().UnixNano())
+		}
+	}
+	return latest
+}
+
+// newIndex returns an empty index for the current type definitions
+func newIndex() *index {
+	return &index{Version: indexVersion, Types: loadedTypes().fingerprint, Entries: make(map[string]*indexEntry)}
+}
+
+// loadIndex reads the index, returning an empty one if it is missing,
+// unreadable, from another version or built with other type definitions
+func loadIndex(path string) *index {
+	empty := newIndex()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return empty
+	}
+	var idx index
+	if json.Unmarshal(data, &idx) != nil || idx.Version != indexVersion || idx.Types != empty.Types || idx.Entries == nil {
+		return empty
+	}
+	return &idx
+}
+
+// save writes the index atomically so concurrent bdev processes never read a partial file
+func (idx *index) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".projects-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// withinAny reports whether path is any of dirs or below one of them
+func withinAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if Within(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Within reports whether path is dir or below it
+func Within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,238 @@
+package projects
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestMain points the config at a temporary home so Scan never writes
+// the project index of the user running the tests
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "bdev-home-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("USERPROFILE", home)
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// ==============================================================
+// Index Tests
+// ==============================================================
+
+// tamperIndex rewrites the cached framework of every entry, so a scan
+// returning it proves the entry was reused
+func tamperIndex(t *testing.T, path, framework string) {
+	t.Helper()
+	idx := loadIndex(path)
+	if len(idx.Entries) == 0 {
+		t.Fatal("index is empty")
+	}
+	for _, e := range idx.Entries {
+		e.Project.Framework = framework
+	}
+	if err := idx.save(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func frameworks(list []Project) map[string]string {
+	got := make(map[string]string)
+	for _, p := range list {
+		got[p.Name] = p.Framework
+	}
+	return got
+}
+
+func TestScanWith_Index(t *testing.T) {
+	root := t.TempDir()
+	indexFile := filepath.Join(t.TempDir(), "cache", "projects.json")
+	createFile(t, root, "web/package.json", `{"dependencies": {"react": "18.2.0"}}`)
+	createFile(t, root, "api/go.mod", "module api\n")
+	opts := ScanOptions{MaxDepth: 2, IndexFile: indexFile}
+
+	if _, err := ScanWith(root, opts); err != nil {
+		t.Fatalf("ScanWith() error = %v", err)
+	}
+	if _, err := os.Stat(indexFile); err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+
+	t.Run("unchanged_projects_are_cached", func(t *testing.T) {
+		tamperIndex(t, indexFile, "cached")
+		list, _ := ScanWith(root, opts)
+		if got := frameworks(list); got["web"] != "cached" || got["api"] != "cached" {
+			t.Errorf("frameworks = %v, want both cached", got)
+		}
+	})
+
+	t.Run("marker_change_invalidates", func(t *testing.T) {
+		tamperIndex(t, indexFile, "cached")
+		later := time.Now().Add(time.Minute)
+		os.Chtimes(filepath.Join(root, "web", "package.json"), later, later)
+
+		got := frameworks(mustScan(t, root, opts))
+		if got["web"] != "react 18.2.0" {
+			t.Errorf("web framework = %q, want it analyzed again", got["web"])
+		}
+		if got["api"] != "cached" {
+			t.Errorf("api framework = %q, want it still cached", got["api"])
+		}
+	})
+
+	t.Run("refresh_ignores_index", func(t *testing.T) {
+		tamperIndex(t, indexFile, "cached")
+		refresh := opts
+		refresh.Refresh = true
+		if got := frameworks(mustScan(t, root, refresh)); got["api"] == "cached" {
+			t.Errorf("frameworks = %v, want every project analyzed", got)
+		}
+	})
+
+	t.Run("removed_projects_are_dropped", func(t *testing.T) {
+		os.RemoveAll(filepath.Join(root, "api"))
+		mustScan(t, root, opts)
+
+		idx := loadIndex(indexFile)
+		if _, ok := idx.Entries[filepath.Join(root, "api")]; ok {
+			t.Error("index still has the removed project")
+		}
+		if _, ok := idx.Entries[filepath.Join(root, "web")]; !ok {
+			t.Error("index lost a remaining project")
+		}
+	})
+}
+
+func TestScanWith_IndexComposeEdit(t *testing.T) {
+	root := t.TempDir()
+	indexFile := filepath.Join(t.TempDir(), "projects.json")
+	createFile(t, root, "shop/go.mod", "module shop\n")
+	createFile(t, root, "shop/compose.yaml", "services:\n  db:\n    image: postgres:16\n")
+	opts := ScanOptions{MaxDepth: 2, IndexFile: indexFile}
+
+	services := func() []string {
+		var names []string
+		for _, svc := range mustScan(t, root, opts)[0].Containers.Services {
+			names = append(names, svc.Name)
+		}
+		return names
+	}
+	if got := services(); !reflect.DeepEqual(got, []string{"db"}) {
+		t.Fatalf("services = %v, want [db]", got)
+	}
+
+	// Editing the Compose file in place leaves the directory mtime alone
+	createFile(t, root, "shop/compose.yaml", "services:\n  db:\n    image: postgres:16\n  cache:\n    image: redis:7\n")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(root, "shop", "compose.yaml"), later, later)
+	if got := services(); len(got) != 2 {
+		t.Errorf("services = %v, want the edited file analyzed again", got)
+	}
+}
+
+func TestScanWith_IndexKeepsOtherRoots(t *testing.T) {
+	indexFile := filepath.Join(t.TempDir(), "projects.json")
+	work, personal := t.TempDir(), t.TempDir()
+	createFile(t, work, "a/go.mod", "module a\n")
+	createFile(t, personal, "b/go.mod", "module b\n")
+	opts := ScanOptions{IndexFile: indexFile}
+
+	mustScan(t, work, opts)
+	mustScan(t, personal, opts)
+
+	if n := len(loadIndex(indexFile).Entries); n != 2 {
+		t.Errorf("index has %d entries, want 2", n)
+	}
+}
+
+func TestScanWith_PackageManagerOverrides(t *testing.T) {
+	root := t.TempDir()
+	indexFile := filepath.Join(t.TempDir(), "projects.json")
+	createFile(t, root, "web/package.json", `{"workspaces": ["packages/*"]}`)
+	createFile(t, root, "web/package-lock.json", "{}")
+	createFile(t, root, "web/packages/ui/package.json", `{"name": "ui"}`)
+
+	pms := func(opts ScanOptions) map[string]PackageManager {
+		got := make(map[string]PackageManager)
+		for _, p := range Flatten(mustScan(t, root, opts)) {
+			got[p.Name] = p.PackageManager
+		}
+		return got
+	}
+
+	opts := ScanOptions{MaxDepth: 2, IndexFile: indexFile}
+	if got := pms(opts); got["web"] != PMNpm {
+		t.Errorf("detected = %v, want npm", got)
+	}
+
+	// Overrides apply to cached entries and to workspace members, and are
+	// not written to the index
+	opts.PackageManagers = map[string]string{"web": "pnpm", "ui": "bun@1.1.0", "api": "unknown"}
+	if got := pms(opts); got["web"] != PMPnpm || got["ui"] != PMBun {
+		t.Errorf("overridden = %v, want web pnpm and ui bun", got)
+	}
+	opts.PackageManagers = nil
+	if got := pms(opts); got["web"] != PMNpm || got["ui"] != PMNone {
+		t.Errorf("without overrides = %v, want the detected ones", got)
+	}
+}
+
+func TestLoadIndex_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"corrupt.json": "{not json",
+		"version.json": `{"version": 999, "entries": {}}`,
+		"missing.json": "",
+	}
+
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		if content != "" {
+			os.WriteFile(path, []byte(content), 0o644)
+		}
+		idx := loadIndex(path)
+		if idx.Version != indexVersion || len(idx.Entries) != 0 {
+			t.Errorf("loadIndex(%s) = %+v, want an empty index", name, idx)
+		}
+	}
+}
+
+func TestIndex_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.json")
+	idx := newIndex()
+	idx.Entries["/p/mono"] = &indexEntry{
+		Project: Project{Name: "mono", Workspace: WorkspacePnpm, Children: []Project{{Name: "ui"}}},
+		Stamps:  map[string]int64{"/p/mono": 1},
+	}
+	if err := idx.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("index is not valid JSON: %v", err)
+	}
+
+	got := loadIndex(path).Entries["/p/mono"]
+	if got == nil || got.Project.Workspace != WorkspacePnpm || len(got.Project.Children) != 1 {
+		t.Errorf("loadIndex() = %+v, want the saved workspace", got)
+	}
+}
+
+func mustScan(t *testing.T, root string, opts ScanOptions) []Project {
+	t.Helper()
+	list, err := ScanWith(root, opts)
+	if err != nil {
+		t.Fatalf("ScanWith() error = %v", err)
+	}
+	return list
+}
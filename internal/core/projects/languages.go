@@ -0,0 +1,174 @@
+package projects
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Language describes how to count the lines of a programming language
+type Language struct {
+	Name       string
+	LineStarts []string    // line comment markers
+	Blocks     [][2]string // block comment delimiters
+}
+
+var (
+	cStyle    = Language{LineStarts: []string{"//"}, Blocks: [][2]string{{"/*", "*/"}}}
+	hashStyle = Language{LineStarts: []string{"#"}}
+	htmlStyle = Language{Blocks: [][2]string{{"<!--", "-->"}}}
+)
+
+// lang returns a copy of style named name
+func lang(name string, style Language) *Language {
+	style.Name = name
+	return &style
+}
+
+// languagesByExt maps lowercase file extensions to languages
+var languagesByExt = map[string]*Language{
+	".go":     lang("Go", cStyle),
+	".js":     lang("JavaScript", cStyle),
+	".mjs":    lang("JavaScript", cStyle),
+	".cjs":    lang("JavaScript", cStyle),
+	".jsx":    lang("JSX", cStyle),
+	".ts":     lang("TypeScript", cStyle),
+	".mts":    lang("TypeScript", cStyle),
+	".cts":    lang("TypeScript", cStyle),
+	".tsx":    lang("TSX", cStyle),
+	".java":   lang("Java", cStyle),
+	".kt":     lang("Kotlin", cStyle),
+	".kts":    lang("Kotlin", cStyle),
+	".rs":     lang("Rust", cStyle),
+	".c":      lang("C", cStyle),
+	".h":      lang("C Header", cStyle),
+	".cc":     lang("C++", cStyle),
+	".cpp":    lang("C++", cStyle),
+	".cxx":    lang("C++", cStyle),
+	".hh":     lang("C++ Header", cStyle),
+	".hpp":    lang("C++ Header", cStyle),
+	".cs":     lang("C#", cStyle),
+	".swift":  lang("Swift", cStyle),
+	".dart":   lang("Dart", cStyle),
+	".scala":  lang("Scala", cStyle),
+	".css":    lang("CSS", Language{Blocks: [][2]string{{"/*", "*/"}}}),
+	".scss":   lang("SCSS", cStyle),
+	".less":   lang("Less", cStyle),
+	".php":    lang("PHP", Language{LineStarts: []string{"//", "#"}, Blocks: [][2]string{{"/*", "*/"}}}),
+	".py":     lang("Python", Language{LineStarts: []string{"#"}, Blocks: [][2]string{{`"""`, `"""`}, {"'''", "'''"}}}),
+	".rb":     lang("Ruby", Language{LineStarts: []string{"#"}, Blocks: [][2]string{{"=begin", "=end"}}}),
+	".ex":     lang("Elixir", hashStyle),
+	".exs":    lang("Elixir", hashStyle),
+	".sh":     lang("Shell", hashStyle),
+	".bash":   lang("Shell", hashStyle),
+	".zsh":    lang("Shell", hashStyle),
+	".ps1":    lang("PowerShell", Language{LineStarts: []string{"#"}, Blocks: [][2]string{{"<#", "#>"}}}),
+	".yml":    lang("YAML", hashStyle),
+	".yaml":   lang("YAML", hashStyle),
+	".toml":   lang("TOML", hashStyle),
+	".json":   lang("JSON", Language{}),
+	".sql":    lang("SQL", Language{LineStarts: []string{"--"}, Blocks: [][2]string{{"/*", "*/"}}}),
+	".lua":    lang("Lua", Language{LineStarts: []string{"--"}, Blocks: [][2]string{{"--[[", "]]"}}}),
+	".html":   lang("HTML", htmlStyle),
+	".htm":    lang("HTML", htmlStyle),
+	".xml":    lang("XML", htmlStyle),
+	".vue":    lang("Vue", Language{LineStarts: []string{"//"}, Blocks: [][2]string{{"<!--", "-->"}, {"/*", "*/"}}}),
+	".svelte": lang("Svelte", Language{LineStarts: []string{"//"}, Blocks: [][2]string{{"<!--", "-->"}, {"/*", "*/"}}}),
+	".astro":  lang("Astro", Language{LineStarts: []string{"//"}, Blocks: [][2]string{{"<!--", "-->"}, {"/*", "*/"}}}),
+	".md":     lang("Markdown", Language{}),
+	".mdx":    lang("Markdown", Language{}),
+}
+
+// languagesByName maps file names without a telling extension to languages
+var languagesByName = map[string]*Language{
+	"Dockerfile":  lang("Dockerfile", hashStyle),
+	"Makefile":    lang("Makefile", hashStyle),
+	"makefile":    lang("Makefile", hashStyle),
+	"Justfile":    lang("Makefile", hashStyle),
+	"Gemfile":     lang("Ruby", hashStyle),
+	"Rakefile":    lang("Ruby", hashStyle),
+	"Jenkinsfile": lang("Groovy", cStyle),
+}
+
+// LanguageOf returns the language of a file, or nil if it is not source code
+func LanguageOf(path string) *Language {
+	name := filepath.Base(path)
+	if l, ok := languagesByName[name]; ok {
+		return l
+	}
+	if strings.HasPrefix(name, "Dockerfile.") {
+		return languagesByName["Dockerfile"]
+	}
+	return languagesByExt[strings.ToLower(filepath.Ext(name))]
+}
+
+// CountLines counts code, comment and blank lines. Comment markers inside
+// strings are not recognized, as in cloc and tokei's fast mode.
+func (l *Language) CountLines(content string) (code, comments, blanks int) {
+	if content == "" {
+		return 0, 0, 0
+	}
+	var closing string // end delimiter of the open block comment
+
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		line = strings.TrimSpace(line)
+
+		if closing != "" {
+			comments++
+			if i := strings.Index(line, closing); i >= 0 {
+				rest := strings.TrimSpace(line[i+len(closing):])
+				closing = ""
+				if rest != "" {
+					// Code after the comment ends makes the line code
+					comments--
+					code++
+				}
+			}
+			continue
+		}
+
+		if line == "" {
+			blanks++
+			continue
+		}
+		// Blocks first: Lua's "--[[" also starts with the line marker "--"
+		if start, end, ok := l.blockStart(line); ok {
+			rest := line[len(start):]
+			if i := strings.Index(rest, end); i >= 0 {
+				if strings.TrimSpace(rest[i+len(end):]) == "" {
+					comments++
+				} else {
+					code++
+				}
+				continue
+			}
+			comments++
+			closing = end
+			continue
+		}
+		if l.isLineComment(line) {
+			comments++
+			continue
+		}
+		code++
+	}
+
+	return code, comments, blanks
+}
+
+func (l *Language) isLineComment(line string) bool {
+	for _, m := range l.LineStarts {
+		if strings.HasPrefix(line, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Language) blockStart(line string) (string, string, bool) {
+	for _, b := range l.Blocks {
+		if strings.HasPrefix(line, b[0]) {
+			return b[0], b[1], true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,229 @@
+package projects
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Meta is user-assigned metadata of a project
+type Meta struct {
+	Tags     []string `json:"tags,omitempty"`
+	Favorite bool     `json:"favorite,omitempty"`
+	Note     string   `json:"note,omitempty"`
+
+	// Remote is the git remote URL and RepoPath the slash-separated project
+	// path within the repository ("" for the top-level), used together to
+	// find the entry again after the project directory is renamed or moved.
+	// Workspace members share their remote, RepoPath tells them apart.
+	Remote   string `json:"remote,omitempty"`
+	RepoPath string `json:"repo_path,omitempty"`
+}
+
+// empty reports whether the metadata carries nothing worth storing
+func (m *Meta) empty() bool {
+	return len(m.Tags) == 0 && !m.Favorite && m.Note == ""
+}
+
+// MetaStore holds project metadata keyed by project path
+type MetaStore struct {
+	Projects map[string]*Meta `json:"projects"`
+
+	path string
+}
+
+// LoadMeta reads the metadata file, returning an empty store if it does not exist
+func LoadMeta(path string) (*MetaStore, error) {
+	s := &MetaStore{Projects: make(map[string]*Meta), path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Projects == nil {
+		s.Projects = make(map[string]*Meta)
+	}
+	return s, nil
+}
+
+// Save writes the store, dropping entries without metadata
+func (s *MetaStore) Save() error {
+	for path, m := range s.Projects {
+		if m.empty() {
+			delete(s.Projects, path)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// Lookup returns the metadata of a project, or nil if it has none. An entry
+// whose path no longer exists but whose remote and path within the
+// repository match the project is moved to the project's new path; Lookup
+// reports whether that happened.
+func (s *MetaStore) Lookup(p *Project) (*Meta, bool) {
+	if m, ok := s.Projects[p.Path]; ok {
+		if p.Remote != "" && (m.Remote != p.Remote || m.RepoPath != repoPath(p.Path)) {
+			m.Remote, m.RepoPath = p.Remote, repoPath(p.Path)
+			return m, true
+		}
+		return m, false
+	}
+	if p.Remote == "" {
+		return nil, false
+	}
+
+	// Sorted so the same entry is picked on every run
+	paths := make([]string, 0, len(s.Projects))
+	for path := range s.Projects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	rel := repoPath(p.Path)
+	for _, path := range paths {
+		m := s.Projects[path]
+		if m.Remote != p.Remote || m.RepoPath != rel {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			// Another checkout of the same repository
+			continue
+		}
+		delete(s.Projects, path)
+		s.Projects[p.Path] = m
+		return m, true
+	}
+	return nil, false
+}
+
+// For returns the metadata of a project, creating an entry if needed
+func (s *MetaStore) For(p *Project) *Meta {
+	if m, _ := s.Lookup(p); m != nil {
+		return m
+	}
+	m := &Meta{Remote: p.Remote}
+	if p.Remote != "" {
+		m.RepoPath = repoPath(p.Path)
+	}
+	s.Projects[p.Path] = m
+	return m
+}
+
+// repoPath returns the slash-separated path of dir below the top-level of
+// its git repository, "" when dir is the top-level or not in a repository
+func repoPath(dir string) string {
+	for top := dir; ; {
+		if _, err := os.Stat(filepath.Join(top, ".git")); err == nil {
+			rel, err := filepath.Rel(top, dir)
+			if err != nil || rel == "." {
+				return ""
+			}
+			return filepath.ToSlash(rel)
+		}
+		parent := filepath.Dir(top)
+		if parent == top {
+			return ""
+		}
+		top = parent
+	}
+}
+
+// Apply sets Tags, Favorite and Note on the projects and their workspace
+// members. It reports whether renamed entries were moved, so the caller
+// can save the store.
+func (s *MetaStore) Apply(list []Project) bool {
+	moved := false
+	for i := range list {
+		p := &list[i]
+		if m, ok := s.Lookup(p); m != nil {
+			p.Tags = m.Tags
+			p.Favorite = m.Favorite
+			p.Note = m.Note
+			moved = moved || ok
+		}
+		if s.Apply(p.Children) {
+			moved = true
+		}
+	}
+	return moved
+}
+
+// Tags returns every tag in use with the number of projects carrying it
+func (s *MetaStore) Tags() map[string]int {
+	counts := make(map[string]int)
+	for _, m := range s.Projects {
+		for _, t := range m.Tags {
+			counts[t]++
+		}
+	}
+	return counts
+}
+
+// AddTags adds tags, ignoring duplicates, and keeps them sorted
+func (m *Meta) AddTags(tags ...string) {
+	for _, t := range tags {
+		t = NormalizeTag(t)
+		if t != "" && !m.HasTag(t) {
+			m.Tags = append(m.Tags, t)
+		}
+	}
+	sort.Strings(m.Tags)
+}
+
+// RemoveTags removes tags, reporting whether any was present
+func (m *Meta) RemoveTags(tags ...string) bool {
+	removed := false
+	for _, t := range tags {
+		t = NormalizeTag(t)
+		for i, have := range m.Tags {
+			if have == t {
+				m.Tags = append(m.Tags[:i], m.Tags[i+1:]...)
+				removed = true
+				break
+			}
+		}
+	}
+	return removed
+}
+
+// HasTag reports whether the metadata carries a tag
+func (m *Meta) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag reports whether the project carries a tag
+func (p *Project) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTag lowercases a tag and replaces spaces with dashes
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
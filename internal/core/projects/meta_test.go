@@ -0,0 +1,174 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// ==============================================================
+// MetaStore Tests
+// ==============================================================
+
+func TestMetaStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects-meta.json")
+
+	store, err := LoadMeta(path)
+	if err != nil {
+		t.Fatalf("LoadMeta() on a missing file error = %v", err)
+	}
+
+	app := &Project{Path: "/p/app", Remote: "git@github.com:acme/app.git"}
+	m := store.For(app)
+	m.AddTags("Client X", "oss", "oss")
+	m.Favorite = true
+	store.For(&Project{Path: "/p/empty"}) // no metadata, not saved
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadMeta(path)
+	if err != nil {
+		t.Fatalf("LoadMeta() error = %v", err)
+	}
+	if len(loaded.Projects) != 1 {
+		t.Fatalf("loaded %d entries, want 1", len(loaded.Projects))
+	}
+	got := loaded.Projects["/p/app"]
+	if !reflect.DeepEqual(got.Tags, []string{"client-x", "oss"}) || !got.Favorite || got.Remote != app.Remote {
+		t.Errorf("loaded entry = %+v", got)
+	}
+}
+
+func TestMetaStore_LookupAfterRename(t *testing.T) {
+	root := t.TempDir()
+	oldPath := filepath.Join(root, "old-name")
+	newPath := filepath.Join(root, "new-name")
+	os.Mkdir(newPath, 0o755)
+
+	store := &MetaStore{Projects: map[string]*Meta{
+		oldPath: {Tags: []string{"oss"}, Remote: "https://github.com/acme/app.git"},
+	}}
+
+	t.Run("other_remote_does_not_match", func(t *testing.T) {
+		m, moved := store.Lookup(&Project{Path: newPath, Remote: "https://github.com/acme/other.git"})
+		if m != nil || moved {
+			t.Errorf("Lookup() = %+v, %v, want no match", m, moved)
+		}
+	})
+
+	t.Run("same_remote_moves_entry", func(t *testing.T) {
+		m, moved := store.Lookup(&Project{Path: newPath, Remote: "https://github.com/acme/app.git"})
+		if m == nil || !moved {
+			t.Fatalf("Lookup() = %+v, %v, want the renamed entry", m, moved)
+		}
+		if _, ok := store.Projects[oldPath]; ok {
+			t.Error("old path is still in the store")
+		}
+		if store.Projects[newPath] != m {
+			t.Error("entry was not moved to the new path")
+		}
+	})
+
+	t.Run("existing_checkout_keeps_entry", func(t *testing.T) {
+		clone := filepath.Join(root, "clone")
+		m, moved := store.Lookup(&Project{Path: clone, Remote: "https://github.com/acme/app.git"})
+		if m != nil || moved {
+			t.Errorf("Lookup() = %+v, %v, want the entry left with its checkout", m, moved)
+		}
+	})
+}
+
+func TestMetaStore_ApplyAfterMonorepoRename(t *testing.T) {
+	root := t.TempDir()
+	const remote = "https://github.com/acme/mono.git"
+	mono := filepath.Join(root, "mono-renamed")
+	createFile(t, mono, ".git/HEAD", "ref: refs/heads/main\n")
+	createFile(t, mono, "packages/ui/package.json", "{}")
+	createFile(t, mono, "packages/api/package.json", "{}")
+
+	// Entries of the members only, recorded before the rename
+	old := filepath.Join(root, "mono")
+	store := &MetaStore{Projects: map[string]*Meta{
+		filepath.Join(old, "packages", "ui"):  {Tags: []string{"design"}, Remote: remote, RepoPath: "packages/ui"},
+		filepath.Join(old, "packages", "api"): {Note: "port 4000", Remote: remote, RepoPath: "packages/api"},
+	}}
+	list := []Project{{Path: mono, Remote: remote, Children: []Project{
+		{Path: filepath.Join(mono, "packages", "api"), Remote: remote},
+		{Path: filepath.Join(mono, "packages", "ui"), Remote: remote},
+	}}}
+
+	if !store.Apply(list) {
+		t.Error("Apply() = false, want the entries moved")
+	}
+	if len(list[0].Tags) > 0 || list[0].Note != "" {
+		t.Errorf("workspace root = %+v, want no member metadata", list[0])
+	}
+	api, ui := list[0].Children[0], list[0].Children[1]
+	if api.Note != "port 4000" || len(api.Tags) > 0 {
+		t.Errorf("api = %+v, want its own note", api)
+	}
+	if !ui.HasTag("design") || ui.Note != "" {
+		t.Errorf("ui = %+v, want its own tags", ui)
+	}
+}
+
+func TestMeta_RemoveTags(t *testing.T) {
+	m := &Meta{Tags: []string{"archived", "client-x", "oss"}}
+
+	if !m.RemoveTags("Client X", "missing") {
+		t.Error("RemoveTags() = false, want true")
+	}
+	if m.RemoveTags("missing") {
+		t.Error("RemoveTags() = true for an absent tag")
+	}
+	if !reflect.DeepEqual(m.Tags, []string{"archived", "oss"}) {
+		t.Errorf("Tags = %v", m.Tags)
+	}
+}
+
+func TestMetaStore_Apply(t *testing.T) {
+	store := &MetaStore{Projects: map[string]*Meta{
+		"/p/mono":    {Favorite: true},
+		"/p/mono/ui": {Tags: []string{"design"}, Note: "storybook on 6006"},
+	}}
+	list := []Project{{Path: "/p/mono", Children: []Project{{Path: "/p/mono/ui"}}}, {Path: "/p/other"}}
+
+	store.Apply(list)
+
+	if !list[0].Favorite {
+		t.Error("mono should be a favorite")
+	}
+	ui := list[0].Children[0]
+	if !ui.HasTag("Design") || ui.Note != "storybook on 6006" {
+		t.Errorf("member = %+v, want its tags and note", ui)
+	}
+	if list[1].Favorite || len(list[1].Tags) > 0 {
+		t.Errorf("other = %+v, want no metadata", list[1])
+	}
+}
+
+func TestScanWith_FavoritesFirst(t *testing.T) {
+	root := t.TempDir()
+	metaFile := filepath.Join(t.TempDir(), "projects-meta.json")
+	createFile(t, root, "alpha/go.mod", "module alpha\n")
+	createFile(t, root, "beta/go.mod", "module beta\n")
+
+	store, _ := LoadMeta(metaFile)
+	store.For(&Project{Path: filepath.Join(root, "alpha")}).Favorite = true
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Make beta the most recently modified
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(root, "beta"), later, later)
+
+	list := mustScan(t, root, ScanOptions{MetaFile: metaFile})
+	if len(list) != 2 || list[0].Name != "alpha" || !list[0].Favorite {
+		t.Errorf("Scan() = %v first, want the favorite alpha", list[0].Name)
+	}
+}
@@ -0,0 +1,191 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackageManager identifies the tool used to install dependencies and run scripts
+type PackageManager string
+
+const (
+	PMNone   PackageManager = ""
+	PMNpm    PackageManager = "npm"
+	PMPnpm   PackageManager = "pnpm"
+	PMYarn   PackageManager = "yarn"
+	PMBun    PackageManager = "bun"
+	PMPip    PackageManager = "pip"
+	PMPoetry PackageManager = "poetry"
+	PMUv     PackageManager = "uv"
+	PMPipenv PackageManager = "pipenv"
+)
+
+// Package ecosystems, see TypeDef.Ecosystem
+const (
+	EcosystemJS     = "js"
+	EcosystemPython = "python"
+)
+
+// lockfiles maps lockfiles to the package manager that writes them, in priority order
+var lockfiles = []struct {
+	file string
+	pm   PackageManager
+}{
+	{"pnpm-lock.yaml", PMPnpm},
+	{"yarn.lock", PMYarn},
+	{"bun.lockb", PMBun},
+	{"bun.lock", PMBun},
+	{"package-lock.json", PMNpm},
+	{"poetry.lock", PMPoetry},
+	{"uv.lock", PMUv},
+	{"Pipfile.lock", PMPipenv},
+	{"Pipfile", PMPipenv},
+}
+
+// ParsePackageManager converts a name such as "pnpm" or "pnpm@8.15.0" to a PackageManager
+func ParsePackageManager(s string) PackageManager {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if idx := strings.Index(name, "@"); idx > 0 {
+		name = name[:idx]
+	}
+
+	switch PackageManager(name) {
+	case PMNpm, PMPnpm, PMYarn, PMBun, PMPip, PMPoetry, PMUv, PMPipenv:
+		return PackageManager(name)
+	}
+	return PMNone
+}
+
+// DetectPackageManager detects the package manager from package.json and
+// lockfiles. A non-empty ecosystem ignores the package managers of others,
+// such as the package-lock.json of a Django project's frontend.
+func DetectPackageManager(path string, pkg *PackageJSON, ecosystem string) PackageManager {
+	fits := func(pm PackageManager) bool {
+		return ecosystem == "" || pm.Ecosystem() == ecosystem
+	}
+
+	// The packageManager field (corepack) is authoritative when present
+	if pkg != nil && pkg.PackageManager != "" {
+		if pm := ParsePackageManager(pkg.PackageManager); pm != PMNone && fits(pm) {
+			return pm
+		}
+	}
+
+	for _, lf := range lockfiles {
+		if !fits(lf.pm) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(path, lf.file)); err == nil {
+			return lf.pm
+		}
+	}
+
+	// pyproject.toml without a lockfile may still declare poetry
+	if !fits(PMPoetry) {
+		return PMNone
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "pyproject.toml")); err == nil {
+		if strings.Contains(string(data), "[tool.poetry]") {
+			return PMPoetry
+		}
+	}
+
+	return PMNone
+}
+
+// IsJS reports whether the package manager belongs to the JavaScript ecosystem
+func (pm PackageManager) IsJS() bool {
+	return pm.Ecosystem() == EcosystemJS
+}
+
+// Ecosystem returns EcosystemJS or EcosystemPython, or "" for PMNone
+func (pm PackageManager) Ecosystem() string {
+	switch pm {
+	case PMNpm, PMPnpm, PMYarn, PMBun:
+		return EcosystemJS
+	case PMPip, PMPoetry, PMUv, PMPipenv:
+		return EcosystemPython
+	}
+	return ""
+}
+
+// RunScript returns the command that runs a package.json script
+func (pm PackageManager) RunScript(script string) (string, []string) {
+	switch pm {
+	case PMYarn:
+		return "yarn", []string{script}
+	case PMPnpm, PMBun:
+		return string(pm), []string{"run", script}
+	}
+
+	// npm has shortcuts for these lifecycle scripts
+	if script == "start" || script == "test" {
+		return "npm", []string{script}
+	}
+	return "npm", []string{"run", script}
+}
+
+// ScriptArgs returns the separator used to forward extra arguments to a script
+func (pm PackageManager) ScriptArgs(args ...string) []string {
+	if pm == PMNpm || pm == PMNone {
+		return append([]string{"--"}, args...)
+	}
+	return args
+}
+
+// Exec returns the command that runs a tool through the package manager
+func (pm PackageManager) Exec(bin string, args ...string) (string, []string) {
+	switch pm {
+	case PMPnpm:
+		return "pnpm", append([]string{"exec", bin}, args...)
+	case PMYarn:
+		return "yarn", append([]string{bin}, args...)
+	case PMBun:
+		return "bunx", append([]string{bin}, args...)
+	case PMNpm:
+		return "npx", append([]string{bin}, args...)
+	case PMPoetry, PMUv, PMPipenv:
+		return string(pm), append([]string{"run", bin}, args...)
+	}
+	return bin, args
+}
+
+// Install returns the command that installs project dependencies
+func (pm PackageManager) Install() (string, []string) {
+	switch pm {
+	case PMNpm, PMPnpm, PMYarn, PMBun, PMPoetry, PMPipenv:
+		return string(pm), []string{"install"}
+	case PMUv:
+		return "uv", []string{"sync"}
+	case PMPip:
+		return "pip", []string{"install", "-r", "requirements.txt"}
+	}
+	return "", nil
+}
+
+// GetPackageManager returns the detected package manager, or the default of
+// the type ecosystem when none was detected or it belongs to another one
+func (p *Project) GetPackageManager() PackageManager {
+	ecosystem := p.Type.Ecosystem()
+	if p.PackageManager != PMNone && (ecosystem == "" || p.PackageManager.Ecosystem() == ecosystem) {
+		return p.PackageManager
+	}
+
+	switch ecosystem {
+	case EcosystemJS:
+		return PMNpm
+	case EcosystemPython:
+		return PMPip
+	}
+	return PMNone
+}
+
+// OverridePackageManager replaces the detected package manager with the one
+// named by override, such as the package_manager project setting. Empty or
+// unknown names keep the detected one.
+func (p *Project) OverridePackageManager(override string) {
+	if pm := ParsePackageManager(override); pm != PMNone {
+		p.PackageManager = pm
+	}
+}
@@ -0,0 +1,316 @@
+package projects
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ==============================================================
+// ParsePackageManager Tests
+// ==============================================================
+
+func TestParsePackageManager(t *testing.T) {
+	tests := []struct {
+		input string
+		want  PackageManager
+	}{
+		{"pnpm", PMPnpm},
+		{"pnpm@8.15.0", PMPnpm},
+		{"yarn@4.1.0", PMYarn},
+		{"Bun", PMBun},
+		{"npm", PMNpm},
+		{"poetry", PMPoetry},
+		{"uv", PMUv},
+		{"pipenv", PMPipenv},
+		{"maven", PMNone},
+		{"", PMNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := ParsePackageManager(tt.input)
+			if got != tt.want {
+				t.Errorf("ParsePackageManager(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// DetectPackageManager Tests
+// ==============================================================
+
+func TestDetectPackageManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		ecosystem string
+		want      PackageManager
+	}{
+		{
+			name:  "pnpm_lockfile",
+			files: map[string]string{"package.json": "{}", "pnpm-lock.yaml": ""},
+			want:  PMPnpm,
+		},
+		{
+			name:  "yarn_lockfile",
+			files: map[string]string{"package.json": "{}", "yarn.lock": ""},
+			want:  PMYarn,
+		},
+		{
+			name:  "bun_lockfile",
+			files: map[string]string{"package.json": "{}", "bun.lockb": ""},
+			want:  PMBun,
+		},
+		{
+			name:  "npm_lockfile",
+			files: map[string]string{"package.json": "{}", "package-lock.json": "{}"},
+			want:  PMNpm,
+		},
+		{
+			name: "package_manager_field_wins",
+			files: map[string]string{
+				"package.json":      `{"packageManager": "pnpm@8.15.0"}`,
+				"package-lock.json": "{}",
+			},
+			want: PMPnpm,
+		},
+		{
+			name:  "poetry_lockfile",
+			files: map[string]string{"pyproject.toml": "", "poetry.lock": ""},
+			want:  PMPoetry,
+		},
+		{
+			name:  "poetry_pyproject_only",
+			files: map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"app\"\n"},
+			want:  PMPoetry,
+		},
+		{
+			name:  "uv_lockfile",
+			files: map[string]string{"pyproject.toml": "", "uv.lock": ""},
+			want:  PMUv,
+		},
+		{
+			name:  "pipenv_lockfile",
+			files: map[string]string{"Pipfile.lock": "{}"},
+			want:  PMPipenv,
+		},
+		{
+			name:  "no_lockfile",
+			files: map[string]string{"requirements.txt": "flask\n"},
+			want:  PMNone,
+		},
+		{
+			name:      "python_ignores_js_lockfile",
+			files:     map[string]string{"package-lock.json": "{}", "poetry.lock": ""},
+			ecosystem: EcosystemPython,
+			want:      PMPoetry,
+		},
+		{
+			name:      "python_without_python_lockfile",
+			files:     map[string]string{"package.json": `{"packageManager": "yarn@4.0.0"}`, "yarn.lock": ""},
+			ecosystem: EcosystemPython,
+			want:      PMNone,
+		},
+		{
+			name:      "js_ignores_python_lockfile",
+			files:     map[string]string{"package.json": "{}", "uv.lock": "", "pyproject.toml": "[tool.poetry]\n"},
+			ecosystem: EcosystemJS,
+			want:      PMNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				createFile(t, dir, name, content)
+			}
+
+			got := DetectPackageManager(dir, ReadPackageJSON(dir), tt.ecosystem)
+			if got != tt.want {
+				t.Errorf("DetectPackageManager() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// Command Builder Tests
+// ==============================================================
+
+func TestPackageManager_RunScript(t *testing.T) {
+	tests := []struct {
+		pm       PackageManager
+		script   string
+		wantBin  string
+		wantArgs []string
+	}{
+		{PMNpm, "dev", "npm", []string{"run", "dev"}},
+		{PMNpm, "start", "npm", []string{"start"}},
+		{PMNpm, "test", "npm", []string{"test"}},
+		{PMPnpm, "dev", "pnpm", []string{"run", "dev"}},
+		{PMYarn, "dev", "yarn", []string{"dev"}},
+		{PMBun, "test", "bun", []string{"run", "test"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.pm)+"_"+tt.script, func(t *testing.T) {
+			bin, args := tt.pm.RunScript(tt.script)
+			if bin != tt.wantBin || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("RunScript(%q) = %s %v, want %s %v", tt.script, bin, args, tt.wantBin, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestPackageManager_Exec(t *testing.T) {
+	tests := []struct {
+		pm       PackageManager
+		wantBin  string
+		wantArgs []string
+	}{
+		{PMNpm, "npx", []string{"eslint", "."}},
+		{PMPnpm, "pnpm", []string{"exec", "eslint", "."}},
+		{PMYarn, "yarn", []string{"eslint", "."}},
+		{PMBun, "bunx", []string{"eslint", "."}},
+		{PMPoetry, "poetry", []string{"run", "eslint", "."}},
+		{PMUv, "uv", []string{"run", "eslint", "."}},
+		{PMPip, "eslint", []string{"."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.pm), func(t *testing.T) {
+			bin, args := tt.pm.Exec("eslint", ".")
+			if bin != tt.wantBin || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Exec() = %s %v, want %s %v", bin, args, tt.wantBin, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestProject_GetPackageManager(t *testing.T) {
+	tests := []struct {
+		name    string
+		project Project
+		want    PackageManager
+	}{
+		{"explicit", Project{Type: TypeNode, PackageManager: PMPnpm}, PMPnpm},
+		{"node_default", Project{Type: TypeNode}, PMNpm},
+		{"next_default", Project{Type: TypeNextJS}, PMNpm},
+		{"python_default", Project{Type: TypePython}, PMPip},
+		{"go_none", Project{Type: TypeGo}, PMNone},
+		{"django_ignores_npm", Project{Type: TypeDjango, PackageManager: PMNpm}, PMPip},
+		{"python_keeps_uv", Project{Type: TypePython, PackageManager: PMUv}, PMUv},
+		{"react_ignores_poetry", Project{Type: TypeReact, PackageManager: PMPoetry}, PMNpm},
+		{"laravel_keeps_yarn", Project{Type: TypeLaravel, PackageManager: PMYarn}, PMYarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.project.GetPackageManager(); got != tt.want {
+				t.Errorf("GetPackageManager() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze_DjangoWithPackageLock(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "manage.py", "")
+	createFile(t, dir, "package.json", `{"devDependencies": {"tailwindcss": "^3.4.0"}}`)
+	createFile(t, dir, "package-lock.json", "{}")
+
+	p := Analyze(dir)
+	if p == nil || p.Type != TypeDjango {
+		t.Fatalf("Analyze() = %+v, want a Django project", p)
+	}
+	if pm := p.GetPackageManager(); pm != PMPip {
+		t.Errorf("GetPackageManager() = %q, want pip", pm)
+	}
+	if bin, args := p.GetTestCommand(); bin != "python" || !reflect.DeepEqual(args, []string{"manage.py", "test"}) {
+		t.Errorf("GetTestCommand() = %s %v, want python manage.py test", bin, args)
+	}
+}
+
+func TestProject_OverridePackageManager(t *testing.T) {
+	tests := []struct {
+		override string
+		want     PackageManager
+	}{
+		{"", PMYarn},
+		{"pnpm", PMPnpm},
+		{"bun@1.1.0", PMBun},
+		{"unknown", PMYarn},
+	}
+
+	for _, tt := range tests {
+		p := Project{Type: TypeNode, PackageManager: PMYarn}
+		p.OverridePackageManager(tt.override)
+		if p.PackageManager != tt.want {
+			t.Errorf("OverridePackageManager(%q) = %q, want %q", tt.override, p.PackageManager, tt.want)
+		}
+	}
+}
+
+func TestProject_Commands_WithPackageManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  Project
+		get      func(p *Project) (string, []string)
+		wantBin  string
+		wantArgs []string
+	}{
+		{
+			name:     "pnpm_dev",
+			project:  Project{Type: TypeNextJS, PackageManager: PMPnpm, Scripts: map[string]string{"dev": "next dev"}},
+			get:      (*Project).GetStartCommand,
+			wantBin:  "pnpm",
+			wantArgs: []string{"run", "dev"},
+		},
+		{
+			name:     "bun_test",
+			project:  Project{Type: TypeNode, PackageManager: PMBun, Scripts: map[string]string{"test": "bun test"}},
+			get:      (*Project).GetTestCommand,
+			wantBin:  "bun",
+			wantArgs: []string{"run", "test"},
+		},
+		{
+			name:     "poetry_start",
+			project:  Project{Type: TypePython, PackageManager: PMPoetry},
+			get:      (*Project).GetStartCommand,
+			wantBin:  "poetry",
+			wantArgs: []string{"run", "python", "main.py"},
+		},
+		{
+			name:     "uv_test",
+			project:  Project{Type: TypePython, PackageManager: PMUv},
+			get:      (*Project).GetTestCommand,
+			wantBin:  "uv",
+			wantArgs: []string{"run", "pytest"},
+		},
+		{
+			name:     "poetry_build",
+			project:  Project{Type: TypePython, PackageManager: PMPoetry},
+			get:      (*Project).GetBuildCommand,
+			wantBin:  "poetry",
+			wantArgs: []string{"build"},
+		},
+		{
+			name:     "pipenv_django",
+			project:  Project{Type: TypeDjango, PackageManager: PMPipenv},
+			get:      (*Project).GetStartCommand,
+			wantBin:  "pipenv",
+			wantArgs: []string{"run", "python", "manage.py", "runserver"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin, args := tt.get(&tt.project)
+			if bin != tt.wantBin || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got %s %v, want %s %v", bin, args, tt.wantBin, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package projects
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileNames are the project-level config files, in lookup order
+var ProjectFileNames = []string{".bdev.yml", ".bdev.yaml", "bdev.toml"}
+
+// ProjectFile represents a project-level .bdev.yml or bdev.toml
+type ProjectFile struct {
+	// Command overrides, each a shell command line
+	Start   string `yaml:"start,omitempty" toml:"start"`
+	Test    string `yaml:"test,omitempty" toml:"test"`
+	Build   string `yaml:"build,omitempty" toml:"build"`
+	Lint    string `yaml:"lint,omitempty" toml:"lint"`
+	Install string `yaml:"install,omitempty" toml:"install"`
+
+	Tasks    map[string]TaskSpec    `yaml:"tasks,omitempty" toml:"tasks"`
+	Services map[string]ServiceSpec `yaml:"services,omitempty" toml:"services"`
+}
+
+// TaskSpec declares a named command run with `bdev run <task>`.
+// A plain string is shorthand for the command alone.
+type TaskSpec struct {
+	Command     string            `yaml:"command" toml:"command"`
+	Description string            `yaml:"description,omitempty" toml:"description"`
+	Cwd         string            `yaml:"cwd,omitempty" toml:"cwd"`
+	Env         map[string]string `yaml:"env,omitempty" toml:"env"`
+}
+
+// ServiceSpec declares a long-running process started by `bdev start`
+type ServiceSpec struct {
+	Command string            `yaml:"command" toml:"command"`
+	Cwd     string            `yaml:"cwd,omitempty" toml:"cwd"`
+	Env     map[string]string `yaml:"env,omitempty" toml:"env"`
+	Restart string            `yaml:"restart,omitempty" toml:"restart"` // no, on-failure, always
+}
+
+// UnmarshalYAML accepts either a command string or a full task mapping
+func (t *TaskSpec) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		t.Command = value.Value
+		return nil
+	}
+	type plain TaskSpec
+	return value.Decode((*plain)(t))
+}
+
+// UnmarshalText handles the string shorthand in bdev.toml
+func (t *TaskSpec) UnmarshalText(text []byte) error {
+	t.Command = string(text)
+	return nil
+}
+
+// LoadProjectFile reads the project-level config, returning nil if there is none
+func LoadProjectFile(path string) (*ProjectFile, error) {
+	for _, name := range ProjectFileNames {
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			continue
+		}
+
+		var pf ProjectFile
+		if filepath.Ext(name) == ".toml" {
+			err = toml.Unmarshal(data, &pf)
+		} else {
+			err = yaml.Unmarshal(data, &pf)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		for task, spec := range pf.Tasks {
+			if spec.Command == "" {
+				return nil, fmt.Errorf("invalid %s: task '%s' has no command", name, task)
+			}
+		}
+		return &pf, nil
+	}
+
+	return nil, nil
+}
+
+// TaskNames returns the declared task names, sorted
+func (pf *ProjectFile) TaskNames() []string {
+	if pf == nil {
+		return nil
+	}
+	names := make([]string, 0, len(pf.Tasks))
+	for name := range pf.Tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ShellCommand wraps a command line in the platform shell
+func ShellCommand(line string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "powershell", []string{"-Command", line}
+	}
+	return "sh", []string{"-c", line}
+}
+
+// ShellQuote quotes an argument so the platform shell passes it through verbatim
+func ShellQuote(arg string) string {
+	if runtime.GOOS == "windows" {
+		return "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -0,0 +1,173 @@
+package projects
+
+import (
+	"strings"
+	"testing"
+)
+
+// ==============================================================
+// LoadProjectFile Tests
+// ==============================================================
+
+func TestLoadProjectFile(t *testing.T) {
+	t.Run("services", func(t *testing.T) {
+		dir := t.TempDir()
+		createFile(t, dir, ".bdev.yml", "services:\n  web:\n    command: npm run dev\n    restart: always\n")
+
+		pf, err := LoadProjectFile(dir)
+		if err != nil {
+			t.Fatalf("LoadProjectFile() error = %v", err)
+		}
+		if pf == nil {
+			t.Fatal("LoadProjectFile() returned nil")
+		}
+		if got := pf.Services["web"].Command; got != "npm run dev" {
+			t.Errorf("Services[web].Command = %q, want 'npm run dev'", got)
+		}
+		if got := pf.Services["web"].Restart; got != "always" {
+			t.Errorf("Services[web].Restart = %q, want 'always'", got)
+		}
+	})
+
+	t.Run("yaml_extension", func(t *testing.T) {
+		dir := t.TempDir()
+		createFile(t, dir, ".bdev.yaml", "services:\n  api:\n    command: go run .\n")
+
+		pf, err := LoadProjectFile(dir)
+		if err != nil || pf == nil {
+			t.Fatalf("LoadProjectFile() = %v, %v", pf, err)
+		}
+		if _, ok := pf.Services["api"]; !ok {
+			t.Error("Services should contain api")
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		pf, err := LoadProjectFile(t.TempDir())
+		if err != nil || pf != nil {
+			t.Errorf("LoadProjectFile() = %v, %v, want nil, nil", pf, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		createFile(t, dir, ".bdev.yml", "services: [unterminated\n")
+
+		if _, err := LoadProjectFile(dir); err == nil {
+			t.Error("LoadProjectFile() should fail on invalid YAML")
+		}
+	})
+}
+
+func TestLoadProjectFile_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, ".bdev.yml", `start: make dev
+test: make test
+install: make deps
+tasks:
+  migrate: php artisan migrate
+  seed:
+    command: ./scripts/seed.sh
+    description: Seed the database
+    cwd: backend
+    env:
+      APP_ENV: local
+`)
+
+	pf, err := LoadProjectFile(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectFile() error = %v", err)
+	}
+	if pf.Start != "make dev" || pf.Test != "make test" || pf.Install != "make deps" {
+		t.Errorf("overrides = %q, %q, %q", pf.Start, pf.Test, pf.Install)
+	}
+	if pf.Build != "" {
+		t.Errorf("Build = %q, want empty", pf.Build)
+	}
+
+	if got := pf.Tasks["migrate"].Command; got != "php artisan migrate" {
+		t.Errorf("Tasks[migrate].Command = %q, want shorthand command", got)
+	}
+	seed := pf.Tasks["seed"]
+	if seed.Command != "./scripts/seed.sh" || seed.Cwd != "backend" || seed.Env["APP_ENV"] != "local" {
+		t.Errorf("Tasks[seed] = %+v", seed)
+	}
+	if got := pf.TaskNames(); len(got) != 2 || got[0] != "migrate" || got[1] != "seed" {
+		t.Errorf("TaskNames() = %v, want [migrate seed]", got)
+	}
+}
+
+func TestLoadProjectFile_TOML(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "bdev.toml", `build = "make release"
+
+[tasks]
+lint = "golangci-lint run"
+
+[tasks.docs]
+command = "mkdocs serve"
+description = "Serve the docs"
+
+[services.web]
+command = "go run ./cmd/web"
+`)
+
+	pf, err := LoadProjectFile(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectFile() error = %v", err)
+	}
+	if pf.Build != "make release" {
+		t.Errorf("Build = %q, want 'make release'", pf.Build)
+	}
+	if got := pf.Tasks["lint"].Command; got != "golangci-lint run" {
+		t.Errorf("Tasks[lint].Command = %q", got)
+	}
+	if got := pf.Tasks["docs"].Description; got != "Serve the docs" {
+		t.Errorf("Tasks[docs].Description = %q", got)
+	}
+	if got := pf.Services["web"].Command; got != "go run ./cmd/web" {
+		t.Errorf("Services[web].Command = %q", got)
+	}
+}
+
+func TestLoadProjectFile_TaskWithoutCommand(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, ".bdev.yml", "tasks:\n  broken:\n    description: nothing to run\n")
+
+	if _, err := LoadProjectFile(dir); err == nil {
+		t.Error("LoadProjectFile() should fail for a task without a command")
+	}
+}
+
+// ==============================================================
+// Override Tests
+// ==============================================================
+
+func TestProject_CommandOverrides(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "go.mod", "module example.com/app\n")
+	createFile(t, dir, ".bdev.yml", "start: air\nbuild: make build\n")
+
+	project := Analyze(dir)
+	if project == nil || project.File == nil {
+		t.Fatal("Analyze() should load the project file")
+	}
+
+	wantBin, wantArgs := ShellCommand("air")
+	bin, args := project.GetStartCommand()
+	if bin != wantBin || strings.Join(args, " ") != strings.Join(wantArgs, " ") {
+		t.Errorf("GetStartCommand() = %s %v, want %s %v", bin, args, wantBin, wantArgs)
+	}
+
+	// Commands without an override keep the detected default
+	if bin, args := project.GetTestCommand(); bin != "go" || args[0] != "test" {
+		t.Errorf("GetTestCommand() = %s %v, want go test", bin, args)
+	}
+}
+
+func TestProject_Overrides_NoFile(t *testing.T) {
+	p := &Project{}
+	if pf := p.Overrides(); pf == nil || pf.Start != "" || len(pf.Tasks) != 0 {
+		t.Errorf("Overrides() = %+v, want empty project file", pf)
+	}
+}
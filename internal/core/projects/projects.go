@@ -11,58 +11,62 @@ import (
 	"github.com/badie/bdev/internal/core/git"
 )
 
-// ProjectType represents the type of project
-type ProjectType int
+// ProjectType identifies a project type definition, see TypeDef. The
+// constants are the built-in types; users can add more without recompiling.
+type ProjectType string
 
+// Built-in project types
 const (
-	TypeUnknown ProjectType = iota
-	TypeNextJS
-	TypeReact
-	TypeVue
-	TypeAngular
-	TypeNode
-	TypeGo
-	TypePython
-	TypeRust
-	TypeLaravel
-	TypeDjango
-	TypeStatic
-	TypeNuxt
-	TypeSvelte
-	TypeAstro
-	TypeJava
-	TypePHP
-	TypeCpp
+	TypeUnknown ProjectType = ""
+	TypeNextJS  ProjectType = "nextjs"
+	TypeReact   ProjectType = "react"
+	TypeVue     ProjectType = "vue"
+	TypeAngular ProjectType = "angular"
+	TypeNode    ProjectType = "node"
+	TypeGo      ProjectType = "go"
+	TypePython  ProjectType = "python"
+	TypeRust    ProjectType = "rust"
+	TypeLaravel ProjectType = "laravel"
+	TypeDjango  ProjectType = "django"
+	TypeStatic  ProjectType = "static"
+	TypeNuxt    ProjectType = "nuxt"
+	TypeSvelte  ProjectType = "svelte"
+	TypeAstro   ProjectType = "astro"
+	TypeJava    ProjectType = "java"
+	TypePHP     ProjectType = "php"
+	TypeCpp     ProjectType = "cpp"
+	TypeDocker  ProjectType = "docker"
 )
 
-// String returns the string representation of ProjectType
+// String returns the display name of the type
 func (t ProjectType) String() string {
-	names := []string{
-		"Unknown", "Next.js", "React", "Vue", "Angular",
-		"Node.js", "Go", "Python", "Rust", "Laravel",
-		"Django", "Static", "Nuxt", "Svelte", "Astro",
-		"Java", "PHP", "C++",
+	if def := LookupType(t); def != nil {
+		return def.Name
 	}
-	if int(t) < len(names) {
-		return names[t]
+	if t == TypeUnknown {
+		return "Unknown"
 	}
-	return "Unknown"
+	// A type whose definition was removed since it was cached
+	return string(t)
 }
 
 // Icon returns a text abbreviation for the project type
 func (t ProjectType) Icon() string {
-	abbrevs := []string{
-		"[?]", "[NX]", "[RC]", "[VU]", "[NG]",
-		"[JS]", "[GO]", "[PY]", "[RS]", "[LV]",
-		"[DJ]", "[--]", "[NU]", "[SV]", "[AS]",
-		"[JV]", "[PH]", "[C+]",
-	}
-	if int(t) < len(abbrevs) {
-		return abbrevs[t]
+	if def := LookupType(t); def != nil {
+		return def.Icon
 	}
 	return "[?]"
 }
 
+// Ecosystem returns the package ecosystem of the type, EcosystemJS,
+// EcosystemPython or "" when its package manager is not restricted
+func (t ProjectType) Ecosystem() string {
+	if def := LookupType(t); def != nil {
+		return def.Ecosystem
+	}
+	return ""
+}
+
 // Project represents a development project
 type Project struct {
 	Name         string            `json:"name"`
@@ -73,7 +77,29 @@ type Project struct {
 	Size         int64             `json:"size"`
 	HasGit       bool              `json:"has_git"`
 	GitBranch    string            `json:"git_branch,omitempty"`
+	Remote       string            `json:"remote,omitempty"`
 	Scripts      map[string]string `json:"scripts,omitempty"`
+
+	PackageManager PackageManager `json:"package_manager,omitempty"`
+
+	// Workspace is the monorepo kind (npm, pnpm, go, cargo) of a workspace
+	// root, whose members are listed in Children
+	Workspace string    `json:"workspace,omitempty"`
+	Children  []Project `json:"children,omitempty"`
+
+	// User metadata from the metadata file, set by Scan
+	Tags     []string `json:"tags,omitempty"`
+	Favorite bool     `json:"favorite,omitempty"`
+	Note     string   `json:"note,omitempty"`
+
+	// Containers is the Dockerfile and Compose setup, nil if there is none
+	Containers *Containers `json:"containers,omitempty"`
+
+	// Stats are the sizes and line counts cached by EnsureStats, nil until measured
+	Stats *Stats `json:"stats,omitempty"`
+
+	// File is the project-level .bdev.yml / bdev.toml, nil if there is none
+	File *ProjectFile `json:"-"`
 }
 
 // PackageJSON represents a Node.js package.json
@@ -83,6 +109,7 @@ type PackageJSON struct {
 	Scripts         map[string]string `json:"scripts"`
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
+	PackageManager  string            `json:"packageManager"`
 }
 
 // GoMod represents a Go module
@@ -92,51 +119,56 @@ type GoMod struct {
 	Require []string
 }
 
-// Scan scans a directory for projects
-func Scan(rootDir string) ([]Project, error) {
-	projects := make([]Project, 0)
+// DefaultScanDepth is how many directory levels Scan descends when the
+// config does not set paths.scan_depth
+const DefaultScanDepth = 2
 
-	entries, err := os.ReadDir(rootDir)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		// Skip hidden directories
-		if strings.HasPrefix(entry.Name(), ".") {
-			continue
-		}
+// skipDirs are directories that never contain projects
+var skipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "__pycache__": true,
+	".git": true, "build": true, "dist": true,
+}
 
-		// Skip common non-project directories
-		skip := []string{"node_modules", "vendor", "__pycache__", ".git", "build", "dist"}
-		shouldSkip := false
-		for _, s := range skip {
-			if entry.Name() == s {
-				shouldSkip = true
-				break
-			}
-		}
-		if shouldSkip {
-			continue
+// loadWorkspace fills Workspace and Children for a monorepo root
+func (p *Project) loadWorkspace() {
+	kind, members := WorkspaceMembers(p.Path)
+	if kind == "" {
+		return
+	}
+	p.Workspace = kind
+	for _, dir := range members {
+		if member := Analyze(dir); member != nil {
+			p.Children = append(p.Children, *member)
 		}
+	}
+}
 
-		projectPath := filepath.Join(rootDir, entry.Name())
-		project := Analyze(projectPath)
-		if project != nil {
-			projects = append(projects, *project)
+// IsProjectRoot reports whether dir is a project rather than a directory
+// grouping projects: it has a known type, a git repository or a .bdev.yml
+func IsProjectRoot(dir string) bool {
+	if Detect(dir) != TypeUnknown {
+		return true
+	}
+	for _, name := range append([]string{".git"}, ProjectFileNames...) {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
 		}
 	}
+	return false
+}
 
-	// Sort by last modified (most recent first)
-	sort.Slice(projects, func(i, j int) bool {
-		return projects[i].LastModified.After(projects[j].LastModified)
-	})
+// Flatten returns the projects followed by their workspace members
+func Flatten(list []Project) []Project {
+	flat := make([]Project, 0, len(list))
+	for _, p := range list {
+		flat = append(flat, p)
+		flat = append(flat, Flatten(p.Children)...)
+	}
+	return flat
+}
 
-	return projects, nil
+func scannable(entry os.DirEntry) bool {
+	return entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && !skipDirs[entry.Name()]
 }
 
 // Analyze analyzes a directory and returns project info
@@ -155,121 +187,62 @@ func Analyze(path string) *Project {
 	}
 
 	// Get framework from package.json
-	if pkg := ReadPackageJSON(path); pkg != nil {
+	pkg := ReadPackageJSON(path)
+	if pkg != nil {
 		project.Framework = DetectFramework(pkg)
 		project.Scripts = pkg.Scripts
 	}
 
+	project.Containers = DetectContainers(path)
+
+	// Project-level overrides and tasks. Errors are reported by the runner,
+	// detection should still work with a broken file.
+	project.File, _ = LoadProjectFile(path)
+
+	// Plain PHP projects run their composer scripts
+	if project.Type == TypePHP {
+		project.Scripts = ReadComposerScripts(path)
+	}
+
+	// Detect package manager; config overrides are applied by the caller,
+	// see OverridePackageManager
+	project.PackageManager = DetectPackageManager(path, pkg, project.Type.Ecosystem())
+
 	// Get git branch
 	if project.HasGit {
 		if repo, err := git.Open(path); err == nil {
 			project.GitBranch = repo.CurrentBranch()
+			project.Remote = repo.Remote()
 		}
 	}
 
 	return project
 }
-func Detect(path string) ProjectType {
-	// 1. PRIMARY: Check for specific config files
-	checks := []struct {
-		file    string
-		ptype   ProjectType
-		content func(string) ProjectType
-	}{
-		{"next.config.js", TypeNextJS, nil},
-		{"next.config.ts", TypeNextJS, nil},
-		{"next.config.mjs", TypeNextJS, nil},
-		{"nuxt.config.js", TypeNuxt, nil},
-		{"nuxt.config.ts", TypeNuxt, nil},
-		{"svelte.config.js", TypeSvelte, nil},
-		{"astro.config.mjs", TypeAstro, nil},
-		{"vue.config.js", TypeVue, nil},
-		{"angular.json", TypeAngular, nil},
-		{"bun.lockb", TypeNode, nil}, // Bun project, usually JS/TS
-		{"go.mod", TypeGo, nil},
-		{"Cargo.toml", TypeRust, nil},
-		{"composer.json", TypePHP, detectComposer}, // Generic PHP or Laravel
-		{"pyproject.toml", TypePython, nil},
-		{"requirements.txt", TypePython, nil},
-		{"manage.py", TypeDjango, nil},
-		{"pom.xml", TypeJava, nil},
-		{"build.gradle", TypeJava, nil},
-		{"CMakeLists.txt", TypeCpp, nil},
-		{"Makefile", TypeCpp, nil}, // Heuristic, usually C/C++ or Go
-		{"index.html", TypeStatic, nil},
-	}
 
-	for _, check := range checks {
-		filePath := filepath.Join(path, check.file)
-		if _, err := os.Stat(filePath); err == nil {
-			if check.content != nil {
-				return check.content(filePath)
-			}
-			return check.ptype
+// Detect returns the type of the highest-priority rule matching the
+// directory, or TypeUnknown
+func Detect(path string) ProjectType {
+	p := newProbe(path)
+	for _, r := range loadedTypes().rules {
+		if r.matches(p) {
+			return r.typ
 		}
 	}
-
-	// 2. SECONDARY: Check package.json for React/Node
-	if pkg := ReadPackageJSON(path); pkg != nil {
-		return DetectFromPackageJSON(pkg)
-	}
-
-	// 3. TERTIARY: Extension Heuristics (Deep Scan)
-	return ExtensionHeuristic(path)
+	return TypeUnknown
 }
 
-// ExtensionHeuristic scans for dominant file extensions
+// ExtensionHeuristic detects the type from the extensions of top-level
+// files, using only the rules based on extensions
 func ExtensionHeuristic(path string) ProjectType {
-	// Limit scan to top level or depth 1
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return TypeUnknown
-	}
-
-	counts := make(map[string]int)
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
+	p := newProbe(path)
+	for _, r := range loadedTypes().rules {
+		if len(r.Extensions) > 0 && len(r.Files)+len(r.Dependencies)+len(r.Contains) == 0 && r.matches(p) {
+			return r.typ
 		}
-		ext := filepath.Ext(e.Name())
-		counts[ext]++
-	}
-
-	if counts[".go"] > 0 {
-		return TypeGo
-	}
-	if counts[".py"] > 0 {
-		return TypePython
-	}
-	if counts[".js"] > 0 || counts[".ts"] > 0 {
-		return TypeNode
-	}
-	if counts[".java"] > 0 {
-		return TypeJava
 	}
-	if counts[".php"] > 0 {
-		return TypePHP
-	}
-	if counts[".cpp"] > 0 || counts[".c"] > 0 || counts[".h"] > 0 {
-		return TypeCpp
-	}
-
 	return TypeUnknown
 }
 
-// detectComposer checks composer.json for framework
-func detectComposer(path string) ProjectType {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return TypePHP
-	}
-	content := string(data)
-	if strings.Contains(content, "laravel/framework") {
-		return TypeLaravel
-	}
-	return TypePHP
-}
-
 // ReadPackageJSON reads and parses package.json
 func ReadPackageJSON(path string) *PackageJSON {
 	pkgPath := filepath.Join(path, "package.json")
@@ -286,47 +259,31 @@ func ReadPackageJSON(path string) *PackageJSON {
 	return &pkg
 }
 
-// DetectFromPackageJSON detects project type from package.json dependencies
+// DetectFromPackageJSON detects project type from package.json
+// dependencies, using only the rules based on dependencies
 func DetectFromPackageJSON(pkg *PackageJSON) ProjectType {
-	deps := make(map[string]bool)
-	for k := range pkg.Dependencies {
-		deps[k] = true
-	}
-	for k := range pkg.DevDependencies {
-		deps[k] = true
-	}
-
-	// Priority order
-	if deps["next"] {
-		return TypeNextJS
-	}
-	if deps["nuxt"] {
-		return TypeNuxt
-	}
-	if deps["@angular/core"] {
-		return TypeAngular
-	}
-	if deps["vue"] {
-		return TypeVue
-	}
-	if deps["svelte"] {
-		return TypeSvelte
-	}
-	if deps["astro"] {
-		return TypeAstro
-	}
-	if deps["react"] {
-		return TypeReact
+	for _, r := range loadedTypes().rules {
+		if len(r.Dependencies) > 0 && len(r.Files)+len(r.Contains)+len(r.Extensions) == 0 && hasDependency(pkg, r.Dependencies) {
+			return r.typ
+		}
 	}
-
 	return TypeNode
 }
 
-// DetectFramework returns the framework name with version
+// DetectFramework returns the framework name with version, from the
+// framework dependencies of the type definitions by detection priority
 func DetectFramework(pkg *PackageJSON) string {
-	frameworks := []string{"next", "nuxt", "@angular/core", "vue", "react", "svelte", "astro"}
+	seen := make(map[ProjectType]bool)
+	for _, r := range loadedTypes().rules {
+		if seen[r.typ] {
+			continue
+		}
+		seen[r.typ] = true
 
-	for _, fw := range frameworks {
+		fw := loadedTypes().defs[r.typ].Framework
+		if fw == "" {
+			continue
+		}
 		if ver, ok := pkg.Dependencies[fw]; ok {
 			return fw + " " + ver
 		}
@@ -340,75 +297,66 @@ func DetectFramework(pkg *PackageJSON) string {
 
 // GetStartCommand returns the start command for a project
 func (p *Project) GetStartCommand() (string, []string) {
-	switch p.Type {
-	case TypeNextJS, TypeReact, TypeVue, TypeNuxt, TypeSvelte, TypeAstro:
-		if _, ok := p.Scripts["dev"]; ok {
-			return "npm", []string{"run", "dev"}
-		}
-		if _, ok := p.Scripts["start"]; ok {
-			return "npm", []string{"start"}
-		}
-	case TypeNode:
-		if _, ok := p.Scripts["dev"]; ok {
-			return "npm", []string{"run", "dev"}
-		}
-		if _, ok := p.Scripts["start"]; ok {
-			return "npm", []string{"start"}
+	if line := p.Overrides().Start; line != "" {
+		return ShellCommand(line)
+	}
+	return p.typeCommandLine("start")
+}
+
+// DefaultPort returns the port the project's dev server listens on by default,
+// or 0 if the framework has no conventional port
+func (p *Project) DefaultPort() int {
+	def := LookupType(p.Type)
+	if def == nil {
+		return 0
+	}
+	pr := newProbe(p.Path)
+	for _, o := range def.Port {
+		if o.holds(p, pr, onPath) {
+			return o.Port
 		}
-		return "node", []string{"index.js"}
-	case TypeGo:
-		return "go", []string{"run", "."}
-	case TypePython:
-		return "python", []string{"main.py"}
-	case TypeDjango:
-		return "python", []string{"manage.py", "runserver"}
-	case TypeLaravel:
-		return "php", []string{"artisan", "serve"}
-	case TypeRust:
-		return "cargo", []string{"run"}
 	}
+	return 0
+}
 
-	return "", nil
+// StartCommandOnPort returns the start command made to listen on port, and
+// false when there is no known way to give it a port. Servers reading PORT
+// or SERVER_PORT get it from the environment and are returned as is, see
+// CommandOption.Port.
+func (p *Project) StartCommandOnPort(port int) (string, []string, bool) {
+	if p.Overrides().Start != "" {
+		bin, args := p.GetStartCommand()
+		return bin, args, false
+	}
+	c := p.typeCommand("start", port, nil)
+	if c == nil {
+		return "", nil, false
+	}
+	return c.Bin, c.Args, c.Port != ""
 }
 
 // GetTestCommand returns the test command for a project
 func (p *Project) GetTestCommand() (string, []string) {
-	switch p.Type {
-	case TypeNextJS, TypeReact, TypeVue, TypeNuxt, TypeSvelte, TypeAstro, TypeNode:
-		if _, ok := p.Scripts["test"]; ok {
-			return "npm", []string{"test"}
-		}
-	case TypeGo:
-		return "go", []string{"test", "./..."}
-	case TypePython:
-		return "pytest", nil
-	case TypeDjango:
-		return "python", []string{"manage.py", "test"}
-	case TypeLaravel:
-		return "php", []string{"artisan", "test"}
-	case TypeRust:
-		return "cargo", []string{"test"}
+	if line := p.Overrides().Test; line != "" {
+		return ShellCommand(line)
 	}
-
-	return "", nil
+	return p.typeCommandLine("test")
 }
 
 // GetBuildCommand returns the build command for a project
 func (p *Project) GetBuildCommand() (string, []string) {
-	switch p.Type {
-	case TypeNextJS, TypeReact, TypeVue, TypeNuxt, TypeSvelte, TypeAstro, TypeNode:
-		if _, ok := p.Scripts["build"]; ok {
-			return "npm", []string{"run", "build"}
-		}
-	case TypeGo:
-		return "go", []string{"build", "-o", "build/" + p.Name}
-	case TypePython:
-		return "python", []string{"-m", "build"}
-	case TypeRust:
-		return "cargo", []string{"build", "--release"}
+	if line := p.Overrides().Build; line != "" {
+		return ShellCommand(line)
 	}
+	return p.typeCommandLine("build")
+}
 
-	return "", nil
+// Overrides returns the project file settings, never nil
+func (p *Project) Overrides() *ProjectFile {
+	if p.File == nil {
+		return &ProjectFile{}
+	}
+	return p.File
 }
 
 // ScriptList returns available npm scripts
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -34,14 +35,15 @@ func TestProjectType_String(t *testing.T) {
 		{TypeJava, "Java"},
 		{TypePHP, "PHP"},
 		{TypeCpp, "C++"},
-		{ProjectType(99), "Unknown"}, // Out of range
+		{TypeDocker, "Docker"},
+		{ProjectType("removed"), "removed"}, // No definition
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
 			got := tt.ptype.String()
 			if got != tt.want {
-				t.Errorf("ProjectType(%d).String() = %q, want %q", tt.ptype, got, tt.want)
+				t.Errorf("ProjectType(%q).String() = %q, want %q", tt.ptype, got, tt.want)
 			}
 		})
 	}
@@ -58,14 +60,14 @@ func TestProjectType_Icon(t *testing.T) {
 		{TypeGo, "[GO]"},
 		{TypePython, "[PY]"},
 		{TypeRust, "[RS]"},
-		{ProjectType(99), "[?]"}, // Out of range
+		{ProjectType("removed"), "[?]"}, // No definition
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
 			got := tt.ptype.Icon()
 			if got != tt.want {
-				t.Errorf("ProjectType(%d).Icon() = %q, want %q", tt.ptype, got, tt.want)
+				t.Errorf("ProjectType(%q).Icon() = %q, want %q", tt.ptype, got, tt.want)
 			}
 		})
 	}
@@ -382,6 +384,21 @@ func TestDetect(t *testing.T) {
 			},
 			wantType: TypePython,
 		},
+		{
+			name: "compose_only",
+			setup: func(t *testing.T, dir string) {
+				createFile(t, dir, "compose.yaml", "services:\n  db:\n    image: postgres\n")
+			},
+			wantType: TypeDocker,
+		},
+		{
+			name: "dockerfile_with_go_sources",
+			setup: func(t *testing.T, dir string) {
+				createFile(t, dir, "Dockerfile", "FROM golang\n")
+				createFile(t, dir, "main.go", "package main\n")
+			},
+			wantType: TypeGo,
+		},
 		{
 			name: "empty_dir",
 			setup: func(t *testing.T, dir string) {
@@ -559,6 +576,9 @@ func TestProject_GetTestCommand(t *testing.T) {
 }
 
 func TestProject_GetBuildCommand(t *testing.T) {
+	composeDir := t.TempDir()
+	createFile(t, composeDir, "compose.yaml", "services: {}\n")
+
 	tests := []struct {
 		name     string
 		project  Project
@@ -592,6 +612,18 @@ func TestProject_GetBuildCommand(t *testing.T) {
 			wantCmd:  "npm",
 			wantArgs: []string{"run", "build"},
 		},
+		{
+			name:     "dockerfile_only",
+			project:  Project{Type: TypeDocker, Name: "MyApp", Containers: &Containers{Dockerfile: true}},
+			wantCmd:  "docker",
+			wantArgs: []string{"build", "-t", "myapp", "."},
+		},
+		{
+			name:     "compose",
+			project:  Project{Type: TypeDocker, Path: composeDir, Containers: &Containers{ComposeFile: "compose.yaml"}},
+			wantCmd:  "docker",
+			wantArgs: []string{"compose", "build"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -646,6 +678,78 @@ func TestProject_ScriptList(t *testing.T) {
 	}
 }
 
+func TestProject_DefaultPort(t *testing.T) {
+	springDir := t.TempDir()
+	createFile(t, springDir, "pom.xml", "<artifactId>spring-boot-starter-web</artifactId>")
+
+	tests := []struct {
+		name    string
+		project Project
+		want    int
+	}{
+		{"nextjs", Project{Type: TypeNextJS}, 3000},
+		{"react_cra", Project{Type: TypeReact, Scripts: map[string]string{"start": "react-scripts start"}}, 3000},
+		{"react_vite", Project{Type: TypeReact, Scripts: map[string]string{"dev": "vite"}}, 5173},
+		{"vue_vite", Project{Type: TypeVue, Scripts: map[string]string{"dev": "vite"}}, 5173},
+		{"vue_cli", Project{Type: TypeVue, Scripts: map[string]string{"serve": "vue-cli-service serve"}}, 8080},
+		{"astro", Project{Type: TypeAstro}, 4321},
+		{"angular", Project{Type: TypeAngular}, 4200},
+		{"django", Project{Type: TypeDjango}, 8000},
+		{"laravel", Project{Type: TypeLaravel}, 8000},
+		{"spring_boot", Project{Type: TypeJava, Path: springDir}, 8080},
+		{"plain_java", Project{Type: TypeJava, Path: t.TempDir()}, 0},
+		{"go", Project{Type: TypeGo}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.project.DefaultPort(); got != tt.want {
+				t.Errorf("DefaultPort() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProject_StartCommandOnPort(t *testing.T) {
+	springDir := t.TempDir()
+	createFile(t, springDir, "pom.xml", "<artifactId>spring-boot-starter-web</artifactId>")
+	publicDir := t.TempDir()
+	createFile(t, publicDir, "public/index.php", "<?php")
+
+	tests := []struct {
+		name    string
+		project Project
+		want    string // command line, "" when the port cannot be changed
+	}{
+		{"nextjs_env", Project{Type: TypeNextJS, Scripts: map[string]string{"dev": "next dev"}}, "npm run dev"},
+		{"react_cra_env", Project{Type: TypeReact, Scripts: map[string]string{"start": "react-scripts start"}}, "npm start"},
+		{"react_vite", Project{Type: TypeReact, Scripts: map[string]string{"dev": "vite"}}, "npm run dev -- --port 8001"},
+		{"svelte_vite_pnpm", Project{Type: TypeSvelte, PackageManager: PMPnpm, Scripts: map[string]string{"dev": "vite dev"}}, "pnpm run dev --port 8001"},
+		{"angular_script", Project{Type: TypeAngular, Scripts: map[string]string{"start": "ng serve"}}, "npm start -- --port 8001"},
+		{"angular_direct", Project{Type: TypeAngular}, "npx ng serve --port 8001"},
+		{"unknown_script", Project{Type: TypeVue, Scripts: map[string]string{"dev": "webpack serve"}}, ""},
+		{"django", Project{Type: TypeDjango}, "python manage.py runserver 8001"},
+		{"php_builtin", Project{Type: TypePHP, Path: publicDir}, "php -S localhost:8001 -t public"},
+		{"laravel_env", Project{Type: TypeLaravel}, "php artisan serve"},
+		{"spring_boot_env", Project{Type: TypeJava, Path: springDir}, "mvn spring-boot:run"},
+		{"plain_java", Project{Type: TypeJava, Path: t.TempDir()}, ""},
+		{"override", Project{Type: TypeNextJS, File: &ProjectFile{Start: "next dev -p 4000"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin, args, ok := tt.project.StartCommandOnPort(8001)
+			got := ""
+			if ok {
+				got = strings.Join(append([]string{bin}, args...), " ")
+			}
+			if got != tt.want {
+				t.Errorf("StartCommandOnPort(8001) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // ==============================================================
 // Integration Tests
 // ==============================================================
@@ -0,0 +1,233 @@
+package projects
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/badie/bdev/internal/core/config"
+)
+
+// Root is a directory searched for projects
+type Root struct {
+	Path      string   `json:"path"`
+	ScanDepth int      `json:"scan_depth,omitempty"` // defaults to the scan depth of the options
+	Ignore    []string `json:"ignore,omitempty"`     // globs of directory names or paths relative to Path
+}
+
+// Registry is where projects are found: the project roots and the projects
+// registered outside of them with `bdev projects add`
+type Registry struct {
+	Roots    []Root   `json:"-"`
+	Projects []string `json:"projects"` // registered project directories
+
+	path string
+}
+
+// LoadRegistry reads the projects registered in file, returning an empty
+// registry if it does not exist. Projects are searched in roots.
+func LoadRegistry(file string, roots []Root) (*Registry, error) {
+	r := &Registry{Roots: roots, path: file}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid project registry %s: %v", file, err)
+	}
+	return r, nil
+}
+
+// DefaultRegistry returns the registry of the configured project roots
+func DefaultRegistry() (*Registry, error) {
+	cfg := config.Get()
+	var roots []Root
+	for _, r := range cfg.ProjectRoots() {
+		if r.ScanDepth <= 0 {
+			r.ScanDepth = DefaultScanDepth
+		}
+		roots = append(roots, Root{Path: r.Path, ScanDepth: r.ScanDepth, Ignore: r.Ignore})
+	}
+	return LoadRegistry(cfg.ProjectRegistryFile(), roots)
+}
+
+// ScanAll finds the projects of the default registry, reusing the project index
+func ScanAll() ([]Project, error) {
+	r, err := DefaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return r.Scan(DefaultScanOptions())
+}
+
+// RefreshAll finds the projects of the default registry, analyzing every
+// project again and rewriting the index
+func RefreshAll() ([]Project, error) {
+	r, err := DefaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+	opts := DefaultScanOptions()
+	opts.Refresh = true
+	return r.Scan(opts)
+}
+
+// Find analyzes the project name resolves to in the default registry,
+// applying its package manager setting
+func Find(name string) (*Project, error) {
+	r, err := DefaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+	path, err := r.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	project := Analyze(path)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", name)
+	}
+	project.OverridePackageManager(config.Get().SettingsFor(project.Name).PackageManager)
+	return project, nil
+}
+
+// Save writes the registered projects
+func (r *Registry) Save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// Scan finds the projects of every root and the registered projects, as
+// ScanWith does for a single root. Roots that cannot be read, such as a
+// disk that is not mounted, are skipped; it is an error only when no root
+// can be read and no project is registered.
+func (r *Registry) Scan(opts ScanOptions) ([]Project, error) {
+	var readable []Root
+	var firstErr error
+	for _, root := range r.Roots {
+		if _, err := os.ReadDir(root.Path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		readable = append(readable, root)
+	}
+	if len(readable) == 0 && len(r.Projects) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return scanRoots(readable, r.Projects, opts), nil
+}
+
+// Add registers a project directory and returns its absolute path
+func (r *Registry) Add(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("not a directory: %s", dir)
+	}
+	if contains(r.Projects, abs) {
+		return "", fmt.Errorf("already registered: %s", abs)
+	}
+
+	r.Projects = append(r.Projects, abs)
+	sort.Strings(r.Projects)
+	return abs, nil
+}
+
+// Remove unregisters a project by path, or by name when a single
+// registered project has it, and returns its path
+func (r *Registry) Remove(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	match := -1
+	for i, p := range r.Projects {
+		if p == abs {
+			match = i
+			break
+		}
+		if filepath.Base(p) == dir {
+			if match >= 0 {
+				return "", fmt.Errorf("several registered projects are named %s, use a path", dir)
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return "", fmt.Errorf("not a registered project: %s", dir)
+	}
+
+	removed := r.Projects[match]
+	r.Projects = append(r.Projects[:match], r.Projects[match+1:]...)
+	return removed, nil
+}
+
+// Resolve returns the directory of a project given as "." or an absolute
+// path, a path relative to a root (clients/acme, the first root having it
+// wins) or the name of a registered project
+func (r *Registry) Resolve(name string) (string, error) {
+	if name == "." || filepath.IsAbs(name) {
+		abs, err := filepath.Abs(name)
+		if err == nil && isDir(abs) {
+			return abs, nil
+		}
+		return "", fmt.Errorf("project not found: %s", name)
+	}
+
+	for _, root := range r.Roots {
+		if path := filepath.Join(root.Path, name); isDir(path) {
+			return path, nil
+		}
+	}
+	for _, p := range r.Projects {
+		if filepath.Base(p) == name && isDir(p) {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("project not found: %s", name)
+}
+
+// RootOf returns the innermost root containing path
+func (r *Registry) RootOf(path string) (Root, bool) {
+	var best Root
+	found := false
+	for _, root := range r.Roots {
+		if Within(path, root.Path) && (!found || len(root.Path) > len(best.Path)) {
+			best, found = root, true
+		}
+	}
+	return best, found
+}
+
+// Label names a project by its path relative to its root, such as
+// clients/acme, or by its directory name outside of the roots
+func (r *Registry) Label(path string) string {
+	if root, ok := r.RootOf(path); ok {
+		if rel, err := filepath.Rel(root.Path, path); err == nil && rel != "." {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.Base(path)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
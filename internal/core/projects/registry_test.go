@@ -0,0 +1,178 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// ==============================================================
+// Registry Tests
+// ==============================================================
+
+func scannedPaths(list []Project) []string {
+	var paths []string
+	for _, p := range list {
+		paths = append(paths, p.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestRegistry_Scan(t *testing.T) {
+	work, oss, outside := t.TempDir(), t.TempDir(), t.TempDir()
+	createFile(t, work, "api/go.mod", "module api\n")
+	createFile(t, work, "clients/acme/go.mod", "module acme\n")
+	createFile(t, work, "clients/old-corp/go.mod", "module corp\n")
+	createFile(t, work, "archive/legacy/go.mod", "module legacy\n")
+	createFile(t, oss, "lib/Cargo.toml", "[package]\nname = \"lib\"\n")
+	createFile(t, oss, "lib/nothing/README.md", "# nothing\n")
+	createFile(t, oss, "deep/a/b/go.mod", "module deep\n")
+	createFile(t, outside, "tool/go.mod", "module tool\n")
+
+	r := &Registry{
+		Roots: []Root{
+			{Path: work, ScanDepth: 2, Ignore: []string{"archive", "clients/old-*"}},
+			{Path: oss, ScanDepth: 3},
+			{Path: filepath.Join(outside, "unmounted")},
+		},
+		Projects: []string{
+			filepath.Join(outside, "tool"),
+			filepath.Join(work, "api"),           // already under a root
+			filepath.Join(outside, "deleted"),    // no longer exists
+			filepath.Join(oss, "lib", "nothing"), // inside a scanned project
+		},
+	}
+
+	indexFile := filepath.Join(t.TempDir(), "projects.json")
+	list, err := r.Scan(ScanOptions{IndexFile: indexFile})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(outside, "tool"),
+		filepath.Join(oss, "deep", "a", "b"),
+		filepath.Join(oss, "lib"),
+		filepath.Join(work, "api"),
+		filepath.Join(work, "clients", "acme"),
+	}
+	sort.Strings(want)
+	if got := scannedPaths(list); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %v, want %v", got, want)
+	}
+	if n := len(loadIndex(indexFile).Entries); n != len(want) {
+		t.Errorf("index has %d entries, want %d", n, len(want))
+	}
+
+	// Entries of a root that cannot be read are kept, removed projects of
+	// the scanned roots are dropped
+	os.RemoveAll(oss)
+	os.RemoveAll(filepath.Join(work, "api"))
+	if _, err := r.Scan(ScanOptions{IndexFile: indexFile}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if n := len(loadIndex(indexFile).Entries); n != len(want)-1 {
+		t.Errorf("index has %d entries, want %d", n, len(want)-1)
+	}
+}
+
+func TestRegistry_ScanUnreadable(t *testing.T) {
+	r := &Registry{Roots: []Root{{Path: filepath.Join(t.TempDir(), "missing")}}}
+	if _, err := r.Scan(ScanOptions{}); err == nil {
+		t.Error("Scan() error = nil, want an error without any readable root")
+	}
+
+	r.Projects = []string{t.TempDir()}
+	if list, err := r.Scan(ScanOptions{}); err != nil || len(list) != 1 {
+		t.Errorf("Scan() = %d projects, %v, want the registered project", len(list), err)
+	}
+}
+
+func TestRegistry_AddRemove(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "registry.json")
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "tool")
+	os.MkdirAll(tool, 0o755)
+
+	r, err := LoadRegistry(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r.Add(tool); err != nil || got != tool {
+		t.Fatalf("Add() = %q, %v", got, err)
+	}
+	if _, err := r.Add(tool); err == nil {
+		t.Error("Add() twice error = nil")
+	}
+	if _, err := r.Add(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Add(missing) error = nil")
+	}
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = LoadRegistry(file, nil)
+	if err != nil || !reflect.DeepEqual(r.Projects, []string{tool}) {
+		t.Fatalf("LoadRegistry() = %v, %v", r.Projects, err)
+	}
+	if _, err := r.Remove("other"); err == nil {
+		t.Error("Remove(other) error = nil")
+	}
+	if got, err := r.Remove("tool"); err != nil || got != tool || len(r.Projects) != 0 {
+		t.Errorf("Remove(tool) = %q, %v, left %v", got, err, r.Projects)
+	}
+
+	os.WriteFile(file, []byte("{not json"), 0o644)
+	if _, err := LoadRegistry(file, nil); err == nil {
+		t.Error("LoadRegistry(corrupt) error = nil")
+	}
+}
+
+func TestRegistry_ResolveAndLabel(t *testing.T) {
+	work, oss, outside := t.TempDir(), t.TempDir(), t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(work, "clients", "acme"),
+		filepath.Join(work, "api"),
+		filepath.Join(oss, "api"),
+		filepath.Join(oss, "lib"),
+		filepath.Join(outside, "tool"),
+	} {
+		os.MkdirAll(dir, 0o755)
+	}
+	r := &Registry{
+		Roots:    []Root{{Path: work}, {Path: oss}},
+		Projects: []string{filepath.Join(outside, "tool")},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"clients/acme", filepath.Join(work, "clients", "acme")},
+		{"api", filepath.Join(work, "api")},
+		{"lib", filepath.Join(oss, "lib")},
+		{"tool", filepath.Join(outside, "tool")},
+		{filepath.Join(outside, "tool"), filepath.Join(outside, "tool")},
+		{"missing", ""},
+	}
+	for _, tt := range tests {
+		got, err := r.Resolve(tt.name)
+		if got != tt.want || (err != nil) != (tt.want == "") {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	labels := map[string]string{
+		filepath.Join(work, "clients", "acme"): "clients/acme",
+		filepath.Join(oss, "lib"):              "lib",
+		filepath.Join(outside, "tool"):         "tool",
+	}
+	for path, want := range labels {
+		if got := r.Label(path); got != want {
+			t.Errorf("Label(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package projects
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/badie/bdev/internal/core/ignore"
+)
+
+// dependencyDirs hold installed dependencies rather than project sources
+var dependencyDirs = map[string]bool{
+	"node_modules": true, "vendor": true, ".venv": true, "venv": true,
+	"bower_components": true, "jspm_packages": true, "Pods": true, ".bundle": true,
+}
+
+// IsDependencyDir reports whether a directory name holds installed
+// dependencies, such as node_modules or .venv
+func IsDependencyDir(name string) bool {
+	return dependencyDirs[name]
+}
+
+// maxCountSize skips counting lines of larger files, which are generated or minified
+const maxCountSize = 2 << 20
+
+// LanguageStats counts the lines of one language
+type LanguageStats struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+	Code     int    `json:"code"`
+	Comments int    `json:"comments"`
+	Blanks   int    `json:"blanks"`
+}
+
+// Lines returns the number of lines of any kind
+func (l LanguageStats) Lines() int {
+	return l.Code + l.Comments + l.Blanks
+}
+
+func (l *LanguageStats) add(o LanguageStats) {
+	l.Files += o.Files
+	l.Code += o.Code
+	l.Comments += o.Comments
+	l.Blanks += o.Blanks
+}
+
+// Stats is the disk usage and line counts of a project. Sources are the
+// files not ignored by .gitignore, dependencies the installed packages, and
+// other the remaining ignored content such as .git and build output.
+type Stats struct {
+	SourceBytes     int64 `json:"source_bytes"`
+	SourceFiles     int   `json:"source_files"`
+	DependencyBytes int64 `json:"dependency_bytes"`
+	DependencyFiles int   `json:"dependency_files"`
+	OtherBytes      int64 `json:"other_bytes"`
+	OtherFiles      int   `json:"other_files"`
+
+	// Languages are sorted by lines of code, most first
+	Languages []LanguageStats `json:"languages"`
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	// Stamp is the statsStamp of the project when measured
+	Stamp int64 `json:"stamp"`
+}
+
+// TotalBytes returns the disk size of the project
+func (s *Stats) TotalBytes() int64 {
+	return s.SourceBytes + s.DependencyBytes + s.OtherBytes
+}
+
+// Total sums the line counts of every language
+func (s *Stats) Total() LanguageStats {
+	total := LanguageStats{Language: "Total"}
+	for _, l := range s.Languages {
+		total.add(l)
+	}
+	return total
+}
+
+// countJob is a source file whose lines are counted by a worker
+type countJob struct {
+	path string
+	lang *Language
+}
+
+// ComputeStats measures a project directory, respecting its .gitignore.
+// Lines are counted and ignored directories measured in parallel.
+func ComputeStats(dir string, jobs int) (*Stats, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	s := &Stats{UpdatedAt: time.Now()}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, jobs)
+
+	// measure sizes an ignored directory into bytes and files
+	measure := func(path string, bytes *int64, files *int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			n, count := dirSize(path)
+			mu.Lock()
+			*bytes += n
+			*files += count
+			mu.Unlock()
+		}()
+	}
+
+	langs := make(map[string]*LanguageStats)
+	queue := make(chan countJob, 64)
+	var workers sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range queue {
+				ls, ok := countFile(job)
+				if !ok {
+					continue
+				}
+				mu.Lock()
+				if langs[ls.Language] == nil {
+					langs[ls.Language] = &LanguageStats{Language: ls.Language}
+				}
+				langs[ls.Language].add(ls)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	matcher := ignore.Load(dir)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			switch {
+			case dependencyDirs[d.Name()]:
+				measure(path, &s.DependencyBytes, &s.DependencyFiles)
+				return filepath.SkipDir
+			case matcher.Match(filepath.ToSlash(rel), true):
+				measure(path, &s.OtherBytes, &s.OtherFiles)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		mu.Lock()
+		if matcher.Match(filepath.ToSlash(rel), false) {
+			s.OtherBytes += info.Size()
+			s.OtherFiles++
+			mu.Unlock()
+			return nil
+		}
+		s.SourceBytes += info.Size()
+		s.SourceFiles++
+		mu.Unlock()
+
+		if l := LanguageOf(path); l != nil && info.Size() <= maxCountSize {
+			queue <- countJob{path: path, lang: l}
+		}
+		return nil
+	})
+
+	close(queue)
+	workers.Wait()
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range langs {
+		s.Languages = append(s.Languages, *l)
+	}
+	sort.Slice(s.Languages, func(i, j int) bool {
+		if s.Languages[i].Code != s.Languages[j].Code {
+			return s.Languages[i].Code > s.Languages[j].Code
+		}
+		return s.Languages[i].Language < s.Languages[j].Language
+	})
+	return s, nil
+}
+
+// statsStamp is the latest modification time of the files and directories
+// ComputeStats walks. Dependency and ignored directories, which it only
+// measures, count with their own time: reinstalling node_modules or
+// rebuilding dist replaces them.
+func statsStamp(dir string) int64 {
+	var latest int64
+	matcher := ignore.Load(dir)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			latest = max(latest, info.ModTime().UnixNano())
+		}
+		if !d.IsDir() || path == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || dependencyDirs[d.Name()] || matcher.Match(filepath.ToSlash(rel), true) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return latest
+}
+
+// countFile counts the lines of a source file, skipping binary content
+func countFile(job countJob) (LanguageStats, bool) {
+	data, err := os.ReadFile(job.path)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 {
+		return LanguageStats{}, false
+	}
+	code, comments, blanks := job.lang.CountLines(string(data))
+	return LanguageStats{Language: job.lang.Name, Files: 1, Code: code, Comments: comments, Blanks: blanks}, true
+}
+
+// dirSize returns the size and number of regular files below dir
+func dirSize(dir string) (int64, int) {
+	var size int64
+	var files int
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	return size, files
+}
+
+// EnsureStats sets Stats and Size on the projects, computing the ones the
+// index does not hold or that changed since they were measured (all of them
+// with refresh) and caching them in indexFile. Checking for changes only
+// reads modification times, see statsStamp.
+func EnsureStats(list []Project, indexFile string, refresh bool) {
+	jobs := runtime.NumCPU()
+	computed := make(map[string]*Stats)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, jobs)
+
+	for i := range list {
+		p := &list[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			// Taken before measuring, a change meanwhile is caught next time
+			stamp := statsStamp(p.Path)
+			if p.Stats != nil && !refresh && p.Stats.Stamp == stamp {
+				p.Size = p.Stats.TotalBytes()
+				return
+			}
+			// Projects are measured in parallel, so each one counts on a single worker
+			s, err := ComputeStats(p.Path, 1)
+			if err != nil {
+				return
+			}
+			s.Stamp = stamp
+			p.Stats = s
+			p.Size = s.TotalBytes()
+			mu.Lock()
+			computed[p.Path] = s
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if indexFile != "" && len(computed) > 0 {
+		// Stats are only a cache, a failed write means computing them again
+		_ = storeStats(indexFile, computed)
+	}
+}
+
+// storeStats records stats of projects and workspace members in the index
+func storeStats(indexFile string, stats map[string]*Stats) error {
+	idx := loadIndex(indexFile)
+	changed := false
+
+	set := func(p *Project) {
+		if s, ok := stats[p.Path]; ok {
+			p.Stats = s
+			p.Size = s.TotalBytes()
+			changed = true
+		}
+	}
+	for _, e := range idx.Entries {
+		set(&e.Project)
+		for i := range e.Project.Children {
+			set(&e.Project.Children[i])
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return idx.save(indexFile)
+}
@@ -0,0 +1,194 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ==============================================================
+// Line Counting Tests
+// ==============================================================
+
+func TestLanguage_CountLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		code     int
+		comments int
+		blanks   int
+	}{
+		{
+			name:    "go",
+			file:    "main.go",
+			content: "// Package main\npackage main\n\n/* block\n   comment */\nfunc main() {}\n",
+			code:    2, comments: 3, blanks: 1,
+		},
+		{
+			name:    "code_after_block_end",
+			file:    "a.ts",
+			content: "/* start\nend */ const x = 1\n",
+			code:    1, comments: 1,
+		},
+		{
+			name:    "one_line_block",
+			file:    "a.c",
+			content: "/* note */\n/* a */ int x;\n",
+			code:    1, comments: 1,
+		},
+		{
+			name:    "python_docstring",
+			file:    "cli.py",
+			content: "\"\"\"Module doc.\n\nMore.\n\"\"\"\n# comment\nimport os\n",
+			code:    1, comments: 5,
+		},
+		{
+			name:    "lua_block_before_line_comment",
+			file:    "init.lua",
+			content: "--[[\nlong\n]]\n-- short\nprint(1)\n",
+			code:    1, comments: 4,
+		},
+		{
+			name:    "crlf_and_indentation",
+			file:    "Dockerfile",
+			content: "FROM alpine\r\n  # comment\r\n\r\n",
+			code:    1, comments: 1, blanks: 1,
+		},
+		{
+			name: "empty",
+			file: "empty.rs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := LanguageOf(tt.file)
+			if l == nil {
+				t.Fatalf("LanguageOf(%q) = nil", tt.file)
+			}
+			code, comments, blanks := l.CountLines(tt.content)
+			if code != tt.code || comments != tt.comments || blanks != tt.blanks {
+				t.Errorf("CountLines() = %d code, %d comments, %d blanks, want %d, %d, %d",
+					code, comments, blanks, tt.code, tt.comments, tt.blanks)
+			}
+		})
+	}
+}
+
+func TestLanguageOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"src/App.TSX", "TSX"},
+		{"docker/Dockerfile.dev", "Dockerfile"},
+		{"Makefile", "Makefile"},
+		{"logo.png", ""},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if l := LanguageOf(tt.path); l != nil {
+			got = l.Name
+		}
+		if got != tt.want {
+			t.Errorf("LanguageOf(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// ==============================================================
+// Stats Tests
+// ==============================================================
+
+func TestComputeStats(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, ".gitignore", "dist/\n*.log\n")
+	createFile(t, dir, "main.go", "package main\n\n// entry\nfunc main() {}\n")
+	createFile(t, dir, "util/util.go", "package util\n")
+	createFile(t, dir, "web/app.js", "console.log(1)\n")
+	createFile(t, dir, "logo.bin", "\x00\x01")
+	createFile(t, dir, "debug.log", "ignored\n")
+	createFile(t, dir, "dist/app.js", "generated();\n")
+	createFile(t, dir, "node_modules/x/index.js", "module.exports = 1\n")
+	createFile(t, dir, ".git/HEAD", "ref: refs/heads/main\n")
+
+	s, err := ComputeStats(dir, 2)
+	if err != nil {
+		t.Fatalf("ComputeStats() error = %v", err)
+	}
+
+	if s.SourceFiles != 5 {
+		t.Errorf("SourceFiles = %d, want 5 (.gitignore, 2 Go, 1 JS, 1 binary)", s.SourceFiles)
+	}
+	if s.DependencyFiles != 1 || s.DependencyBytes != int64(len("module.exports = 1\n")) {
+		t.Errorf("dependencies = %d files, %d bytes", s.DependencyFiles, s.DependencyBytes)
+	}
+	if s.OtherFiles != 3 {
+		t.Errorf("OtherFiles = %d, want 3 (.git/HEAD, dist/app.js, debug.log)", s.OtherFiles)
+	}
+
+	if len(s.Languages) != 2 || s.Languages[0].Language != "Go" {
+		t.Fatalf("Languages = %+v, want Go then JavaScript", s.Languages)
+	}
+	goStats := s.Languages[0]
+	if goStats.Files != 2 || goStats.Code != 3 || goStats.Comments != 1 || goStats.Blanks != 1 {
+		t.Errorf("Go = %+v", goStats)
+	}
+	if total := s.Total(); total.Code != 4 || total.Files != 3 {
+		t.Errorf("Total() = %+v, want 4 lines of code in 3 files", total)
+	}
+	if s.TotalBytes() != s.SourceBytes+s.DependencyBytes+s.OtherBytes {
+		t.Error("TotalBytes() does not add up")
+	}
+
+	if _, err := ComputeStats(filepath.Join(dir, "missing"), 1); err == nil {
+		t.Error("ComputeStats() expected error for a missing directory")
+	}
+}
+
+func TestEnsureStats_CachedInIndex(t *testing.T) {
+	root := t.TempDir()
+	indexFile := filepath.Join(t.TempDir(), "projects.json")
+	createFile(t, root, "api/go.mod", "module api\n")
+	createFile(t, root, "api/main.go", "package main\n")
+	createFile(t, root, "api/internal/db/db.go", "package db\n")
+	opts := ScanOptions{MaxDepth: 2, IndexFile: indexFile}
+
+	list := mustScan(t, root, opts)
+	EnsureStats(list, indexFile, false)
+	if list[0].Stats == nil || list[0].Size == 0 {
+		t.Fatalf("EnsureStats() left %+v unmeasured", list[0])
+	}
+
+	// A second scan reads the stats back from the index
+	list = mustScan(t, root, opts)
+	if list[0].Stats == nil || list[0].Size == 0 {
+		t.Fatal("stats were not cached in the index")
+	}
+
+	// Cached stats are kept without refresh, so a tampered count survives
+	list[0].Stats.Languages[0].Code = 999
+	EnsureStats(list, indexFile, false)
+	if list[0].Stats.Languages[0].Code != 999 {
+		t.Error("EnsureStats() recomputed cached stats without refresh")
+	}
+	EnsureStats(list, indexFile, true)
+	if list[0].Stats.Languages[0].Code != 2 {
+		t.Errorf("EnsureStats(refresh) code = %d, want 2", list[0].Stats.Languages[0].Code)
+	}
+
+	// Editing a nested file in place invalidates the stats, although the
+	// project directory and its marker files are unchanged
+	createFile(t, root, "api/internal/db/db.go", "package db\n\nvar ready = true\n")
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(root, "api", "internal", "db", "db.go"), later, later)
+	list = mustScan(t, root, opts)
+	list[0].Stats.Languages[0].Code = 999
+	EnsureStats(list, indexFile, false)
+	if got := list[0].Stats.Languages[0].Code; got != 3 {
+		t.Errorf("EnsureStats() after an edit code = %d, want 3", got)
+	}
+}
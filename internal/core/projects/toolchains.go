@@ -0,0 +1,64 @@
+package projects
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CMakeBuildDir is the out-of-source build directory used for CMake projects
+const CMakeBuildDir = "build"
+
+// UsesCMake reports whether a C/C++ project is built with CMake rather than make
+func (p *Project) UsesCMake() bool {
+	return p.hasFile("CMakeLists.txt")
+}
+
+// GetConfigureCommand returns the step that must run before building, if any.
+// For CMake projects that is the configure step until the build directory exists.
+func (p *Project) GetConfigureCommand() (string, []string) {
+	if p.Type != TypeCpp || !p.UsesCMake() {
+		return "", nil
+	}
+	if p.hasFile(filepath.Join(CMakeBuildDir, "CMakeCache.txt")) {
+		return "", nil
+	}
+	return "cmake", []string{"-S", ".", "-B", CMakeBuildDir}
+}
+
+// ReadComposerScripts reads the scripts section of composer.json.
+// Array scripts are joined with " && " for display.
+func ReadComposerScripts(path string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(path, "composer.json"))
+	if err != nil {
+		return nil
+	}
+
+	var composer struct {
+		Scripts map[string]json.RawMessage `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &composer); err != nil || len(composer.Scripts) == 0 {
+		return nil
+	}
+
+	scripts := make(map[string]string, len(composer.Scripts))
+	for name, raw := range composer.Scripts {
+		var single string
+		if err := json.Unmarshal(raw, &single); err == nil {
+			scripts[name] = single
+			continue
+		}
+		var list []string
+		if err := json.Unmarshal(raw, &list); err == nil {
+			scripts[name] = strings.Join(list, " && ")
+		}
+	}
+	return scripts
+}
+
+// hasFile reports whether a file exists relative to the project root
+func (p *Project) hasFile(name string) bool {
+	_, err := os.Stat(filepath.Join(p.Path, name))
+	return err == nil
+}
@@ -0,0 +1,190 @@
+package projects
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// ==============================================================
+// Toolchain Command Tests
+// ==============================================================
+
+type command struct {
+	bin  string
+	args []string
+}
+
+func TestProject_ToolchainCommands(t *testing.T) {
+	mvnw := "." + string(filepath.Separator) + "mvnw"
+	gradlew := "." + string(filepath.Separator) + "gradlew"
+	if runtime.GOOS == "windows" {
+		mvnw += ".cmd"
+		gradlew += ".cmd"
+	}
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		project   Project
+		wantStart command
+		wantTest  command
+		wantBuild command
+	}{
+		{
+			name:      "maven",
+			files:     map[string]string{"pom.xml": "<project/>"},
+			project:   Project{Type: TypeJava},
+			wantStart: command{"mvn", []string{"compile", "exec:java"}},
+			wantTest:  command{"mvn", []string{"test"}},
+			wantBuild: command{"mvn", []string{"package"}},
+		},
+		{
+			name:      "maven_wrapper_spring_boot",
+			files:     map[string]string{"pom.xml": "<artifactId>spring-boot-starter-web</artifactId>", filepath.Base(mvnw): ""},
+			project:   Project{Type: TypeJava},
+			wantStart: command{mvnw, []string{"spring-boot:run"}},
+			wantTest:  command{mvnw, []string{"test"}},
+			wantBuild: command{mvnw, []string{"package"}},
+		},
+		{
+			name:      "gradle",
+			files:     map[string]string{"build.gradle": "plugins { id 'application' }"},
+			project:   Project{Type: TypeJava},
+			wantStart: command{"gradle", []string{"run"}},
+			wantTest:  command{"gradle", []string{"test"}},
+			wantBuild: command{"gradle", []string{"build"}},
+		},
+		{
+			name:      "gradle_wrapper_spring_boot",
+			files:     map[string]string{"build.gradle": "id 'org.springframework.boot' version '3.2.0'\nimplementation 'org.springframework.boot:spring-boot-starter'", filepath.Base(gradlew): ""},
+			project:   Project{Type: TypeJava},
+			wantStart: command{gradlew, []string{"bootRun"}},
+			wantTest:  command{gradlew, []string{"test"}},
+			wantBuild: command{gradlew, []string{"build"}},
+		},
+		{
+			name:      "cmake",
+			files:     map[string]string{"CMakeLists.txt": "project(app)"},
+			project:   Project{Type: TypeCpp, Name: "app"},
+			wantStart: command{filepath.Join(".", "build", "app"), nil},
+			wantTest:  command{"ctest", []string{"--test-dir", "build", "--output-on-failure"}},
+			wantBuild: command{"cmake", []string{"--build", "build"}},
+		},
+		{
+			name:      "makefile",
+			files:     map[string]string{"Makefile": "all:"},
+			project:   Project{Type: TypeCpp},
+			wantStart: command{"make", []string{"run"}},
+			wantTest:  command{"make", []string{"test"}},
+			wantBuild: command{"make", nil},
+		},
+		{
+			name:      "php_composer_scripts",
+			files:     map[string]string{"composer.json": "{}"},
+			project:   Project{Type: TypePHP, Scripts: map[string]string{"serve": "php -S localhost:9000", "test": "phpunit", "build": "box compile"}},
+			wantStart: command{"composer", []string{"run-script", "serve"}},
+			wantTest:  command{"composer", []string{"run-script", "test"}},
+			wantBuild: command{"composer", []string{"run-script", "build"}},
+		},
+		{
+			name:      "php_defaults",
+			files:     map[string]string{"composer.json": "{}", "public/index.php": ""},
+			project:   Project{Type: TypePHP},
+			wantStart: command{"php", []string{"-S", "localhost:8000", "-t", "public"}},
+			wantTest:  command{filepath.Join("vendor", "bin", "phpunit"), nil},
+			wantBuild: command{"composer", []string{"install", "--no-dev", "--optimize-autoloader"}},
+		},
+		{
+			name:      "angular_cli",
+			files:     map[string]string{"angular.json": "{}"},
+			project:   Project{Type: TypeAngular},
+			wantStart: command{"npx", []string{"ng", "serve"}},
+			wantTest:  command{"npx", []string{"ng", "test"}},
+			wantBuild: command{"npx", []string{"ng", "build"}},
+		},
+		{
+			name:      "angular_scripts",
+			files:     map[string]string{"angular.json": "{}"},
+			project:   Project{Type: TypeAngular, Scripts: map[string]string{"start": "ng serve", "test": "ng test", "build": "ng build"}},
+			wantStart: command{"npm", []string{"start"}},
+			wantTest:  command{"npm", []string{"test"}},
+			wantBuild: command{"npm", []string{"run", "build"}},
+		},
+		{
+			name:      "django",
+			files:     map[string]string{"manage.py": ""},
+			project:   Project{Type: TypeDjango},
+			wantStart: command{"python", []string{"manage.py", "runserver"}},
+			wantTest:  command{"python", []string{"manage.py", "test"}},
+			wantBuild: command{"python", []string{"manage.py", "collectstatic", "--noinput"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				createFile(t, dir, name, content)
+			}
+			p := tt.project
+			p.Path = dir
+
+			check := func(kind string, bin string, args []string, want command) {
+				t.Helper()
+				if bin != want.bin || !reflect.DeepEqual(args, want.args) {
+					t.Errorf("%s = %s %v, want %s %v", kind, bin, args, want.bin, want.args)
+				}
+			}
+
+			bin, args := p.GetStartCommand()
+			check("GetStartCommand()", bin, args, tt.wantStart)
+			bin, args = p.GetTestCommand()
+			check("GetTestCommand()", bin, args, tt.wantTest)
+			bin, args = p.GetBuildCommand()
+			check("GetBuildCommand()", bin, args, tt.wantBuild)
+		})
+	}
+}
+
+func TestProject_GetConfigureCommand(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "CMakeLists.txt", "project(app)")
+	p := Project{Type: TypeCpp, Path: dir}
+
+	bin, args := p.GetConfigureCommand()
+	if bin != "cmake" || !reflect.DeepEqual(args, []string{"-S", ".", "-B", "build"}) {
+		t.Errorf("GetConfigureCommand() = %s %v, want cmake -S . -B build", bin, args)
+	}
+
+	createFile(t, dir, "build/CMakeCache.txt", "")
+	if bin, _ := p.GetConfigureCommand(); bin != "" {
+		t.Errorf("GetConfigureCommand() = %q after configure, want none", bin)
+	}
+
+	goProject := Project{Type: TypeGo, Path: dir}
+	if bin, _ := goProject.GetConfigureCommand(); bin != "" {
+		t.Errorf("GetConfigureCommand() for Go = %q, want none", bin)
+	}
+}
+
+func TestReadComposerScripts(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "composer.json", `{
+  "scripts": {
+    "test": "phpunit",
+    "check": ["@lint", "@test"]
+  }
+}`)
+
+	got := ReadComposerScripts(dir)
+	want := map[string]string{"test": "phpunit", "check": "@lint && @test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadComposerScripts() = %v, want %v", got, want)
+	}
+
+	if got := ReadComposerScripts(t.TempDir()); got != nil {
+		t.Errorf("ReadComposerScripts() without composer.json = %v, want nil", got)
+	}
+}
@@ -0,0 +1,452 @@
+package projects
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/badie/bdev/internal/core/config"
+)
+
+//go:embed types/*.yml
+var builtinTypes embed.FS
+
+// SourceBuiltin is the Source of the type definitions shipped with bdev
+const SourceBuiltin = "builtin"
+
+// maxContentSize bounds how much of a file content rules read
+const maxContentSize = 1 << 20
+
+var typeID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// TypeDef defines a project type: how it is detected, how it is shown and
+// the commands, port, artifacts and watched files used when the project
+// does not override them
+type TypeDef struct {
+	ID       ProjectType  `yaml:"id" json:"id"`
+	Name     string       `yaml:"name" json:"name"`
+	Icon     string       `yaml:"icon" json:"icon"`
+	Detect   []Rule       `yaml:"detect" json:"detect"`
+	Commands TypeCommands `yaml:"commands" json:"commands,omitempty"`
+
+	// Ecosystem limits the package managers of the type, see PackageManager.Ecosystem
+	Ecosystem string `yaml:"ecosystem,omitempty" json:"ecosystem,omitempty"`
+
+	// Framework is the package.json dependency whose version is shown
+	Framework string `yaml:"framework,omitempty" json:"framework,omitempty"`
+
+	// Deps names the tools checking dependency updates and advisories
+	Deps string `yaml:"deps,omitempty" json:"deps,omitempty"`
+
+	// Port is the port the dev server listens on by default
+	Port PortOptions `yaml:"port,omitempty" json:"port,omitempty"`
+
+	// Artifacts are the generated directories that can be deleted
+	Artifacts []ArtifactRule `yaml:"artifacts,omitempty" json:"artifacts,omitempty"`
+
+	// Watch are the source globs watched for changes
+	Watch []string `yaml:"watch,omitempty" json:"watch,omitempty"`
+
+	// Source is SourceBuiltin or the file the type was loaded from
+	Source string `yaml:"-" json:"source"`
+}
+
+// DirMatch holds conditions on a project directory. Every condition set must
+// hold: any of the files exists, any of the dependencies is in
+// package.json, every content pattern matches and a top-level file has any
+// of the extensions.
+type DirMatch struct {
+	Files        []string       `yaml:"files,omitempty" json:"files,omitempty"`               // names or globs
+	Dependencies []string       `yaml:"dependencies,omitempty" json:"dependencies,omitempty"` // names or globs like @nestjs/*
+	Contains     []ContentMatch `yaml:"contains,omitempty" json:"contains,omitempty"`
+	Extensions   []string       `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+}
+
+// Rule detects a project type. The matching rule with the highest
+// priority decides the project type.
+type Rule struct {
+	Priority int `yaml:"priority" json:"priority"`
+	DirMatch `yaml:",inline"`
+}
+
+// ContentMatch is a regular expression searched in a project file
+type ContentMatch struct {
+	File    string `yaml:"file" json:"file"`
+	Pattern string `yaml:"pattern" json:"pattern"`
+
+	re *regexp.Regexp
+}
+
+// Condition restricts a command option, port or artifact to the projects
+// meeting it. Besides the directory matches, any of the package scripts
+// exists and runs any of the script_uses tools, the package manager is any
+// of the listed ones and every tool is installed.
+type Condition struct {
+	DirMatch `yaml:",inline"`
+
+	Scripts         []string         `yaml:"scripts,omitempty" json:"scripts,omitempty"`
+	ScriptUses      []string         `yaml:"script_uses,omitempty" json:"script_uses,omitempty"`
+	PackageManagers []PackageManager `yaml:"package_managers,omitempty" json:"package_managers,omitempty"`
+	Tools           []string         `yaml:"tools,omitempty" json:"tools,omitempty"`
+}
+
+// Dependency tools of TypeDef.Deps
+const (
+	DepsNpm      = "npm"
+	DepsGo       = "go"
+	DepsPip      = "pip"
+	DepsCargo    = "cargo"
+	DepsComposer = "composer"
+)
+
+// typeRegistry holds the loaded definitions and their rules by priority
+type typeRegistry struct {
+	defs   map[ProjectType]*TypeDef
+	rules  []typeRule
+	errors []error
+
+	// fingerprint changes with the definition files, invalidating cached types
+	fingerprint string
+}
+
+type typeRule struct {
+	Rule
+	typ ProjectType
+}
+
+var (
+	typesOnce sync.Once
+	types     *typeRegistry
+)
+
+// loadedTypes loads the built-in and user definitions on first use
+func loadedTypes() *typeRegistry {
+	typesOnce.Do(func() {
+		types = loadTypes(config.Get().ProjectTypesDir())
+	})
+	return types
+}
+
+// Types returns the project type definitions sorted by name
+func Types() []*TypeDef {
+	defs := make([]*TypeDef, 0, len(loadedTypes().defs))
+	for _, def := range loadedTypes().defs {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// TypeErrors returns the problems found in user definition files, which
+// are skipped
+func TypeErrors() []error {
+	return loadedTypes().errors
+}
+
+// LookupType returns the definition of a type, or nil
+func LookupType(t ProjectType) *TypeDef {
+	return loadedTypes().defs[t]
+}
+
+// ParseType finds a type by ID or display name, ignoring case
+func ParseType(s string) (ProjectType, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for id, def := range loadedTypes().defs {
+		if string(id) == s || strings.ToLower(def.Name) == s {
+			return id, true
+		}
+	}
+	return TypeUnknown, false
+}
+
+// loadTypes reads the embedded definitions, then the *.yml files of dir. A
+// user definition replaces the built-in one with the same ID.
+func loadTypes(dir string) *typeRegistry {
+	reg := &typeRegistry{defs: make(map[ProjectType]*TypeDef)}
+	hash := sha256.New()
+
+	builtin, _ := fs.Glob(builtinTypes, "types/*.yml")
+	for _, name := range builtin {
+		data, _ := builtinTypes.ReadFile(name)
+		hash.Write(data)
+		def, err := parseTypeDef(data)
+		if err != nil {
+			// Embedded definitions are covered by tests
+			panic(fmt.Sprintf("invalid built-in project type %s: %v", name, err))
+		}
+		def.Source = SourceBuiltin
+		reg.defs[def.ID] = def
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.yml"))
+	yamlFiles, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	files = append(files, yamlFiles...)
+	sort.Strings(files)
+
+	user := make(map[ProjectType]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			fmt.Fprintf(hash, "%s\x00%s\x00", filepath.Base(file), data)
+			var def *TypeDef
+			if def, err = parseTypeDef(data); err == nil {
+				if other, dup := user[def.ID]; dup {
+					err = fmt.Errorf("type %q is already defined in %s", string(def.ID), filepath.Base(other))
+				} else {
+					def.Source = file
+					user[def.ID] = file
+					reg.defs[def.ID] = def
+				}
+			}
+		}
+		if err != nil {
+			reg.errors = append(reg.errors, fmt.Errorf("%s: %v", filepath.Base(file), err))
+		}
+	}
+
+	for id, def := range reg.defs {
+		for _, r := range def.Detect {
+			reg.rules = append(reg.rules, typeRule{Rule: r, typ: id})
+		}
+	}
+	// Ties go to the type ID, so detection does not depend on map order
+	sort.SliceStable(reg.rules, func(i, j int) bool {
+		if reg.rules[i].Priority != reg.rules[j].Priority {
+			return reg.rules[i].Priority > reg.rules[j].Priority
+		}
+		return reg.rules[i].typ < reg.rules[j].typ
+	})
+	reg.fingerprint = hex.EncodeToString(hash.Sum(nil))[:16]
+	return reg
+}
+
+// parseTypeDef reads and validates a definition, filling defaults
+func parseTypeDef(data []byte) (*TypeDef, error) {
+	var def TypeDef
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return nil, err
+	}
+
+	if !typeID.MatchString(string(def.ID)) {
+		return nil, fmt.Errorf("invalid id %q (use lowercase letters, digits, - and _)", string(def.ID))
+	}
+	if def.Name == "" {
+		def.Name = string(def.ID)
+	}
+	switch def.Ecosystem {
+	case "", EcosystemJS, EcosystemPython:
+	default:
+		return nil, fmt.Errorf("unknown ecosystem %q (use %s or %s)", def.Ecosystem, EcosystemJS, EcosystemPython)
+	}
+	switch def.Deps {
+	case "", DepsNpm, DepsGo, DepsPip, DepsCargo, DepsComposer:
+	default:
+		return nil, fmt.Errorf("unknown deps %q (use %s, %s, %s, %s or %s)", def.Deps, DepsNpm, DepsGo, DepsPip, DepsCargo, DepsComposer)
+	}
+	if def.Icon == "" {
+		def.Icon = "[" + strings.ToUpper(string(def.ID)[:min(2, len(def.ID))]) + "]"
+	}
+
+	for i := range def.Detect {
+		r := &def.Detect[i]
+		if len(r.Files)+len(r.Dependencies)+len(r.Contains)+len(r.Extensions) == 0 {
+			return nil, fmt.Errorf("detect rule %d has no condition", i+1)
+		}
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("detect rule %d: %v", i+1, err)
+		}
+	}
+	if err := def.Commands.compile(); err != nil {
+		return nil, err
+	}
+	for i := range def.Port {
+		if err := def.Port[i].compile(); err != nil {
+			return nil, fmt.Errorf("port %d: %v", i+1, err)
+		}
+	}
+	for i := range def.Artifacts {
+		a := &def.Artifacts[i]
+		if a.Name == "" {
+			return nil, fmt.Errorf("artifact %d has no name", i+1)
+		}
+		if err := a.compile(); err != nil {
+			return nil, fmt.Errorf("artifact %s: %v", a.Name, err)
+		}
+	}
+	return &def, nil
+}
+
+// compile checks the content patterns and normalizes the extensions
+func (m *DirMatch) compile() error {
+	for j := range m.Contains {
+		c := &m.Contains[j]
+		if c.File == "" {
+			return fmt.Errorf("contains needs a file")
+		}
+		re, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return err
+		}
+		c.re = re
+	}
+	for j, ext := range m.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			m.Extensions[j] = "." + ext
+		}
+	}
+	return nil
+}
+
+// ============================================================
+// Rule matching
+// ============================================================
+
+// probe inspects a directory for rules, reading each input at most once
+type probe struct {
+	dir      string
+	exists   map[string]bool
+	contents map[string]string
+	pkg      *PackageJSON
+	pkgRead  bool
+	exts     map[string]bool
+}
+
+func newProbe(dir string) *probe {
+	return &probe{dir: dir, exists: make(map[string]bool), contents: make(map[string]string)}
+}
+
+func (p *probe) hasFile(pattern string) bool {
+	found, ok := p.exists[pattern]
+	if !ok {
+		if strings.ContainsAny(pattern, "*?[") {
+			matches, _ := filepath.Glob(filepath.Join(p.dir, pattern))
+			found = len(matches) > 0
+		} else {
+			_, err := os.Stat(filepath.Join(p.dir, pattern))
+			found = err == nil
+		}
+		p.exists[pattern] = found
+	}
+	return found
+}
+
+func (p *probe) content(name string) string {
+	s, ok := p.contents[name]
+	if !ok {
+		if f, err := os.Open(filepath.Join(p.dir, name)); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(f, maxContentSize))
+			f.Close()
+			s = string(data)
+		}
+		p.contents[name] = s
+	}
+	return s
+}
+
+func (p *probe) packageJSON() *PackageJSON {
+	if !p.pkgRead {
+		p.pkg = ReadPackageJSON(p.dir)
+		p.pkgRead = true
+	}
+	return p.pkg
+}
+
+func (p *probe) extensions() map[string]bool {
+	if p.exts == nil {
+		p.exts = make(map[string]bool)
+		entries, _ := os.ReadDir(p.dir)
+		for _, e := range entries {
+			if !e.IsDir() {
+				p.exts[filepath.Ext(e.Name())] = true
+			}
+		}
+	}
+	return p.exts
+}
+
+// matches reports whether every condition set holds in the probed directory
+func (r *DirMatch) matches(p *probe) bool {
+	if len(r.Files) > 0 && !anyOf(r.Files, p.hasFile) {
+		return false
+	}
+	if len(r.Dependencies) > 0 && !hasDependency(p.packageJSON(), r.Dependencies) {
+		return false
+	}
+	for _, c := range r.Contains {
+		if c.re == nil || !p.hasFile(c.File) || !c.re.MatchString(p.content(c.File)) {
+			return false
+		}
+	}
+	if len(r.Extensions) > 0 && !anyOf(r.Extensions, func(ext string) bool { return p.extensions()[ext] }) {
+		return false
+	}
+	return true
+}
+
+// hasDependency reports whether package.json depends on any of the names
+func hasDependency(pkg *PackageJSON, names []string) bool {
+	if pkg == nil {
+		return false
+	}
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies} {
+		for dep := range deps {
+			for _, name := range names {
+				if ok, _ := path.Match(name, dep); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// holds reports whether the project meets every condition set. hasTool
+// reports whether a tool is installed.
+func (c *Condition) holds(p *Project, pr *probe, hasTool func(string) bool) bool {
+	if !c.matches(pr) {
+		return false
+	}
+	if len(c.Scripts) > 0 && !anyOf(c.Scripts, p.hasScript) {
+		return false
+	}
+	if len(c.ScriptUses) > 0 {
+		names := c.Scripts
+		if len(names) == 0 {
+			names = p.ScriptList()
+		}
+		if !anyOf(names, func(name string) bool { return containsAny(p.Scripts[name], c.ScriptUses) }) {
+			return false
+		}
+	}
+	if len(c.PackageManagers) > 0 && !slices.Contains(c.PackageManagers, p.GetPackageManager()) {
+		return false
+	}
+	for _, tool := range c.Tools {
+		if !hasTool(tool) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyOf(list []string, pred func(string) bool) bool {
+	for _, s := range list {
+		if pred(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,317 @@
+package projects
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// ==============================================================
+// Type Definition Tests
+// ==============================================================
+
+func TestBuiltinTypes(t *testing.T) {
+	builtins := []ProjectType{
+		TypeNextJS, TypeReact, TypeVue, TypeAngular, TypeNode, TypeGo,
+		TypePython, TypeRust, TypeLaravel, TypeDjango, TypeStatic, TypeNuxt,
+		TypeSvelte, TypeAstro, TypeJava, TypePHP, TypeCpp, TypeDocker,
+	}
+
+	for _, typ := range builtins {
+		def := LookupType(typ)
+		if def == nil {
+			t.Errorf("no definition for built-in type %q", typ)
+			continue
+		}
+		if def.Source != SourceBuiltin || len(def.Detect) == 0 {
+			t.Errorf("definition of %q = %+v", typ, def)
+		}
+	}
+	if len(Types()) != len(builtins) {
+		t.Errorf("len(Types()) = %d, want %d", len(Types()), len(builtins))
+	}
+	if TypeUnknown.String() != "Unknown" || TypeUnknown.Icon() != "[?]" {
+		t.Errorf("TypeUnknown = %q %q", TypeUnknown.String(), TypeUnknown.Icon())
+	}
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ProjectType
+		ok    bool
+	}{
+		{"go", TypeGo, true},
+		{"Next.js", TypeNextJS, true},
+		{" CPP ", TypeCpp, true},
+		{"c++", TypeCpp, true},
+		{"cobol", TypeUnknown, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseType(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseType(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLoadTypes_UserDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "dotnet.yml", `id: dotnet
+name: .NET
+detect:
+  - files: ["*.csproj"]
+    priority: 150
+commands:
+  start: dotnet run
+  build: dotnet publish -o out/{{name}}
+  test: dotnet test | tee test.log
+`)
+	createFile(t, dir, "go.yml", `id: go
+name: Golang
+icon: "[GL]"
+detect:
+  - files: [go.mod]
+    priority: 155
+`)
+	createFile(t, dir, "bad-id.yml", "id: Bad Id\n")
+	createFile(t, dir, "bad-regex.yml", "id: x\ndetect:\n  - contains: [{file: a, pattern: \"(\"}]\n")
+	createFile(t, dir, "empty-rule.yml", "id: y\ndetect:\n  - priority: 1\n")
+	createFile(t, dir, "z-dotnet.yaml", "id: dotnet\n")
+	createFile(t, dir, "notes.txt", "ignored")
+	useTypes(t, dir)
+
+	if errs := TypeErrors(); len(errs) != 4 {
+		t.Errorf("TypeErrors() = %v, want 4 errors", errs)
+	}
+
+	dotnet := LookupType("dotnet")
+	if dotnet == nil || dotnet.Icon != "[DO]" || dotnet.Source != filepath.Join(dir, "dotnet.yml") {
+		t.Fatalf("LookupType(dotnet) = %+v", dotnet)
+	}
+	if got := ProjectType("go").String(); got != "Golang" {
+		t.Errorf("overridden go type String() = %q, want Golang", got)
+	}
+
+	project := t.TempDir()
+	createFile(t, project, "Api.csproj", "<Project/>")
+	createFile(t, project, "main.go", "package main\n")
+	if got := Detect(project); got != "dotnet" {
+		t.Errorf("Detect() = %q, want dotnet", got)
+	}
+
+	// The overriding go type has no extension rule and no commands
+	goOnly := t.TempDir()
+	createFile(t, goOnly, "main.go", "package main\n")
+	if got := Detect(goOnly); got != TypeUnknown {
+		t.Errorf("Detect() = %q, want unknown without the built-in extension rule", got)
+	}
+	if bin, _ := (&Project{Type: TypeGo}).GetTestCommand(); bin != "" {
+		t.Errorf("GetTestCommand() = %q, want none from the overriding definition", bin)
+	}
+
+	p := &Project{Name: "My Api", Type: "dotnet"}
+	if bin, args := p.GetStartCommand(); bin != "dotnet" || !reflect.DeepEqual(args, []string{"run"}) {
+		t.Errorf("GetStartCommand() = %q %v", bin, args)
+	}
+	if bin, args := p.GetBuildCommand(); bin != "dotnet" || args[len(args)-1] != "out/My Api" {
+		t.Errorf("GetBuildCommand() = %q %v, want the name as one argument", bin, args)
+	}
+	if bin, _ := p.GetTestCommand(); runtime.GOOS != "windows" && bin != "sh" {
+		t.Errorf("GetTestCommand() = %q, want a shell for pipes", bin)
+	}
+}
+
+func TestLoadTypes_UserCommandsWin(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "python.yml", `id: python
+detect:
+  - files: [pyproject.toml]
+    priority: 140
+commands:
+  start: uvicorn app:main --reload
+  test: tox
+`)
+	useTypes(t, dir)
+
+	p := &Project{Name: "api", Type: TypePython, PackageManager: PMPoetry}
+	tests := []struct {
+		name     string
+		get      func() (string, []string)
+		wantBin  string
+		wantArgs []string
+	}{
+		{"start", p.GetStartCommand, "uvicorn", []string{"app:main", "--reload"}},
+		{"test", p.GetTestCommand, "tox", nil},
+		{"build_not_inherited", p.GetBuildCommand, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bin, args := tt.get(); bin != tt.wantBin || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got %q %v, want %q %v", bin, args, tt.wantBin, tt.wantArgs)
+			}
+		})
+	}
+
+	if _, _, ok := p.StartCommandOnPort(9000); ok {
+		t.Error("StartCommandOnPort() ok = true, want false for a user start command")
+	}
+}
+
+func TestLoadTypes_UserTypeSettings(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "deno.yml", `id: deno
+detect:
+  - files: [deno.json]
+commands:
+  start:
+    - scripts: [dev]
+      run: deno task dev
+    - files: [main.ts]
+      run: deno run -A main.ts --port={{port}}
+  lint: deno lint
+  format:
+    - tools: [deno]
+      run: deno fmt
+port:
+  - files: [fresh.config.ts]
+    port: 8000
+  - port: 8080
+artifacts:
+  - vendor
+  - name: node_modules
+    files: [package.json]
+`)
+	createFile(t, dir, "bad.yml", "id: bad\ncommands:\n  start:\n    - run: a\n      exec: b\n")
+	createFile(t, dir, "bad-port.yml", "id: bad-port\ncommands:\n  start:\n    - run: a\n      port: socket\n")
+	createFile(t, dir, "bad-args.yml", "id: bad-args\ncommands:\n  lint:\n    - run: a\n      args: [--fix]\n")
+	useTypes(t, dir)
+
+	if errs := TypeErrors(); len(errs) != 3 {
+		t.Errorf("TypeErrors() = %v, want 3 errors", errs)
+	}
+
+	project := t.TempDir()
+	createFile(t, project, "deno.json", "{}")
+	createFile(t, project, "main.ts", "")
+	p := &Project{Name: "api", Path: project, Type: "deno"}
+
+	if bin, args, ok := p.StartCommandOnPort(9000); !ok || bin != "deno" || args[len(args)-1] != "--port=9000" {
+		t.Errorf("StartCommandOnPort(9000) = %q %v %v, want the port in the run line", bin, args, ok)
+	}
+	if bin, args := p.GetStartCommand(); bin != "deno" || args[len(args)-1] != "--port=8080" {
+		t.Errorf("GetStartCommand() = %q %v, want the default port", bin, args)
+	}
+	p.Scripts = map[string]string{"dev": "deno run -A --watch main.ts"}
+	if bin, args := p.GetStartCommand(); bin != "deno" || !reflect.DeepEqual(args, []string{"task", "dev"}) {
+		t.Errorf("GetStartCommand() = %q %v, want the dev task", bin, args)
+	}
+
+	if c := p.TypeCommand("lint_fix", nil); c == nil || c.Bin != "deno" || !reflect.DeepEqual(c.Args, []string{"lint"}) {
+		t.Errorf("TypeCommand(lint_fix) = %+v, want the lint command", c)
+	}
+	if c := p.TypeCommand("format", func(string) bool { return false }); c != nil {
+		t.Errorf("TypeCommand(format) = %+v, want none without the tool", c)
+	}
+
+	rules := p.ArtifactRules()
+	if len(rules) != 1 || rules[0].Name != "vendor" {
+		t.Errorf("ArtifactRules() = %+v, want vendor only without package.json", rules)
+	}
+}
+
+func TestLoadTypes_Fingerprint(t *testing.T) {
+	dir := t.TempDir()
+	before := loadTypes(dir).fingerprint
+	if before != loadTypes(dir).fingerprint {
+		t.Error("fingerprint is not stable")
+	}
+
+	createFile(t, dir, "deno.yml", "id: deno\ndetect:\n  - files: [deno.json]\n")
+	if loadTypes(dir).fingerprint == before {
+		t.Error("fingerprint did not change with a new definition")
+	}
+}
+
+// ==============================================================
+// Rule Matching Tests
+// ==============================================================
+
+func TestRule_Matches(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "package.json", `{"dependencies": {"@nestjs/core": "^10"}}`)
+	createFile(t, dir, "config/app.php", "<?php return ['name' => 'Symfony'];\n")
+	createFile(t, dir, "main.rb", "puts 1\n")
+
+	tests := []struct {
+		name string
+		rule string
+		want bool
+	}{
+		{"file", "files: [package.json]", true},
+		{"file_glob", "files: ['*.rb']", true},
+		{"missing_file", "files: [Gemfile]", false},
+		{"dependency_glob", "dependencies: ['@nestjs/*']", true},
+		{"missing_dependency", "dependencies: [express]", false},
+		{"contains", "contains: [{file: config/app.php, pattern: 'Symf\\w+'}]", true},
+		{"contains_missing_file", "contains: [{file: nope.php, pattern: '.*'}]", false},
+		{"extension_without_dot", "extensions: [rb]", true},
+		{"all_conditions", "files: [package.json]\n    dependencies: ['@nestjs/core']\n    extensions: [.rb]", true},
+		{"one_condition_fails", "files: [package.json]\n    extensions: [.py]", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, err := parseTypeDef([]byte("id: test\ndetect:\n  - " + tt.rule + "\n"))
+			if err != nil {
+				t.Fatalf("parseTypeDef() error = %v", err)
+			}
+			if got := def.Detect[0].matches(newProbe(dir)); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantBin  string
+		wantArgs []string
+	}{
+		{"", "", nil},
+		{"go run .", "go", []string{"run", "."}},
+		{"  cargo   build  ", "cargo", []string{"build"}},
+		{"go build -o build/{{name}}", "go", []string{"build", "-o", "build/my app"}},
+		{"php -S localhost:{{port}} -t .", "php", []string{"-S", "localhost:8000", "-t", "."}},
+	}
+
+	vars := map[string]string{"name": "my app", "port": "8000"}
+	for _, tt := range tests {
+		bin, args := commandLine(tt.line, vars)
+		if bin != tt.wantBin || !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("commandLine(%q) = %q %v, want %q %v", tt.line, bin, args, tt.wantBin, tt.wantArgs)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		bin, args := commandLine("echo {{name}} > out.txt", vars)
+		if bin != "sh" || args[1] != "echo 'my app' > out.txt" {
+			t.Errorf("commandLine() = %q %v, want a quoted name in a shell line", bin, args)
+		}
+	}
+}
+
+// ==============================================================
+// Test Helpers
+// ==============================================================
+
+// useTypes loads the built-in definitions and the ones of dir for the test
+func useTypes(t *testing.T, dir string) {
+	t.Helper()
+	previous := loadedTypes()
+	types = loadTypes(dir)
+	t.Cleanup(func() { types = previous })
+}
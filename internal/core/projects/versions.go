@@ -0,0 +1,113 @@
+package projects
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// VersionPin is a tool version required by a project file
+type VersionPin struct {
+	Tool       string // node, npm, pnpm, yarn, go, python, rust, ...
+	Constraint string // exact version, prefix (20, 3.12), range (>=18 <21) or channel (stable)
+	Source     string // file the pin was read from
+}
+
+// asdfTools maps .tool-versions plugin names to tool names
+var asdfTools = map[string]string{
+	"nodejs": "node",
+	"golang": "go",
+}
+
+// VersionPins reads the toolchain version pins declared in the project
+func (p *Project) VersionPins() []VersionPin {
+	var pins []VersionPin
+	add := func(tool, constraint, source string) {
+		constraint = strings.TrimSpace(constraint)
+		if constraint != "" {
+			pins = append(pins, VersionPin{Tool: tool, Constraint: constraint, Source: source})
+		}
+	}
+
+	for _, name := range []string{".nvmrc", ".node-version"} {
+		if line := p.firstLine(name); line != "" {
+			add("node", strings.TrimPrefix(line, "v"), name)
+			break
+		}
+	}
+
+	if line := p.firstLine(".python-version"); line != "" {
+		add("python", line, ".python-version")
+	}
+
+	if f, err := os.Open(filepath.Join(p.Path, ".tool-versions")); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			tool := fields[0]
+			if mapped, ok := asdfTools[tool]; ok {
+				tool = mapped
+			}
+			add(tool, fields[1], ".tool-versions")
+		}
+		f.Close()
+	}
+
+	// The go directive is a minimum version
+	if data, err := os.ReadFile(filepath.Join(p.Path, "go.mod")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(line), "go "); ok {
+				add("go", ">="+strings.TrimSpace(v), "go.mod")
+				break
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(p.Path, "package.json")); err == nil {
+		var pkg struct {
+			Engines map[string]string `json:"engines"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			for _, tool := range []string{"node", "npm", "pnpm", "yarn"} {
+				add(tool, pkg.Engines[tool], "package.json")
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(p.Path, "rust-toolchain.toml")); err == nil {
+		var tc struct {
+			Toolchain struct {
+				Channel string `toml:"channel"`
+			} `toml:"toolchain"`
+		}
+		if toml.Unmarshal(data, &tc) == nil {
+			add("rust", tc.Toolchain.Channel, "rust-toolchain.toml")
+		}
+	} else if line := p.firstLine("rust-toolchain"); line != "" {
+		add("rust", line, "rust-toolchain")
+	}
+
+	return pins
+}
+
+// firstLine returns the first non-empty, non-comment line of a project file
+func (p *Project) firstLine(name string) string {
+	data, err := os.ReadFile(filepath.Join(p.Path, name))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package projects
+
+import "testing"
+
+// ==============================================================
+// Version Pin Tests
+// ==============================================================
+
+func TestProject_VersionPins(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []VersionPin
+	}{
+		{
+			name:  "nvmrc",
+			files: map[string]string{".nvmrc": "v20.11.0\n"},
+			want:  []VersionPin{{"node", "20.11.0", ".nvmrc"}},
+		},
+		{
+			name:  "nvmrc_wins_over_node_version",
+			files: map[string]string{".nvmrc": "18\n", ".node-version": "20\n"},
+			want:  []VersionPin{{"node", "18", ".nvmrc"}},
+		},
+		{
+			name:  "tool_versions",
+			files: map[string]string{".tool-versions": "# asdf\nnodejs 20.1.0\ngolang 1.22.3\nruby 3.3.0\n"},
+			want: []VersionPin{
+				{"node", "20.1.0", ".tool-versions"},
+				{"go", "1.22.3", ".tool-versions"},
+				{"ruby", "3.3.0", ".tool-versions"},
+			},
+		},
+		{
+			name:  "go_mod",
+			files: map[string]string{"go.mod": "module example.com/app\n\ngo 1.22\n\ntoolchain go1.22.3\n"},
+			want:  []VersionPin{{"go", ">=1.22", "go.mod"}},
+		},
+		{
+			name:  "engines",
+			files: map[string]string{"package.json": `{"engines": {"node": ">=18 <21", "pnpm": "^8"}}`},
+			want:  []VersionPin{{"node", ">=18 <21", "package.json"}, {"pnpm", "^8", "package.json"}},
+		},
+		{
+			name:  "rust_toolchain_toml",
+			files: map[string]string{"rust-toolchain.toml": "[toolchain]\nchannel = \"1.75.0\"\ncomponents = [\"clippy\"]\n"},
+			want:  []VersionPin{{"rust", "1.75.0", "rust-toolchain.toml"}},
+		},
+		{
+			name:  "rust_toolchain_legacy",
+			files: map[string]string{"rust-toolchain": "stable\n"},
+			want:  []VersionPin{{"rust", "stable", "rust-toolchain"}},
+		},
+		{
+			name:  "python_version",
+			files: map[string]string{".python-version": "3.12\n"},
+			want:  []VersionPin{{"python", "3.12", ".python-version"}},
+		},
+		{
+			name: "none",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				createFile(t, dir, name, content)
+			}
+
+			p := &Project{Path: dir}
+			got := p.VersionPins()
+			if len(got) != len(tt.want) {
+				t.Fatalf("VersionPins() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("VersionPins()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
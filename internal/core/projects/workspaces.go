@@ -0,0 +1,182 @@
+package projects
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace kinds
+const (
+	WorkspaceNpm   = "npm" // package.json workspaces (npm, yarn, bun)
+	WorkspacePnpm  = "pnpm"
+	WorkspaceGo    = "go"
+	WorkspaceCargo = "cargo"
+)
+
+// WorkspaceMembers returns the workspace kind of a monorepo root and the
+// directories of its members, or "" if dir is not a workspace root
+func WorkspaceMembers(dir string) (string, []string) {
+	if patterns := pnpmWorkspace(dir); patterns != nil {
+		return WorkspacePnpm, expandMembers(dir, patterns)
+	}
+	if patterns := npmWorkspaces(dir); patterns != nil {
+		return WorkspaceNpm, expandMembers(dir, patterns)
+	}
+	if patterns := goWork(dir); patterns != nil {
+		return WorkspaceGo, expandMembers(dir, patterns)
+	}
+	if patterns := cargoWorkspace(dir); patterns != nil {
+		return WorkspaceCargo, expandMembers(dir, patterns)
+	}
+	return "", nil
+}
+
+// pnpmWorkspace reads the packages of pnpm-workspace.yaml
+func pnpmWorkspace(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, "pnpm-workspace.yaml"))
+	if err != nil {
+		return nil
+	}
+	var ws struct {
+		Packages []string `yaml:"packages"`
+	}
+	if yaml.Unmarshal(data, &ws) != nil || ws.Packages == nil {
+		return nil
+	}
+	return ws.Packages
+}
+
+// npmWorkspaces reads package.json workspaces, either a list of patterns or
+// yarn's {"packages": [...]} form
+func npmWorkspaces(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	if json.Unmarshal(pkg.Workspaces, &patterns) == nil {
+		return patterns
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(pkg.Workspaces, &yarn) == nil {
+		return yarn.Packages
+	}
+	return nil
+}
+
+// goWork reads the use directives of go.work
+func goWork(dir string) []string {
+	f, err := os.Open(filepath.Join(dir, "go.work"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	uses := []string{}
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			uses = append(uses, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			uses = append(uses, strings.Trim(strings.TrimSpace(line[4:]), `"`))
+		}
+	}
+	return uses
+}
+
+// cargoWorkspace reads [workspace] members of Cargo.toml, with exclude
+// entries turned into negated patterns
+func cargoWorkspace(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return nil
+	}
+	var cargo struct {
+		Workspace *struct {
+			Members []string `toml:"members"`
+			Exclude []string `toml:"exclude"`
+		} `toml:"workspace"`
+	}
+	if toml.Unmarshal(data, &cargo) != nil || cargo.Workspace == nil {
+		return nil
+	}
+
+	patterns := append([]string{}, cargo.Workspace.Members...)
+	for _, e := range cargo.Workspace.Exclude {
+		patterns = append(patterns, "!"+e)
+	}
+	return patterns
+}
+
+// expandMembers resolves member glob patterns relative to root into sorted
+// directories. Patterns starting with "!" exclude matches, and "**" is
+// treated as a single level.
+func expandMembers(root string, patterns []string) []string {
+	excluded := make(map[string]bool)
+	for _, pattern := range patterns {
+		if rest, ok := strings.CutPrefix(pattern, "!"); ok {
+			for _, m := range globDirs(root, rest) {
+				excluded[m] = true
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var members []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		for _, m := range globDirs(root, pattern) {
+			if m == root || excluded[m] || seen[m] {
+				continue
+			}
+			seen[m] = true
+			members = append(members, m)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+func globDirs(root, pattern string) []string {
+	pattern = strings.ReplaceAll(filepath.FromSlash(strings.TrimPrefix(pattern, "./")), "**", "*")
+	matches, _ := filepath.Glob(filepath.Join(root, pattern))
+
+	var dirs []string
+	for _, m := range matches {
+		if filepath.Base(m) == "node_modules" {
+			continue
+		}
+		if info, err := os.Stat(m); err == nil && info.IsDir() {
+			dirs = append(dirs, filepath.Clean(m))
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,163 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// ==============================================================
+// WorkspaceMembers Tests
+// ==============================================================
+
+func TestWorkspaceMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		dirs     []string
+		wantKind string
+		want     []string
+	}{
+		{
+			name:     "npm",
+			files:    map[string]string{"package.json": `{"workspaces": ["packages/*", "apps/web"]}`},
+			dirs:     []string{"packages/ui", "packages/api", "apps/web", "apps/docs"},
+			wantKind: WorkspaceNpm,
+			want:     []string{"apps/web", "packages/api", "packages/ui"},
+		},
+		{
+			name:     "yarn_packages_object",
+			files:    map[string]string{"package.json": `{"workspaces": {"packages": ["libs/*"]}}`},
+			dirs:     []string{"libs/core"},
+			wantKind: WorkspaceNpm,
+			want:     []string{"libs/core"},
+		},
+		{
+			name: "pnpm_with_exclusion",
+			files: map[string]string{
+				"package.json":        `{"name": "root"}`,
+				"pnpm-workspace.yaml": "packages:\n  - 'packages/**'\n  - '!packages/legacy'\n",
+			},
+			dirs:     []string{"packages/ui", "packages/legacy"},
+			wantKind: WorkspacePnpm,
+			want:     []string{"packages/ui"},
+		},
+		{
+			name:     "go_work",
+			files:    map[string]string{"go.work": "go 1.22\n\nuse (\n\t./api // service\n\t./tools\n)\n\nuse ./cli\n"},
+			dirs:     []string{"api", "tools", "cli"},
+			wantKind: WorkspaceGo,
+			want:     []string{"api", "cli", "tools"},
+		},
+		{
+			name:     "cargo",
+			files:    map[string]string{"Cargo.toml": "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/old\"]\n"},
+			dirs:     []string{"crates/core", "crates/cli", "crates/old"},
+			wantKind: WorkspaceCargo,
+			want:     []string{"crates/cli", "crates/core"},
+		},
+		{
+			name:  "plain_package",
+			files: map[string]string{"package.json": `{"name": "app"}`, "Cargo.toml": "[package]\nname = \"app\"\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				createFile(t, dir, name, content)
+			}
+			for _, d := range tt.dirs {
+				os.MkdirAll(filepath.Join(dir, d), 0o755)
+			}
+
+			kind, members := WorkspaceMembers(dir)
+			if kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", kind, tt.wantKind)
+			}
+
+			var rel []string
+			for _, m := range members {
+				r, _ := filepath.Rel(dir, m)
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			if !reflect.DeepEqual(rel, tt.want) {
+				t.Errorf("members = %v, want %v", rel, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// ScanDepth Tests
+// ==============================================================
+
+func TestScanDepth_Groups(t *testing.T) {
+	root := t.TempDir()
+	createFile(t, root, "clients/acme/go.mod", "module acme\n")
+	createFile(t, root, "clients/globex/package.json", `{"name": "globex"}`)
+	createFile(t, root, "clients/globex/api/go.mod", "module api\n") // inside a project, not scanned
+	createFile(t, root, "archive/2019/old/go.mod", "module old\n")   // beyond max depth
+	createFile(t, root, "tool/main.go", "package main\n")
+	os.MkdirAll(filepath.Join(root, "empty"), 0o755)
+
+	list, err := ScanDepth(root, 2)
+	if err != nil {
+		t.Fatalf("ScanDepth() error = %v", err)
+	}
+
+	got := make(map[string]ProjectType)
+	for _, p := range list {
+		rel, _ := filepath.Rel(root, p.Path)
+		got[filepath.ToSlash(rel)] = p.Type
+	}
+	want := map[string]ProjectType{
+		"clients/acme":   TypeGo,
+		"clients/globex": TypeNode,
+		"tool":           TypeGo,
+		"archive":        TypeUnknown,
+		"empty":          TypeUnknown,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanDepth() = %v, want %v", got, want)
+	}
+
+	// archive/2019/old is found one level deeper
+	if deeper, _ := ScanDepth(root, 3); len(deeper) != 5 {
+		t.Errorf("ScanDepth(3) found %d projects, want 5", len(deeper))
+	}
+}
+
+func TestScanDepth_Workspace(t *testing.T) {
+	root := t.TempDir()
+	createFile(t, root, "mono/package.json", `{"name": "mono", "workspaces": ["packages/*"]}`)
+	createFile(t, root, "mono/packages/ui/package.json", `{"name": "ui"}`)
+	createFile(t, root, "mono/packages/api/package.json", `{"name": "api"}`)
+
+	list, err := ScanDepth(root, 2)
+	if err != nil {
+		t.Fatalf("ScanDepth() error = %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("ScanDepth() found %d top-level projects, want 1", len(list))
+	}
+
+	mono := list[0]
+	if mono.Workspace != WorkspaceNpm {
+		t.Errorf("Workspace = %q, want %q", mono.Workspace, WorkspaceNpm)
+	}
+	var names []string
+	for _, c := range mono.Children {
+		names = append(names, c.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"api", "ui"}) {
+		t.Errorf("Children = %v, want [api ui]", names)
+	}
+
+	flat := Flatten(list)
+	if len(flat) != 3 || flat[0].Name != "mono" {
+		t.Errorf("Flatten() = %d projects starting with %q, want mono and its 2 members", len(flat), flat[0].Name)
+	}
+}
@@ -2,13 +2,15 @@ package repl
 
 import (
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/chzyer/readline"
 	"github.com/spf13/cobra"
 
 	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/internal/core/scaffold"
 )
 
 // Completer provides tab completion for the REPL
@@ -24,29 +26,39 @@ func NewCompleter(rootCmd *cobra.Command, cfg *config.Config) *Completer {
 		rootCmd: rootCmd,
 		config:  cfg,
 	}
-	c.loadProjects()
+	c.loadProjects(projects.ScanAll)
 	return c
 }
 
-func (c *Completer) loadProjects() {
-	projectsDir := c.config.Paths.Projects
-	entries, err := os.ReadDir(projectsDir)
+// loadProjects completes project paths relative to their root, such as
+// clients/acme, and the names of registered projects
+func (c *Completer) loadProjects(scan func() ([]projects.Project, error)) {
+	c.projects = make([]string, 0)
+
+	registry, err := projects.DefaultRegistry()
 	if err != nil {
-		c.projects = make([]string, 0)
 		return
 	}
-
-	c.projects = make([]string, 0)
-	for _, entry := range entries {
-		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
-			c.projects = append(c.projects, entry.Name())
+	list, err := scan()
+	if err != nil {
+		return
+	}
+	// Favorites are offered first
+	flat := projects.Flatten(list)
+	sort.SliceStable(flat, func(i, j int) bool {
+		if flat[i].Favorite != flat[j].Favorite {
+			return flat[i].Favorite
 		}
+		return flat[i].Path < flat[j].Path
+	})
+	for _, p := range flat {
+		c.projects = append(c.projects, registry.Label(p.Path))
 	}
 }
 
-// RefreshProjects reloads the project list
+// RefreshProjects rescans the projects, rebuilding the project index
 func (c *Completer) RefreshProjects() {
-	c.loadProjects()
+	c.loadProjects(projects.RefreshAll)
 }
 
 // Do implements readline.AutoCompleter
@@ -94,7 +106,7 @@ func (c *Completer) getTopLevelCommands() []string {
 		"projects", "git", "ai", "agents", "workflow",
 		"secrets", "multi", "config", "theme", "analytics",
 		// Quick actions
-		"list", "start", "test", "build", "fix", "deploy", "do", "install", "lint", "clean",
+		"list", "start", "test", "build", "fix", "fmt", "deploy", "do", "install", "lint", "run", "clean", "backup", "doctor", "ports", "docker", "jump",
 		// REPL built-ins
 		"help", "exit", "quit", "clear", "cls", "history", "status", "reload",
 		"version", "cd",
@@ -119,6 +131,10 @@ func (c *Completer) getContextualCompletions(parts []string) []string {
 		return []string{"list", "run", "create", "show", "edit"}
 	case "secrets":
 		return []string{"init", "set", "get", "list", "export", "delete"}
+	case "docker":
+		return []string{"up", "down", "logs", "ps"}
+	case "backup":
+		return append([]string{"list", "restore", "prune"}, c.projects...)
 	case "multi":
 		return []string{"status", "pull", "audit", "run", "update"}
 	case "config":
@@ -127,7 +143,7 @@ func (c *Completer) getContextualCompletions(parts []string) []string {
 		return []string{"list", "set", "preview", "claude", "gemini", "matrix"}
 	case "analytics":
 		return []string{"today", "week", "month", "summary", "reset"}
-	case "start", "test", "build", "run", "open", "describe":
+	case "start", "test", "build", "run", "open", "describe", "jump":
 		return c.projects
 	case "cd":
 		return c.getDirectoryCompletions(parts)
@@ -138,15 +154,20 @@ func (c *Completer) getContextualCompletions(parts []string) []string {
 
 func (c *Completer) getProjectsCompletions(parts []string) []string {
 	if len(parts) == 1 {
-		return []string{"list", "new", "open", "find", "describe", "run", "delete"}
+		return []string{"list", "new", "open", "find", "describe", "run", "delete", "tag", "favorite", "note", "health", "stats", "graph", "dependents", "types", "add", "remove", "roots"}
 	}
 
 	subcmd := strings.ToLower(parts[1])
 	switch subcmd {
-	case "open", "run", "describe", "delete":
+	case "open", "run", "describe", "delete", "favorite", "note", "health", "stats", "dependents":
 		return c.projects
 	case "new":
 		return c.getTemplates()
+	case "tag":
+		if len(parts) == 2 {
+			return []string{"add", "rm", "ls"}
+		}
+		return c.projects
 	}
 
 	return nil
@@ -198,17 +219,10 @@ func (c *Completer) getAgentsCompletions(parts []string) []string {
 }
 
 func (c *Completer) getTemplates() []string {
-	templatesDir := filepath.Join(c.config.Paths.Bdev, "..", "bdev", "templates")
-	entries, err := os.ReadDir(templatesDir)
-	if err != nil {
-		return []string{"nextjs-starter", "python-cli", "go-cli", "laravel-api"}
-	}
-
-	templates := make([]string, 0)
-	for _, entry := range entries {
-		if entry.IsDir() {
-			templates = append(templates, entry.Name())
-		}
+	list := scaffold.List(c.config.TemplateDirs())
+	templates := make([]string, 0, len(list))
+	for _, t := range list {
+		templates = append(templates, t.Name)
 	}
 	return templates
 }
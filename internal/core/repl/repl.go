@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/badie/bdev/internal/core/config"
+	"github.com/badie/bdev/internal/core/projects"
 	"github.com/badie/bdev/internal/core/session"
 	"github.com/badie/bdev/pkg/ui"
 )
@@ -181,7 +182,8 @@ func (r *REPL) handleBuiltin(line string) bool {
 
 	case "reload":
 		r.config = config.Load()
-		fmt.Println(ui.Success("Configuration reloaded"))
+		r.completer.RefreshProjects()
+		fmt.Println(ui.Success("Configuration and projects reloaded"))
 		return true
 	}
 
@@ -189,10 +191,22 @@ func (r *REPL) handleBuiltin(line string) bool {
 	if strings.HasPrefix(lower, "cd ") {
 		dir := strings.TrimPrefix(line, "cd ")
 		dir = strings.TrimPrefix(dir, "CD ")
-		if err := os.Chdir(strings.TrimSpace(dir)); err != nil {
+		dir = strings.TrimSpace(dir)
+
+		// Not a directory: treat it as a project query, as `bdev jump` does
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			if path := r.findProject(dir); path != "" {
+				dir = path
+			}
+		}
+
+		if err := os.Chdir(dir); err != nil {
 			fmt.Println(ui.Error("Error: " + err.Error()))
 		} else {
 			r.session.UpdateCurrentDir()
+			if cwd, err := os.Getwd(); err == nil {
+				fmt.Println(ui.Muted(cwd))
+			}
 		}
 		return true
 	}
@@ -200,6 +214,23 @@ func (r *REPL) handleBuiltin(line string) bool {
 	return false
 }
 
+// findProject returns the path of the best project matching query, or ""
+func (r *REPL) findProject(query string) string {
+	list, err := projects.ScanAll()
+	if err != nil {
+		return ""
+	}
+	usage, _ := projects.LoadUsage(r.config.UsageFile())
+
+	matches := projects.Rank(projects.Flatten(list), query, usage)
+	if len(matches) == 0 {
+		return ""
+	}
+	path := matches[0].Project.Path
+	projects.RecordUse(r.config.UsageFile(), path)
+	return path
+}
+
 func (r *REPL) resolveAlias(line string) string {
 	parts := strings.Fields(line)
 	if len(parts) == 0 {
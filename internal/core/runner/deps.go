@@ -0,0 +1,832 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// Severity ranks an outdated dependency (by semver distance) or an advisory
+type Severity string
+
+const (
+	SeverityPatch    Severity = "patch"
+	SeverityMinor    Severity = "minor"
+	SeverityMajor    Severity = "major"
+	SeverityUnknown  Severity = "unknown"
+	SeverityLow      Severity = "low"
+	SeverityModerate Severity = "moderate"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// severityRank orders severities for sorting, most urgent highest
+var severityRank = map[Severity]int{
+	SeverityPatch: 1, SeverityMinor: 2, SeverityMajor: 3,
+	SeverityUnknown: 4, SeverityLow: 5, SeverityModerate: 6, SeverityHigh: 7, SeverityCritical: 8,
+}
+
+// Dependency is a row of an outdated or audit report
+type Dependency struct {
+	Name     string   `json:"name"`
+	Current  string   `json:"current,omitempty"`
+	Wanted   string   `json:"wanted,omitempty"` // newest version allowed by the manifest, or the first fixed version of an advisory
+	Latest   string   `json:"latest,omitempty"`
+	Severity Severity `json:"severity"`
+	Advisory string   `json:"advisory,omitempty"` // advisory ID, audit reports only
+	Title    string   `json:"title,omitempty"`
+}
+
+// DepsReport is the normalized result of an outdated or audit run
+type DepsReport struct {
+	Project      string       `json:"project"`
+	Tool         string       `json:"tool"`
+	Dependencies []Dependency `json:"dependencies"`
+	Error        string       `json:"error,omitempty"` // why the project could not be checked
+}
+
+// Sort orders dependencies by severity, most urgent first, then by name
+func (d *DepsReport) Sort() {
+	sort.SliceStable(d.Dependencies, func(i, j int) bool {
+		a, b := d.Dependencies[i], d.Dependencies[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		return a.Name < b.Name
+	})
+}
+
+// depsSpec describes an ecosystem tool invocation and its output parser
+type depsSpec struct {
+	tool  string
+	cmd   toolCommand
+	parse func(io.Reader) ([]Dependency, error)
+}
+
+// Outdated lists dependencies with newer versions available
+func (r *Runner) Outdated() (*DepsReport, error) {
+	spec, err := r.outdatedSpec()
+	if err != nil {
+		return nil, err
+	}
+	return r.runDeps(spec)
+}
+
+// Audit lists dependencies with known vulnerabilities
+func (r *Runner) Audit() (*DepsReport, error) {
+	spec, err := r.auditSpec()
+	if err != nil {
+		return nil, err
+	}
+	return r.runDeps(spec)
+}
+
+func (r *Runner) runDeps(spec *depsSpec) (*DepsReport, error) {
+	if err := r.ensureTool(&spec.cmd); err != nil {
+		return nil, err
+	}
+
+	cmd := r.command(spec.cmd.bin, spec.cmd.args)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stdin = nil
+
+	// Most of these tools exit non-zero when they find something
+	runErr := cmd.Run()
+
+	deps, err := spec.parse(&stdout)
+	if err != nil {
+		if runErr != nil {
+			return nil, exitError(runErr)
+		}
+		return nil, fmt.Errorf("failed to parse %s output: %w", spec.tool, err)
+	}
+
+	report := &DepsReport{Project: r.Project.Name, Tool: spec.tool, Dependencies: deps}
+	report.Sort()
+	return report, nil
+}
+
+func (r *Runner) outdatedSpec() (*depsSpec, error) {
+	switch r.deps() {
+	case projects.DepsNpm:
+		switch pm := r.Project.GetPackageManager(); pm {
+		case projects.PMPnpm:
+			return &depsSpec{tool: "pnpm outdated", cmd: toolCommand{bin: "pnpm", args: []string{"outdated", "--format", "json"}}, parse: ParseNpmOutdated}, nil
+		case projects.PMYarn:
+			return &depsSpec{tool: "yarn outdated", cmd: toolCommand{bin: "yarn", args: []string{"outdated", "--json"}}, parse: ParseYarnOutdated}, nil
+		case projects.PMBun:
+			return nil, fmt.Errorf("bun outdated has no machine-readable output")
+		default:
+			return &depsSpec{tool: "npm outdated", cmd: toolCommand{bin: "npm", args: []string{"outdated", "--json"}}, parse: ParseNpmOutdated}, nil
+		}
+
+	case projects.DepsGo:
+		return &depsSpec{tool: "go list", cmd: toolCommand{bin: "go", args: []string{"list", "-m", "-u", "-json", "all"}}, parse: ParseGoListUpdates}, nil
+
+	case projects.DepsPip:
+		bin, args := r.pip("list", "--outdated", "--format=json")
+		return &depsSpec{tool: "pip list", cmd: toolCommand{bin: bin, args: args}, parse: ParsePipOutdated}, nil
+
+	case projects.DepsCargo:
+		return &depsSpec{tool: "cargo outdated", cmd: toolCommand{bin: "cargo", args: []string{"outdated", "--root-deps-only", "--format", "json"}, tool: "cargo-outdated"}, parse: ParseCargoOutdated}, nil
+
+	case projects.DepsComposer:
+		return &depsSpec{tool: "composer outdated", cmd: toolCommand{bin: "composer", args: []string{"outdated", "--direct", "--format=json"}}, parse: ParseComposerOutdated}, nil
+	}
+
+	return nil, fmt.Errorf("dependency updates are not supported for %s projects", r.Project.Type)
+}
+
+func (r *Runner) auditSpec() (*depsSpec, error) {
+	switch r.deps() {
+	case projects.DepsNpm:
+		// yarn's audit output differs between major versions, npm's report is the stable one
+		if pm := r.Project.GetPackageManager(); pm == projects.PMPnpm {
+			return &depsSpec{tool: "pnpm audit", cmd: toolCommand{bin: "pnpm", args: []string{"audit", "--json"}}, parse: ParseNpmAudit}, nil
+		}
+		return &depsSpec{tool: "npm audit", cmd: toolCommand{bin: "npm", args: []string{"audit", "--json"}}, parse: ParseNpmAudit}, nil
+
+	case projects.DepsGo:
+		return &depsSpec{tool: "govulncheck", cmd: toolCommand{bin: "govulncheck", args: []string{"-json", "./..."}}, parse: ParseGovulncheck}, nil
+
+	case projects.DepsPip:
+		bin := r.localTool(filepath.Join(".venv", "bin"), "pip-audit")
+		return &depsSpec{tool: "pip-audit", cmd: toolCommand{bin: bin, args: []string{"-f", "json"}}, parse: ParsePipAudit}, nil
+
+	case projects.DepsCargo:
+		return &depsSpec{tool: "cargo audit", cmd: toolCommand{bin: "cargo", args: []string{"audit", "--json"}, tool: "cargo-audit"}, parse: ParseCargoAudit}, nil
+
+	case projects.DepsComposer:
+		return &depsSpec{tool: "composer audit", cmd: toolCommand{bin: "composer", args: []string{"audit", "--format=json"}}, parse: ParseComposerAudit}, nil
+	}
+
+	return nil, fmt.Errorf("dependency audits are not supported for %s projects", r.Project.Type)
+}
+
+// deps returns the dependency tools of the project type, see TypeDef.Deps
+func (r *Runner) deps() string {
+	if def := projects.LookupType(r.Project.Type); def != nil {
+		return def.Deps
+	}
+	return ""
+}
+
+// pip returns a pip invocation, preferring the project's virtualenv
+func (r *Runner) pip(args ...string) (string, []string) {
+	if r.Project.GetPackageManager() == projects.PMUv {
+		return "uv", append([]string{"pip"}, args...)
+	}
+	return r.localTool(filepath.Join(".venv", "bin"), "pip"), args
+}
+
+// ==============================================================
+// Outdated parsers
+// ==============================================================
+
+// ParseNpmOutdated parses `npm outdated --json` and `pnpm outdated --format json`
+func ParseNpmOutdated(r io.Reader) ([]Dependency, error) {
+	var out map[string]json.RawMessage
+	if err := decodeJSON(r, &out); err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		Current string `json:"current"`
+		Wanted  string `json:"wanted"`
+		Latest  string `json:"latest"`
+	}
+
+	deps := make([]Dependency, 0, len(out))
+	for name, raw := range out {
+		// Workspaces report one entry per dependent package
+		var entries []entry
+		var single entry
+		if err := json.Unmarshal(raw, &single); err == nil {
+			entries = []entry{single}
+		} else if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			deps = append(deps, outdated(name, e.Current, e.Wanted, e.Latest))
+		}
+	}
+	return deps, nil
+}
+
+// ParseYarnOutdated parses the table event of yarn v1's `outdated --json`
+func ParseYarnOutdated(r io.Reader) ([]Dependency, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+
+	deps := []Dependency{}
+	for scanner.Scan() {
+		var event struct {
+			Type string `json:"type"`
+			Data struct {
+				Body [][]string `json:"body"`
+			} `json:"data"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &event) != nil || event.Type != "table" {
+			continue
+		}
+		// Columns: Package, Current, Wanted, Latest, Package Type, URL
+		for _, row := range event.Data.Body {
+			if len(row) >= 4 {
+				deps = append(deps, outdated(row[0], row[1], row[2], row[3]))
+			}
+		}
+	}
+	return deps, scanner.Err()
+}
+
+// ParseGoListUpdates parses `go list -m -u -json all`, keeping direct dependencies
+func ParseGoListUpdates(r io.Reader) ([]Dependency, error) {
+	dec := json.NewDecoder(r)
+	deps := []Dependency{}
+	for {
+		var mod struct {
+			Path     string
+			Version  string
+			Main     bool
+			Indirect bool
+			Update   *struct{ Version string }
+		}
+		if err := dec.Decode(&mod); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if mod.Main || mod.Indirect || mod.Update == nil {
+			continue
+		}
+		// Go has no range notion, the minimal version is what the build uses
+		deps = append(deps, outdated(mod.Path, mod.Version, mod.Update.Version, mod.Update.Version))
+	}
+	return deps, nil
+}
+
+// ParsePipOutdated parses `pip list --outdated --format=json`
+func ParsePipOutdated(r io.Reader) ([]Dependency, error) {
+	var out []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Latest  string `json:"latest_version"`
+	}
+	if err := decodeJSON(r, &out); err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(out))
+	for _, p := range out {
+		deps = append(deps, outdated(p.Name, p.Version, "", p.Latest))
+	}
+	return deps, nil
+}
+
+// ParseCargoOutdated parses `cargo outdated --format json`
+func ParseCargoOutdated(r io.Reader) ([]Dependency, error) {
+	var out struct {
+		Dependencies []struct {
+			Name    string `json:"name"`
+			Project string `json:"project"`
+			Compat  string `json:"compat"`
+			Latest  string `json:"latest"`
+		} `json:"dependencies"`
+	}
+	if err := decodeJSON(r, &out); err != nil {
+		return nil, err
+	}
+
+	// cargo outdated prints "---" when there is no newer version
+	none := func(s string) string {
+		if s == "---" || s == "Removed" {
+			return ""
+		}
+		return s
+	}
+
+	deps := make([]Dependency, 0, len(out.Dependencies))
+	for _, d := range out.Dependencies {
+		deps = append(deps, outdated(d.Name, d.Project, none(d.Compat), none(d.Latest)))
+	}
+	return deps, nil
+}
+
+// ParseComposerOutdated parses `composer outdated --format=json`
+func ParseComposerOutdated(r io.Reader) ([]Dependency, error) {
+	var out struct {
+		Installed []struct {
+			Name         string `json:"name"`
+			Version      string `json:"version"`
+			Latest       string `json:"latest"`
+			LatestStatus string `json:"latest-status"`
+		} `json:"installed"`
+	}
+	if err := decodeJSON(r, &out); err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(out.Installed))
+	for _, p := range out.Installed {
+		if p.LatestStatus == "up-to-date" {
+			continue
+		}
+		wanted := ""
+		if p.LatestStatus == "semver-safe-update" {
+			wanted = p.Latest
+		}
+		deps = append(deps, outdated(p.Name, p.Version, wanted, p.Latest))
+	}
+	return deps, nil
+}
+
+// outdated builds a row, grading severity by the semver distance to latest
+func outdated(name, current, wanted, latest string) Dependency {
+	return Dependency{
+		Name:     name,
+		Current:  current,
+		Wanted:   wanted,
+		Latest:   latest,
+		Severity: semverDistance(current, latest),
+	}
+}
+
+// semverDistance returns the most significant version part that differs
+func semverDistance(current, latest string) Severity {
+	cur := versionParts(current)
+	lat := versionParts(latest)
+	if len(cur) == 0 || len(lat) == 0 {
+		return SeverityUnknown
+	}
+	for i, s := range []Severity{SeverityMajor, SeverityMinor} {
+		if i >= len(cur) || i >= len(lat) || cur[i] != lat[i] {
+			return s
+		}
+	}
+	return SeverityPatch
+}
+
+func versionParts(v string) []string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ".")
+}
+
+// ==============================================================
+// Audit parsers
+// ==============================================================
+
+// ParseNpmAudit parses `npm audit --json`, in the npm 7+ format or the
+// advisories format that npm 6 and pnpm print
+func ParseNpmAudit(r io.Reader) ([]Dependency, error) {
+	var out struct {
+		Advisories map[string]struct {
+			ModuleName      string `json:"module_name"`
+			Severity        string `json:"severity"`
+			Title           string `json:"title"`
+			URL             string `json:"url"`
+			PatchedVersions string `json:"patched_versions"`
+			Findings        []struct {
+				Version string `json:"version"`
+			} `json:"findings"`
+		} `json:"advisories"`
+		Vulnerabilities map[string]struct {
+			Name         string            `json:"name"`
+			Severity     string            `json:"severity"`
+			Range        string            `json:"range"`
+			Via          []json.RawMessage `json:"via"`
+			FixAvailable json.RawMessage   `json:"fixAvailable"`
+		} `json:"vulnerabilities"`
+	}
+	if err := decodeJSON(r, &out); err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(out.Vulnerabilities)+len(out.Advisories))
+	for _, a := range out.Advisories {
+		d := Dependency{
+			Name:     a.ModuleName,
+			Wanted:   a.PatchedVersions,
+			Severity: auditSeverity(a.Severity),
+			Advisory: advisoryID(a.URL),
+			Title:    a.Title,
+		}
+		if len(a.Findings) > 0 {
+			d.Current = a.Findings[0].Version
+		}
+		deps = append(deps, d)
+	}
+	for name, v := range out.Vulnerabilities {
+		d := Dependency{Name: name, Current: v.Range, Severity: auditSeverity(v.Severity)}
+
+		// via holds advisories, or names of vulnerable dependencies it pulls in
+		for _, raw := range v.Via {
+			var adv struct {
+				Title string `json:"title"`
+				URL   string `json:"url"`
+			}
+			if json.Unmarshal(raw, &adv) == nil && adv.Title != "" {
+				d.Title = adv.Title
+				d.Advisory = advisoryID(adv.URL)
+				break
+			}
+		}
+		if d.Title == "" {
+			var via string
+			if len(v.Via) > 0 && json.Unmarshal(v.Via[0], &via) == nil {
+				d.Title = "via " + via
+			}
+		}
+
+		var fix struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(v.FixAvailable, &fix) == nil {
+			d.Wanted = fix.Version
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// ParseGovulncheck parses the JSON message stream of `govulncheck -json`
+func ParseGovulncheck(r io.Reader) ([]Dependency, error) {
+	dec := json.NewDecoder(r)
+	titles := make(map[string]string)
+	seen := make(map[string]bool)
+	var findings []Dependency
+
+	for {
+		var msg struct {
+			OSV *struct {
+				ID      string `json:"id"`
+				Summary string `json:"summary"`
+			} `json:"osv"`
+			Finding *struct {
+				OSV          string `json:"osv"`
+				FixedVersion string `json:"fixed_version"`
+				Trace        []struct {
+					Module   string `json:"module"`
+					Version  string `json:"version"`
+					Function string `json:"function"`
+				} `json:"trace"`
+			} `json:"finding"`
+		}
+		if err := dec.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if msg.OSV != nil {
+			titles[msg.OSV.ID] = msg.OSV.Summary
+		}
+		f := msg.Finding
+		if f == nil || len(f.Trace) == 0 {
+			continue
+		}
+
+		key := f.OSV + "\x00" + f.Trace[0].Module
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		// govulncheck has no severity scores, a reachable function call is the strongest signal
+		severity := SeverityUnknown
+		if f.Trace[0].Function != "" {
+			severity = SeverityHigh
+		}
+		findings = append(findings, Dependency{
+			Name:     f.Trace[0].Module,
+			Current:  f.Trace[0].Version,
+			Wanted:   f.FixedVersion,
+			Severity: severity,
+			Advisory: f.OSV,
+		})
+	}
+
+	deps := make([]Dependency, 0, len(findings))
+	for _, d := range findings {
+		d.Title = titles[d.Advisory]
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// ParsePipAudit parses `pip-audit -f json`
+func ParsePipAudit(r io.Reader) ([]Dependency, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	type pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Vulns   []struct {
+			ID          string   `json:"id"`
+			FixVersions []string `json:"fix_versions"`
+			Description string   `json:"description"`
+		} `json:"vulns"`
+	}
+
+	// Newer releases wrap the list in {"dependencies": [...]}
+	var out struct {
+		Dependencies []pkg `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		if err := json.Unmarshal(data, &out.Dependencies); err != nil {
+			return nil, err
+		}
+	}
+
+	deps := []Dependency{}
+	for _, p := range out.Dependencies {
+		for _, v := range p.Vulns {
+			d := Dependency{Name: p.Name, Current: p.Version, Severity: SeverityUnknown, Advisory: v.ID, Title: firstSentence(v.Description)}
+			if len(v.FixVersions) > 0 {
+				d.Wanted = v.FixVersions[0]
+			}
+			deps = append(deps, d)
+		}
+	}
+	return deps, nil
+}
+
+// ParseCargoAudit parses `cargo audit --json`
+func ParseCargoAudit(r io.Reader) ([]Dependency, error) {
+	var out struct {
+		Vulnerabilities struct {
+			List []struct {
+				Advisory struct {
+					ID    string `json:"id"`
+					Title string `json:"title"`
+					CVSS  string `json:"cvss"`
+				} `json:"advisory"`
+				Package struct {
+					Name    string `json:"name"`
+					Version string `json:"version"`
+				} `json:"package"`
+				Versions struct {
+					Patched []string `json:"patched"`
+				} `json:"versions"`
+			} `json:"list"`
+		} `json:"vulnerabilities"`
+	}
+	if err := decodeJSON(r, &out); err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(out.Vulnerabilities.List))
+	for _, v := range out.Vulnerabilities.List {
+		d := Dependency{
+			Name:     v.Package.Name,
+			Current:  v.Package.Version,
+			Severity: SeverityUnknown,
+			Advisory: v.Advisory.ID,
+			Title:    v.Advisory.Title,
+		}
+		if len(v.Versions.Patched) > 0 {
+			d.Wanted = v.Versions.Patched[0]
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// ParseComposerAudit parses `composer audit --format=json`
+func ParseComposerAudit(r io.Reader) ([]Dependency, error) {
+	var out struct {
+		Advisories json.RawMessage `json:"advisories"`
+	}
+	if err := decodeJSON(r, &out); err != nil {
+		return nil, err
+	}
+
+	type advisory struct {
+		AdvisoryID       string `json:"advisoryId"`
+		PackageName      string `json:"packageName"`
+		AffectedVersions string `json:"affectedVersions"`
+		Title            string `json:"title"`
+		CVE              string `json:"cve"`
+		Severity         string `json:"severity"`
+	}
+
+	// Composer encodes an empty advisory map as []
+	advisories := make(map[string][]advisory)
+	if len(out.Advisories) > 0 && out.Advisories[0] == '{' {
+		if err := json.Unmarshal(out.Advisories, &advisories); err != nil {
+			return nil, err
+		}
+	}
+
+	deps := []Dependency{}
+	for _, list := range advisories {
+		for _, a := range list {
+			id := a.CVE
+			if id == "" {
+				id = a.AdvisoryID
+			}
+			deps = append(deps, Dependency{
+				Name:     a.PackageName,
+				Current:  a.AffectedVersions,
+				Severity: auditSeverity(a.Severity),
+				Advisory: id,
+				Title:    a.Title,
+			})
+		}
+	}
+	return deps, nil
+}
+
+// auditSeverity normalizes an advisory severity
+func auditSeverity(s string) Severity {
+	switch strings.ToLower(s) {
+	case "low", "info":
+		return SeverityLow
+	case "moderate", "medium":
+		return SeverityModerate
+	case "high":
+		return SeverityHigh
+	case "critical":
+		return SeverityCritical
+	}
+	return SeverityUnknown
+}
+
+// ==============================================================
+// Helpers
+// ==============================================================
+
+// decodeJSON decodes a JSON document, treating empty output as an empty result
+func decodeJSON(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// advisoryID returns the last element of an advisory URL, e.g. the GHSA ID
+func advisoryID(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+// firstSentence shortens an advisory description to its first sentence
+func firstSentence(s string) string {
+	if i := strings.Index(s, ". "); i > 0 {
+		return s[:i+1]
+	}
+	return s
+}
+
+// WriteDepsReports prints reports as a table, or as JSON, followed by the
+// projects that could not be checked. It returns an error when there are any.
+func WriteDepsReports(w io.Writer, reports []*DepsReport, asJSON bool) error {
+	var failed []*DepsReport
+	for _, rep := range reports {
+		if rep.Error != "" {
+			failed = append(failed, rep)
+		}
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		if len(reports) == 1 && len(failed) == 0 {
+			fmt.Fprintf(w, "%s %s\n\n", ui.Muted("Tool:"), reports[0].Tool)
+		}
+		if len(failed) < len(reports) {
+			PrintDepsReports(w, reports)
+		}
+		if len(failed) > 0 {
+			if len(failed) < len(reports) {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, ui.Warning(fmt.Sprintf("%d project(s) could not be checked:", len(failed))))
+			for _, rep := range failed {
+				fmt.Fprintf(w, "  %s %s %s\n", ui.Error(ui.ActiveGlyphs.Cross), ui.Bold(rep.Project), ui.Muted(rep.Error))
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d project(s) could not be checked", len(failed), len(reports))
+	}
+	return nil
+}
+
+// PrintDepsReports prints reports as one table. The project column is shown
+// when more than one project is included. Failed reports are left out.
+func PrintDepsReports(w io.Writer, reports []*DepsReport) {
+	header := []string{"PACKAGE", "CURRENT", "WANTED", "LATEST", "SEVERITY"}
+	checked := 0
+	for _, rep := range reports {
+		if rep.Error == "" {
+			checked++
+		}
+	}
+	multi := checked > 1
+	if multi {
+		header = append([]string{"PROJECT"}, header...)
+	}
+
+	type row struct {
+		cells []string
+		dep   Dependency
+	}
+	var rows []row
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len(h)
+	}
+	for _, rep := range reports {
+		for _, d := range rep.Dependencies {
+			cells := []string{d.Name, dash(d.Current), dash(d.Wanted), dash(d.Latest), string(d.Severity)}
+			if multi {
+				cells = append([]string{rep.Project}, cells...)
+			}
+			for i, c := range cells {
+				widths[i] = max(widths[i], len(c))
+			}
+			rows = append(rows, row{cells, d})
+		}
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(w, ui.Success("No issues found"))
+		return
+	}
+
+	// Pad before coloring, escape codes would throw the widths off
+	pad := func(i int, s string) string { return fmt.Sprintf("%-*s  ", widths[i], s) }
+
+	var line strings.Builder
+	for i, h := range header {
+		line.WriteString(pad(i, h))
+	}
+	fmt.Fprintln(w, ui.Bold(strings.TrimRight(line.String()+"ADVISORY", " ")))
+
+	sev := len(header) - 1
+	for _, r := range rows {
+		line.Reset()
+		for i, c := range r.cells {
+			if i == sev {
+				line.WriteString(colorSeverity(r.dep.Severity, pad(i, c)))
+				continue
+			}
+			line.WriteString(pad(i, c))
+		}
+		line.WriteString(advisoryLabel(r.dep))
+		fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	}
+}
+
+func advisoryLabel(d Dependency) string {
+	if d.Advisory == "" {
+		return ""
+	}
+	if d.Title == "" {
+		return d.Advisory
+	}
+	return d.Advisory + " " + ui.Muted(d.Title)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func colorSeverity(s Severity, text string) string {
+	switch s {
+	case SeverityCritical, SeverityHigh, SeverityMajor:
+		return ui.Error(text)
+	case SeverityModerate, SeverityMinor:
+		return ui.Warning(text)
+	}
+	return ui.Muted(text)
+}
@@ -0,0 +1,354 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Recorded tool outputs
+// ==============================================================
+
+const npmOutdatedOutput = `{
+  "react": {"current": "18.2.0", "wanted": "18.3.1", "latest": "19.0.0", "dependent": "web", "location": "node_modules/react"},
+  "lodash": {"current": "4.17.20", "wanted": "4.17.21", "latest": "4.17.21", "dependent": "web"},
+  "vite": {"current": "5.0.0", "wanted": "5.4.2", "latest": "5.4.2", "dependent": "web"}
+}`
+
+const yarnOutdatedOutput = `{"type":"info","data":"Color legend : ..."}
+{"type":"table","data":{"head":["Package","Current","Wanted","Latest","Package Type","URL"],"body":[["axios","0.27.2","0.27.2","1.6.0","dependencies","https://axios-http.com"]]}}
+`
+
+const goListOutput = `{"Path": "example.com/app", "Main": true, "GoVersion": "1.22"}
+{"Path": "github.com/spf13/cobra", "Version": "v1.7.0", "Update": {"Path": "github.com/spf13/cobra", "Version": "v1.8.0"}}
+{"Path": "golang.org/x/sys", "Version": "v0.10.0", "Indirect": true, "Update": {"Path": "golang.org/x/sys", "Version": "v0.20.0"}}
+{"Path": "gopkg.in/yaml.v3", "Version": "v3.0.1"}
+`
+
+const pipOutdatedOutput = `[{"name": "Django", "version": "4.2.7", "latest_version": "5.0.1", "latest_filetype": "wheel"},
+{"name": "requests", "version": "2.31.0", "latest_version": "2.31.1", "latest_filetype": "wheel"}]`
+
+const cargoOutdatedOutput = `{"crate_name": "app", "dependencies": [
+  {"name": "serde", "project": "1.0.190", "compat": "1.0.195", "latest": "1.0.195", "kind": "Normal", "platform": null},
+  {"name": "clap", "project": "3.2.25", "compat": "---", "latest": "4.4.18", "kind": "Normal", "platform": null}
+]}`
+
+const composerOutdatedOutput = `{"installed": [
+  {"name": "laravel/framework", "version": "v10.48.4", "latest": "v11.0.7", "latest-status": "update-possible", "description": "The Laravel Framework."},
+  {"name": "guzzlehttp/guzzle", "version": "7.8.0", "latest": "7.8.1", "latest-status": "semver-safe-update"},
+  {"name": "monolog/monolog", "version": "3.5.0", "latest": "3.5.0", "latest-status": "up-to-date"}
+]}`
+
+const npmAuditOutput = `{
+  "auditReportVersion": 2,
+  "vulnerabilities": {
+    "semver": {
+      "name": "semver", "severity": "moderate", "isDirect": false,
+      "via": [{"source": 1096482, "name": "semver", "title": "semver vulnerable to Regular Expression Denial of Service", "url": "https://github.com/advisories/GHSA-c2qf-rxjj-qqgw", "severity": "moderate"}],
+      "range": "<5.7.2", "fixAvailable": {"name": "semver", "version": "7.5.4", "isSemVerMajor": true}
+    },
+    "make-dir": {
+      "name": "make-dir", "severity": "moderate", "isDirect": false,
+      "via": ["semver"], "range": "2.0.0 - 3.1.0", "fixAvailable": true
+    }
+  }
+}`
+
+const pnpmAuditOutput = `{"advisories": {"1096366": {"module_name": "follow-redirects", "severity": "high", "title": "Improper Input Validation", "url": "https://github.com/advisories/GHSA-jchw-25xp-jwwc", "patched_versions": ">=1.15.4", "findings": [{"version": "1.15.2"}]}}}`
+
+const govulncheckOutput = `{"config": {"protocol_version": "v1.0.0", "scanner_name": "govulncheck"}}
+{"osv": {"id": "GO-2024-2687", "summary": "HTTP/2 CONTINUATION flood in net/http"}}
+{"finding": {"osv": "GO-2024-2687", "fixed_version": "v0.23.0", "trace": [{"module": "golang.org/x/net", "version": "v0.17.0", "package": "golang.org/x/net/http2", "function": "Read"}]}}
+{"finding": {"osv": "GO-2024-2687", "fixed_version": "v0.23.0", "trace": [{"module": "golang.org/x/net", "version": "v0.17.0", "package": "golang.org/x/net/http2", "function": "Write"}]}}
+{"osv": {"id": "GO-2023-1988", "summary": "Improper rendering of text nodes"}}
+{"finding": {"osv": "GO-2023-1988", "fixed_version": "v0.13.0", "trace": [{"module": "golang.org/x/net", "version": "v0.17.0"}]}}
+`
+
+const pipAuditOutput = `{"dependencies": [
+  {"name": "flask", "version": "2.2.0", "vulns": [{"id": "PYSEC-2023-62", "fix_versions": ["2.2.5", "2.3.2"], "aliases": ["CVE-2023-30861"], "description": "Flask is a lightweight WSGI web application framework. When all of the following conditions are met..."}]},
+  {"name": "jinja2", "version": "3.1.3", "vulns": []}
+], "fixes": []}`
+
+const cargoAuditOutput = `{"database": {}, "vulnerabilities": {"found": true, "count": 1, "list": [
+  {"advisory": {"id": "RUSTSEC-2023-0071", "package": "rsa", "title": "Marvin Attack: potential key recovery through timing sidechannels"},
+   "versions": {"patched": [], "unaffected": []}, "package": {"name": "rsa", "version": "0.9.6"}}
+]}}`
+
+const composerAuditOutput = `{"advisories": {"symfony/http-kernel": [
+  {"advisoryId": "PKSA-2rx9-r5tw-8qxt", "packageName": "symfony/http-kernel", "affectedVersions": ">=6.0.0,<6.3.8", "title": "CVE-2023-46733: Possible session fixation", "cve": "CVE-2023-46733", "severity": "high"}
+]}, "abandoned": []}`
+
+// ==============================================================
+// Parser Tests
+// ==============================================================
+
+func TestDepsParsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		parse  func(string) ([]Dependency, error)
+		output string
+		want   map[string]Dependency // by name, only non-empty fields are compared
+	}{
+		{"npm_outdated", wrap(ParseNpmOutdated), npmOutdatedOutput, map[string]Dependency{
+			"react":  {Current: "18.2.0", Wanted: "18.3.1", Latest: "19.0.0", Severity: SeverityMajor},
+			"lodash": {Current: "4.17.20", Latest: "4.17.21", Severity: SeverityPatch},
+			"vite":   {Severity: SeverityMinor},
+		}},
+		{"yarn_outdated", wrap(ParseYarnOutdated), yarnOutdatedOutput, map[string]Dependency{
+			"axios": {Current: "0.27.2", Wanted: "0.27.2", Latest: "1.6.0", Severity: SeverityMajor},
+		}},
+		{"go_list", wrap(ParseGoListUpdates), goListOutput, map[string]Dependency{
+			"github.com/spf13/cobra": {Current: "v1.7.0", Latest: "v1.8.0", Severity: SeverityMinor},
+		}},
+		{"pip_outdated", wrap(ParsePipOutdated), pipOutdatedOutput, map[string]Dependency{
+			"Django":   {Current: "4.2.7", Latest: "5.0.1", Severity: SeverityMajor},
+			"requests": {Severity: SeverityPatch},
+		}},
+		{"cargo_outdated", wrap(ParseCargoOutdated), cargoOutdatedOutput, map[string]Dependency{
+			"serde": {Current: "1.0.190", Wanted: "1.0.195", Severity: SeverityPatch},
+			"clap":  {Current: "3.2.25", Latest: "4.4.18", Severity: SeverityMajor},
+		}},
+		{"composer_outdated", wrap(ParseComposerOutdated), composerOutdatedOutput, map[string]Dependency{
+			"laravel/framework": {Current: "v10.48.4", Latest: "v11.0.7", Severity: SeverityMajor},
+			"guzzlehttp/guzzle": {Wanted: "7.8.1", Severity: SeverityPatch},
+		}},
+		{"npm_audit", wrap(ParseNpmAudit), npmAuditOutput, map[string]Dependency{
+			"semver":   {Current: "<5.7.2", Wanted: "7.5.4", Severity: SeverityModerate, Advisory: "GHSA-c2qf-rxjj-qqgw"},
+			"make-dir": {Severity: SeverityModerate, Title: "via semver"},
+		}},
+		{"pnpm_audit", wrap(ParseNpmAudit), pnpmAuditOutput, map[string]Dependency{
+			"follow-redirects": {Current: "1.15.2", Wanted: ">=1.15.4", Severity: SeverityHigh, Advisory: "GHSA-jchw-25xp-jwwc"},
+		}},
+		{"pip_audit", wrap(ParsePipAudit), pipAuditOutput, map[string]Dependency{
+			"flask": {Current: "2.2.0", Wanted: "2.2.5", Advisory: "PYSEC-2023-62", Title: "Flask is a lightweight WSGI web application framework."},
+		}},
+		{"cargo_audit", wrap(ParseCargoAudit), cargoAuditOutput, map[string]Dependency{
+			"rsa": {Current: "0.9.6", Severity: SeverityUnknown, Advisory: "RUSTSEC-2023-0071"},
+		}},
+		{"composer_audit", wrap(ParseComposerAudit), composerAuditOutput, map[string]Dependency{
+			"symfony/http-kernel": {Severity: SeverityHigh, Advisory: "CVE-2023-46733"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps, err := tt.parse(tt.output)
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if len(deps) != len(tt.want) {
+				t.Fatalf("got %d dependencies, want %d: %+v", len(deps), len(tt.want), deps)
+			}
+			for _, d := range deps {
+				want, ok := tt.want[d.Name]
+				if !ok {
+					t.Errorf("unexpected dependency %q", d.Name)
+					continue
+				}
+				assertDependency(t, d, want)
+			}
+		})
+	}
+}
+
+func TestParseGovulncheck(t *testing.T) {
+	deps, err := ParseGovulncheck(strings.NewReader(govulncheckOutput))
+	if err != nil {
+		t.Fatalf("ParseGovulncheck() error = %v", err)
+	}
+	// Two traces of the same advisory collapse into one row
+	if len(deps) != 2 {
+		t.Fatalf("got %d findings, want 2: %+v", len(deps), deps)
+	}
+	if deps[0].Advisory != "GO-2024-2687" || deps[0].Severity != SeverityHigh || deps[0].Wanted != "v0.23.0" {
+		t.Errorf("called finding = %+v", deps[0])
+	}
+	if deps[0].Title != "HTTP/2 CONTINUATION flood in net/http" {
+		t.Errorf("Title = %q", deps[0].Title)
+	}
+	if deps[1].Severity != SeverityUnknown {
+		t.Errorf("module-level finding severity = %s, want unknown", deps[1].Severity)
+	}
+}
+
+func TestDepsParsers_Empty(t *testing.T) {
+	for name, parse := range map[string]func(string) ([]Dependency, error){
+		"npm":      wrap(ParseNpmOutdated),
+		"composer": wrap(ParseComposerAudit),
+	} {
+		deps, err := parse("")
+		if err != nil || len(deps) != 0 {
+			t.Errorf("%s: parse(\"\") = %v, %v, want no dependencies", name, deps, err)
+		}
+	}
+
+	// Composer prints an empty advisory map as a list
+	deps, err := ParseComposerAudit(strings.NewReader(`{"advisories": [], "abandoned": []}`))
+	if err != nil || len(deps) != 0 {
+		t.Errorf("ParseComposerAudit() = %v, %v, want no advisories", deps, err)
+	}
+}
+
+// ==============================================================
+// Report Tests
+// ==============================================================
+
+func TestDepsReport_Sort(t *testing.T) {
+	report := &DepsReport{Dependencies: []Dependency{
+		{Name: "b", Severity: SeverityPatch},
+		{Name: "a", Severity: SeverityMajor},
+		{Name: "c", Severity: SeverityCritical},
+		{Name: "d", Severity: SeverityMajor},
+	}}
+	report.Sort()
+
+	var names []string
+	for _, d := range report.Dependencies {
+		names = append(names, d.Name)
+	}
+	if got := strings.Join(names, ","); got != "c,a,d,b" {
+		t.Errorf("Sort() order = %s, want c,a,d,b", got)
+	}
+}
+
+func TestPrintDepsReports(t *testing.T) {
+	reports := []*DepsReport{
+		{Project: "web", Dependencies: []Dependency{{Name: "react", Current: "18.2.0", Latest: "19.0.0", Severity: SeverityMajor}}},
+		{Project: "api", Dependencies: []Dependency{{Name: "flask", Current: "2.2.0", Wanted: "2.2.5", Severity: SeverityUnknown, Advisory: "PYSEC-2023-62"}}},
+	}
+
+	var buf bytes.Buffer
+	PrintDepsReports(&buf, reports)
+	out := buf.String()
+	for _, want := range []string{"PROJECT", "web", "react", "19.0.0", "api", "PYSEC-2023-62"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	PrintDepsReports(&buf, reports[:1])
+	if strings.Contains(buf.String(), "PROJECT") {
+		t.Error("single-project table should not have a project column")
+	}
+}
+
+func TestWriteDepsReports(t *testing.T) {
+	reports := []*DepsReport{
+		{Project: "web", Tool: "npm outdated", Dependencies: []Dependency{{Name: "react", Current: "18.2.0", Latest: "19.0.0", Severity: SeverityMajor}}},
+		{Project: "api", Error: "pip-audit not found"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDepsReports(&buf, reports, false); err == nil {
+		t.Error("WriteDepsReports() error = nil, want an error for the failed project")
+	}
+	out := buf.String()
+	for _, want := range []string{"react", "could not be checked", "api", "pip-audit not found"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "PROJECT") {
+		t.Error("a single checked project should not have a project column")
+	}
+
+	buf.Reset()
+	if err := WriteDepsReports(&buf, reports, true); err == nil {
+		t.Error("WriteDepsReports(json) error = nil")
+	}
+	var decoded []DepsReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 2 || decoded[1].Error != "pip-audit not found" {
+		t.Errorf("JSON = %s, want both reports with the error", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteDepsReports(&buf, reports[:1], false); err != nil || !strings.Contains(buf.String(), "npm outdated") {
+		t.Errorf("WriteDepsReports(single) = %v:\n%s", err, buf.String())
+	}
+}
+
+// ==============================================================
+// Spec Tests
+// ==============================================================
+
+func TestRunner_depsSpecs(t *testing.T) {
+	tests := []struct {
+		name         string
+		project      projects.Project
+		wantOutdated string
+		wantAudit    string
+	}{
+		{"npm", projects.Project{Type: projects.TypeReact}, "npm outdated", "npm audit"},
+		{"pnpm", projects.Project{Type: projects.TypeVue, PackageManager: projects.PMPnpm}, "pnpm outdated", "pnpm audit"},
+		{"go", projects.Project{Type: projects.TypeGo}, "go list", "govulncheck"},
+		{"python", projects.Project{Type: projects.TypePython}, "pip list", "pip-audit"},
+		{"rust", projects.Project{Type: projects.TypeRust}, "cargo outdated", "cargo audit"},
+		{"laravel", projects.Project{Type: projects.TypeLaravel}, "composer outdated", "composer audit"},
+		{"static", projects.Project{Type: projects.TypeStatic}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.project.Path = t.TempDir()
+			r := &Runner{Project: &tt.project, Cwd: tt.project.Path}
+
+			outdated, err := r.outdatedSpec()
+			if tt.wantOutdated == "" {
+				if err == nil {
+					t.Error("outdatedSpec() expected error")
+				}
+			} else if err != nil || outdated.tool != tt.wantOutdated {
+				t.Errorf("outdatedSpec() = %v, %v, want %s", outdated, err, tt.wantOutdated)
+			}
+
+			audit, err := r.auditSpec()
+			if tt.wantAudit == "" {
+				if err == nil {
+					t.Error("auditSpec() expected error")
+				}
+			} else if err != nil || audit.tool != tt.wantAudit {
+				t.Errorf("auditSpec() = %v, %v, want %s", audit, err, tt.wantAudit)
+			}
+		})
+	}
+}
+
+func TestRunner_Outdated_MissingTool(t *testing.T) {
+	withoutTools(t)
+
+	dir := t.TempDir()
+	r := &Runner{Project: &projects.Project{Type: projects.TypeRust, Path: dir}, Cwd: dir}
+	_, err := r.Outdated()
+	if err == nil || !strings.Contains(err.Error(), "cargo install cargo-outdated") {
+		t.Errorf("Outdated() error = %v, want install hint", err)
+	}
+}
+
+// ==============================================================
+// Test Helpers
+// ==============================================================
+
+func wrap(parse func(io.Reader) ([]Dependency, error)) func(string) ([]Dependency, error) {
+	return func(s string) ([]Dependency, error) { return parse(strings.NewReader(s)) }
+}
+
+func assertDependency(t *testing.T, got, want Dependency) {
+	t.Helper()
+	check := func(field, g, w string) {
+		if w != "" && g != w {
+			t.Errorf("%s %s = %q, want %q", got.Name, field, g, w)
+		}
+	}
+	check("Current", got.Current, want.Current)
+	check("Wanted", got.Wanted, want.Wanted)
+	check("Latest", got.Latest, want.Latest)
+	check("Severity", string(got.Severity), string(want.Severity))
+	check("Advisory", got.Advisory, want.Advisory)
+	check("Title", got.Title, want.Title)
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SecretStore is the part of the vault used to resolve secrets
+type SecretStore interface {
+	Get(key string) (string, error)
+	List() ([]string, error)
+}
+
+// SecretSpec selects which vault secrets are injected into the child environment
+type SecretSpec struct {
+	Keys    []string          // env var names, resolved through Mapping or used as vault keys
+	All     bool              // inject every secret in the vault, and the Mapping entries
+	Mapped  bool              // inject every Mapping entry
+	Mapping map[string]string // env var name -> vault key
+}
+
+// Empty reports whether the spec selects no secrets, so the vault can stay locked
+func (s SecretSpec) Empty() bool {
+	return !s.All && len(s.Keys) == 0 && (!s.Mapped || len(s.Mapping) == 0)
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseEnvFile parses dotenv-style KEY=VALUE lines
+func ParseEnvFile(r io.Reader) (map[string]string, error) {
+	env := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, `"`):
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", lineNo)
+			}
+			value = unquoted
+		case strings.HasPrefix(value, "'"):
+			if len(value) < 2 || !strings.HasSuffix(value, "'") {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", lineNo)
+			}
+			value = value[1 : len(value)-1]
+		default:
+			// Unquoted values may carry a trailing comment
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = strings.TrimSpace(value[:idx])
+			}
+		}
+
+		env[key] = value
+	}
+
+	return env, scanner.Err()
+}
+
+// LoadEnvFile adds the variables from a dotenv file to the child environment
+func (r *Runner) LoadEnvFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	env, err := ParseEnvFile(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	for k, v := range env {
+		r.Env[k] = v
+	}
+	return nil
+}
+
+// InjectSecrets resolves the selected secrets and adds them to the child
+// environment. Values only live in memory; the injected names are returned.
+func (r *Runner) InjectSecrets(store SecretStore, spec SecretSpec) ([]string, error) {
+	wanted := make(map[string]string) // env var -> vault key
+
+	if spec.All {
+		keys, err := store.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			wanted[EnvName(key)] = key
+		}
+	}
+	if spec.All || spec.Mapped {
+		for name, key := range spec.Mapping {
+			wanted[name] = key
+		}
+	}
+	for _, name := range spec.Keys {
+		if key, ok := spec.Mapping[name]; ok {
+			wanted[name] = key
+		} else {
+			wanted[name] = name
+		}
+	}
+
+	names := make([]string, 0, len(wanted))
+	for name, key := range wanted {
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid environment variable name '%s'", name)
+		}
+
+		value, err := store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		r.Env[name] = value
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// EnvName converts a vault key such as "db.password" to an environment variable name
+func EnvName(key string) string {
+	var b strings.Builder
+	for i, c := range strings.ToUpper(key) {
+		switch {
+		case c >= 'A' && c <= 'Z', c == '_':
+			b.WriteRune(c)
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,206 @@
+package runner
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ==============================================================
+// ParseEnvFile Tests
+// ==============================================================
+
+func TestParseEnvFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "plain_and_quoted",
+			input: `# database
+DB_HOST=localhost
+export DB_PORT=5432
+DB_NAME = app # trailing comment
+DB_PASS="p@ss # not a comment\n"
+API_URL='http://localhost:8080'
+EMPTY=
+`,
+			want: map[string]string{
+				"DB_HOST": "localhost",
+				"DB_PORT": "5432",
+				"DB_NAME": "app",
+				"DB_PASS": "p@ss # not a comment\n",
+				"API_URL": "http://localhost:8080",
+				"EMPTY":   "",
+			},
+		},
+		{name: "missing_equals", input: "DB_HOST\n", wantErr: true},
+		{name: "invalid_name", input: "1DB=x\n", wantErr: true},
+		{name: "unterminated_quote", input: "KEY=\"value\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEnvFile(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEnvFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseEnvFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_LoadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".env.local", "PORT=3000\n")
+
+	r := &Runner{Env: map[string]string{"PORT": "80", "MODE": "dev"}}
+	if err := r.LoadEnvFile(dir + "/.env.local"); err != nil {
+		t.Fatalf("LoadEnvFile() error = %v", err)
+	}
+	if r.Env["PORT"] != "3000" || r.Env["MODE"] != "dev" {
+		t.Errorf("Env = %v, want PORT overridden and MODE kept", r.Env)
+	}
+
+	if err := r.LoadEnvFile(dir + "/missing.env"); err == nil {
+		t.Error("LoadEnvFile() should fail for a missing file")
+	}
+}
+
+// ==============================================================
+// InjectSecrets Tests
+// ==============================================================
+
+// fakeStore is an in-memory SecretStore
+type fakeStore map[string]string
+
+func (s fakeStore) Get(key string) (string, error) {
+	v, ok := s[key]
+	if !ok {
+		return "", fmt.Errorf("secret not found: %s", key)
+	}
+	return v, nil
+}
+
+func (s fakeStore) List() ([]string, error) {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func TestRunner_InjectSecrets(t *testing.T) {
+	store := fakeStore{
+		"DB_PASSWORD":     "hunter2",
+		"stripe.test_key": "sk_test",
+		"github-token":    "ghp_x",
+	}
+
+	tests := []struct {
+		name    string
+		spec    SecretSpec
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "keys",
+			spec: SecretSpec{Keys: []string{"DB_PASSWORD"}},
+			want: map[string]string{"DB_PASSWORD": "hunter2"},
+		},
+		{
+			name: "mapping",
+			spec: SecretSpec{Mapped: true, Mapping: map[string]string{"STRIPE_KEY": "stripe.test_key"}},
+			want: map[string]string{"STRIPE_KEY": "sk_test"},
+		},
+		{
+			name: "mapping_not_injected_without_opt_in",
+			spec: SecretSpec{Mapping: map[string]string{"STRIPE_KEY": "stripe.test_key"}},
+			want: map[string]string{},
+		},
+		{
+			name: "keys_resolved_through_mapping",
+			spec: SecretSpec{Keys: []string{"GH_TOKEN"}, Mapping: map[string]string{"GH_TOKEN": "github-token", "STRIPE_KEY": "stripe.test_key"}},
+			want: map[string]string{"GH_TOKEN": "ghp_x"},
+		},
+		{
+			name: "all",
+			spec: SecretSpec{All: true},
+			want: map[string]string{"DB_PASSWORD": "hunter2", "STRIPE_TEST_KEY": "sk_test", "GITHUB_TOKEN": "ghp_x"},
+		},
+		{
+			name:    "missing_key",
+			spec:    SecretSpec{Keys: []string{"NOPE"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_name",
+			spec:    SecretSpec{Mapped: true, Mapping: map[string]string{"bad-name": "DB_PASSWORD"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Runner{Env: make(map[string]string)}
+			names, err := r.InjectSecrets(store, tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InjectSecrets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(r.Env, tt.want) {
+				t.Errorf("Env = %v, want %v", r.Env, tt.want)
+			}
+			if len(names) != len(tt.want) {
+				t.Errorf("InjectSecrets() returned %v, want %d names", names, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSecretSpec_Empty(t *testing.T) {
+	mapping := map[string]string{"A": "a"}
+	tests := []struct {
+		name string
+		spec SecretSpec
+		want bool
+	}{
+		{"zero", SecretSpec{}, true},
+		{"mapping_only", SecretSpec{Mapping: mapping}, true},
+		{"mapping_opted_in", SecretSpec{Mapped: true, Mapping: mapping}, false},
+		{"opted_in_without_mapping", SecretSpec{Mapped: true}, true},
+		{"keys", SecretSpec{Keys: []string{"A"}, Mapping: mapping}, false},
+		{"all", SecretSpec{All: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.Empty(); got != tt.want {
+				t.Errorf("Empty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"DB_PASSWORD":     "DB_PASSWORD",
+		"stripe.test_key": "STRIPE_TEST_KEY",
+		"github-token":    "GITHUB_TOKEN",
+		"2fa":             "_2FA",
+	}
+	for in, want := range tests {
+		if got := EnvName(in); got != want {
+			t.Errorf("EnvName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// Format runs the project formatter. In check mode files are only verified.
+func (r *Runner) Format(check bool) error {
+	c, err := r.formatCommand(check)
+	if err != nil {
+		return err
+	}
+	if err := r.ensureTool(c); err != nil {
+		return err
+	}
+
+	title := "Formatting"
+	if check {
+		title = "Checking format of"
+	}
+	ui.PrintHeader(fmt.Sprintf("%s %s", title, r.Project.Name))
+	fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(c.bin), c.args)
+
+	if !c.failOnOutput {
+		return r.execute(c.bin, c.args)
+	}
+
+	var out bytes.Buffer
+	cmd := r.command(c.bin, c.args)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return exitError(err)
+	}
+	if files := strings.TrimSpace(out.String()); files != "" {
+		fmt.Fprintln(r.Stdout, files)
+		return fmt.Errorf("%d file(s) need formatting", strings.Count(files, "\n")+1)
+	}
+	return nil
+}
+
+// GetFormatCommand returns the format command
+func (r *Runner) GetFormatCommand(check bool) (string, []string, error) {
+	c, err := r.formatCommand(check)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.bin, c.args, nil
+}
+
+func (r *Runner) formatCommand(check bool) (*toolCommand, error) {
+	kind := "format"
+	if check {
+		kind = "format_check"
+	}
+	if c := r.Project.TypeCommand(kind, r.hasTool); c != nil {
+		return newToolCommand(c), nil
+	}
+	return nil, fmt.Errorf("no format command for %s", r.Project.Type)
+}
+
+// Fix formats the project and then runs the linter with auto-fix
+func (r *Runner) Fix() error {
+	if err := r.Format(false); err != nil {
+		return err
+	}
+	fmt.Println()
+	return r.Lint(true)
+}
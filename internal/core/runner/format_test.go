@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// GetFormatCommand Tests
+// ==============================================================
+
+func TestRunner_GetFormatCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "src/main.cpp", "")
+	writeFile(t, dir, "include/app.h", "")
+	writeFile(t, dir, "build/generated.cpp", "")
+	writeFile(t, dir, "README.md", "")
+
+	tests := []struct {
+		name           string
+		projectType    projects.ProjectType
+		packageManager projects.PackageManager
+		scripts        map[string]string
+		check          bool
+		wantBin        string
+		wantArgs       []string
+		wantErr        bool
+	}{
+		{name: "go_gofmt", projectType: projects.TypeGo, wantBin: "gofmt", wantArgs: []string{"-l", "-w", "."}},
+		{name: "go_gofmt_check", projectType: projects.TypeGo, check: true, wantBin: "gofmt", wantArgs: []string{"-l", "."}},
+		{name: "node_prettier", projectType: projects.TypeNode, wantBin: "npx", wantArgs: []string{"prettier", "--write", "."}},
+		{name: "node_prettier_check", projectType: projects.TypeReact, check: true, wantBin: "npx", wantArgs: []string{"prettier", "--check", "."}},
+		{name: "node_format_script", projectType: projects.TypeNode, scripts: map[string]string{"format": "biome format"}, wantBin: "npm", wantArgs: []string{"run", "format"}},
+		{name: "pnpm_prettier", projectType: projects.TypeVue, packageManager: projects.PMPnpm, wantBin: "pnpm", wantArgs: []string{"exec", "prettier", "--write", "."}},
+		{name: "python_ruff", projectType: projects.TypePython, wantBin: "ruff", wantArgs: []string{"format", "."}},
+		{name: "python_ruff_check", projectType: projects.TypePython, check: true, wantBin: "ruff", wantArgs: []string{"format", ".", "--check"}},
+		{name: "rust_fmt_check", projectType: projects.TypeRust, check: true, wantBin: "cargo", wantArgs: []string{"fmt", "--check"}},
+		{name: "laravel_pint", projectType: projects.TypeLaravel, wantBin: "pint", wantArgs: nil},
+		{name: "php_cs_fixer_check", projectType: projects.TypePHP, check: true, wantBin: "php-cs-fixer", wantArgs: []string{"fix", "--dry-run", "--diff"}},
+		{name: "cpp_clang_format", projectType: projects.TypeCpp, wantBin: "clang-format", wantArgs: []string{"-i", filepath.Join("include", "app.h"), filepath.Join("src", "main.cpp")}},
+		{name: "java_unsupported", projectType: projects.TypeJava, wantErr: true},
+	}
+
+	withoutTools(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Runner{
+				Project: &projects.Project{
+					Path:           dir,
+					Type:           tt.projectType,
+					PackageManager: tt.packageManager,
+					Scripts:        tt.scripts,
+				},
+				Cwd: dir,
+			}
+
+			bin, args, err := r.GetFormatCommand(tt.check)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("GetFormatCommand() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetFormatCommand() error = %v", err)
+			}
+			if bin != tt.wantBin || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("GetFormatCommand() = %s %v, want %s %v", bin, args, tt.wantBin, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRunner_GetFormatCommand_Black(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "pyproject.toml", "[tool.black]\nline-length = 100\n")
+
+	r := &Runner{Project: &projects.Project{Type: projects.TypePython, Path: dir}, Cwd: dir}
+	bin, args, _ := r.GetFormatCommand(true)
+	if bin != "black" || !reflect.DeepEqual(args, []string{".", "--check"}) {
+		t.Errorf("GetFormatCommand() = %s %v, want black . --check", bin, args)
+	}
+}
+
+func TestRunner_GetFormatCommand_Goimports(t *testing.T) {
+	lookPath = func(name string) (string, error) {
+		if name == "goimports" {
+			return "/usr/bin/goimports", nil
+		}
+		return "", fmt.Errorf("not found")
+	}
+	t.Cleanup(func() { lookPath = defaultLookPath })
+
+	r := &Runner{Project: &projects.Project{Type: projects.TypeGo}, Cwd: t.TempDir()}
+	if bin, _, _ := r.GetFormatCommand(false); bin != "goimports" {
+		t.Errorf("GetFormatCommand() bin = %q, want goimports when installed", bin)
+	}
+}
+
+// ==============================================================
+// Tool Detection Tests
+// ==============================================================
+
+func TestRunner_ensureTool(t *testing.T) {
+	withoutTools(t)
+
+	dir := t.TempDir()
+	writeFile(t, dir, "node_modules/.bin/prettier", "")
+	writeFile(t, dir, "vendor/bin/pint", "")
+	r := &Runner{Cwd: dir}
+
+	tests := []struct {
+		name     string
+		cmd      toolCommand
+		wantErr  bool
+		wantHint string
+	}{
+		{name: "node_local", cmd: toolCommand{bin: "npx", tool: "prettier"}},
+		{name: "vendor_path", cmd: toolCommand{bin: filepath.Join("vendor", "bin", "pint")}},
+		{name: "missing_with_hint", cmd: toolCommand{bin: "cargo", tool: "cargo-clippy"}, wantErr: true, wantHint: "rustup component add clippy"},
+		{name: "missing_bin", cmd: toolCommand{bin: "clang-format"}, wantErr: true, wantHint: "clang-format is not installed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.ensureTool(&tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ensureTool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantHint) {
+				t.Errorf("ensureTool() error = %q, want it to mention %q", err, tt.wantHint)
+			}
+		})
+	}
+}
+
+// withoutTools makes PATH lookups fail for the duration of the test
+func withoutTools(t *testing.T) {
+	t.Helper()
+	lookPath = func(name string) (string, error) { return "", fmt.Errorf("%s not found", name) }
+	t.Cleanup(func() { lookPath = defaultLookPath })
+}
@@ -0,0 +1,215 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/badie/bdev/internal/core/config"
+)
+
+// MaxTestRuns is the number of runs kept in a project's test history
+const MaxTestRuns = 50
+
+// TestRun is a recorded test run
+type TestRun struct {
+	Time     time.Time     `json:"time"`
+	Tool     string        `json:"tool"`
+	Duration time.Duration `json:"duration"`
+	Cases    []TestCase    `json:"cases"`
+
+	// Partial runs only ran some of the tests, e.g. with --rerun-failed
+	Partial bool `json:"partial,omitempty"`
+}
+
+// TestHistory holds the recorded runs of a project, oldest first
+type TestHistory struct {
+	Path string    `json:"-"`
+	Runs []TestRun `json:"runs"`
+}
+
+// TestStat aggregates the history of a single test
+type TestStat struct {
+	Name        string
+	Suite       string
+	Runs        int
+	Failures    int
+	Flips       int // pass/fail changes between consecutive runs
+	AvgDuration time.Duration
+	MaxDuration time.Duration
+	LastStatus  TestStatus
+}
+
+// TestHistoryDir returns the history directory for the project at path,
+// named after a hash of the absolute path so projects sharing a name in
+// different roots keep separate histories
+func TestHistoryDir(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(config.Get().Paths.Bdev, "cache", "tests", hex.EncodeToString(sum[:8]))
+}
+
+// LoadTestHistory reads the history in dir, returning an empty history if there is none
+func LoadTestHistory(dir string) (*TestHistory, error) {
+	h := &TestHistory{Path: filepath.Join(dir, "history.json")}
+
+	data, err := os.ReadFile(h.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Record appends a report as a new run, dropping the oldest runs past MaxTestRuns
+func (h *TestHistory) Record(report *TestReport) {
+	h.record(report, false)
+}
+
+// RecordPartial appends a report of a run of some of the tests. Stats leaves
+// partial runs out: a rerun of failed tests would skew their failure rates.
+func (h *TestHistory) RecordPartial(report *TestReport) {
+	h.record(report, true)
+}
+
+func (h *TestHistory) record(report *TestReport, partial bool) {
+	run := TestRun{
+		Time:     time.Now(),
+		Tool:     report.Tool,
+		Duration: report.Duration,
+		Cases:    make([]TestCase, len(report.Cases)),
+		Partial:  partial,
+	}
+	for i, c := range report.Cases {
+		// Failure output is only useful for the current run
+		c.Message = ""
+		run.Cases[i] = c
+	}
+
+	h.Runs = append(h.Runs, run)
+	if len(h.Runs) > MaxTestRuns {
+		h.Runs = h.Runs[len(h.Runs)-MaxTestRuns:]
+	}
+}
+
+// Save writes the history to disk
+func (h *TestHistory) Save() error {
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.Path, data, 0o644)
+}
+
+// LastFailed returns the tests that failed in the most recent run
+func (h *TestHistory) LastFailed() []TestCase {
+	if len(h.Runs) == 0 {
+		return nil
+	}
+
+	var failed []TestCase
+	for _, c := range h.Runs[len(h.Runs)-1].Cases {
+		if c.Status == TestFailed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// FullRuns returns the runs of the whole test suite, oldest first
+func (h *TestHistory) FullRuns() []TestRun {
+	var runs []TestRun
+	for _, run := range h.Runs {
+		if !run.Partial {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// Stats aggregates the last n full runs per test (all of them if n <= 0)
+func (h *TestHistory) Stats(n int) []TestStat {
+	runs := h.FullRuns()
+	if n > 0 && len(runs) > n {
+		runs = runs[len(runs)-n:]
+	}
+
+	index := make(map[string]*TestStat)
+	var order []string
+	total := make(map[string]time.Duration)
+
+	for _, run := range runs {
+		for _, c := range run.Cases {
+			if c.Status == TestSkipped {
+				continue
+			}
+
+			key := c.Suite + "\x00" + c.Name
+			st, ok := index[key]
+			if !ok {
+				st = &TestStat{Name: c.Name, Suite: c.Suite}
+				index[key] = st
+				order = append(order, key)
+			}
+
+			if st.Runs > 0 && st.LastStatus != c.Status {
+				st.Flips++
+			}
+			st.Runs++
+			if c.Status == TestFailed {
+				st.Failures++
+			}
+			if c.Duration > st.MaxDuration {
+				st.MaxDuration = c.Duration
+			}
+			total[key] += c.Duration
+			st.LastStatus = c.Status
+		}
+	}
+
+	stats := make([]TestStat, 0, len(order))
+	for _, key := range order {
+		st := index[key]
+		st.AvgDuration = total[key] / time.Duration(st.Runs)
+		stats = append(stats, *st)
+	}
+	return stats
+}
+
+// Flaky returns tests whose outcome flipped within the last n full runs, most flips first
+func (h *TestHistory) Flaky(n int) []TestStat {
+	var flaky []TestStat
+	for _, st := range h.Stats(n) {
+		if st.Flips > 0 {
+			flaky = append(flaky, st)
+		}
+	}
+	sort.SliceStable(flaky, func(i, j int) bool { return flaky[i].Flips > flaky[j].Flips })
+	return flaky
+}
+
+// Slowest returns up to limit tests ordered by average duration over the last n full runs
+func (h *TestHistory) Slowest(n, limit int) []TestStat {
+	stats := h.Stats(n)
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].AvgDuration > stats[j].AvgDuration })
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
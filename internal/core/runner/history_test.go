@@ -0,0 +1,178 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ==============================================================
+// TestHistory Tests
+// ==============================================================
+
+func TestTestHistory_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	h, err := LoadTestHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadTestHistory() error = %v", err)
+	}
+	if len(h.Runs) != 0 {
+		t.Fatalf("new history has %d runs, want 0", len(h.Runs))
+	}
+
+	h.Record(runOf(TestCase{Name: "TestA", Status: TestFailed, Message: "boom"}))
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadTestHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadTestHistory() error = %v", err)
+	}
+	if len(loaded.Runs) != 1 {
+		t.Fatalf("loaded %d runs, want 1", len(loaded.Runs))
+	}
+	if msg := loaded.Runs[0].Cases[0].Message; msg != "" {
+		t.Errorf("failure message should not be persisted, got %q", msg)
+	}
+}
+
+func TestTestHistory_Record_Trims(t *testing.T) {
+	h := &TestHistory{}
+	for i := 0; i < MaxTestRuns+5; i++ {
+		h.Record(runOf(TestCase{Name: "TestA", Status: TestPassed}))
+	}
+	if len(h.Runs) != MaxTestRuns {
+		t.Errorf("history has %d runs, want %d", len(h.Runs), MaxTestRuns)
+	}
+}
+
+func TestTestHistory_LastFailed(t *testing.T) {
+	h := &TestHistory{}
+	if got := h.LastFailed(); got != nil {
+		t.Errorf("LastFailed() on empty history = %v, want nil", got)
+	}
+
+	h.Record(runOf(
+		TestCase{Name: "TestA", Status: TestFailed},
+		TestCase{Name: "TestB", Status: TestFailed},
+	))
+	h.Record(runOf(
+		TestCase{Name: "TestA", Status: TestPassed},
+		TestCase{Name: "TestB", Status: TestFailed},
+	))
+
+	failed := h.LastFailed()
+	if len(failed) != 1 || failed[0].Name != "TestB" {
+		t.Errorf("LastFailed() = %v, want [TestB]", failed)
+	}
+}
+
+func TestTestHistory_Flaky(t *testing.T) {
+	h := &TestHistory{}
+	statuses := []struct{ flaky, stable TestStatus }{
+		{TestPassed, TestPassed},
+		{TestFailed, TestPassed},
+		{TestPassed, TestPassed},
+		{TestPassed, TestPassed},
+		{TestPassed, TestPassed},
+	}
+	for _, s := range statuses {
+		h.Record(runOf(
+			TestCase{Name: "TestFlaky", Status: s.flaky},
+			TestCase{Name: "TestStable", Status: s.stable},
+		))
+	}
+
+	tests := []struct {
+		name   string
+		window int
+		want   int
+	}{
+		{"all_runs", 0, 2},
+		{"window_with_flip", 4, 1},
+		{"window_after_flip", 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := h.Flaky(tt.window)
+			flips := 0
+			for _, st := range flaky {
+				if st.Name != "TestFlaky" {
+					t.Errorf("unexpected flaky test %s", st.Name)
+				}
+				flips = st.Flips
+			}
+			if flips != tt.want {
+				t.Errorf("Flaky(%d) flips = %d, want %d", tt.window, flips, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestHistory_PartialRunsSkipped(t *testing.T) {
+	h := &TestHistory{}
+	h.Record(runOf(TestCase{Name: "TestA", Status: TestFailed}, TestCase{Name: "TestB", Status: TestPassed}))
+	h.RecordPartial(runOf(TestCase{Name: "TestA", Status: TestPassed}))
+	h.Record(runOf(TestCase{Name: "TestA", Status: TestFailed}, TestCase{Name: "TestB", Status: TestPassed}))
+
+	if flaky := h.Flaky(0); len(flaky) != 0 {
+		t.Errorf("Flaky() = %v, want none without the rerun", flaky)
+	}
+	for _, st := range h.Stats(0) {
+		if st.Runs != 2 {
+			t.Errorf("%s Runs = %d, want 2 full runs", st.Name, st.Runs)
+		}
+	}
+	if len(h.FullRuns()) != 2 {
+		t.Errorf("FullRuns() = %d runs, want 2", len(h.FullRuns()))
+	}
+
+	// The rerun still decides what failed last
+	h.RecordPartial(runOf(TestCase{Name: "TestA", Status: TestPassed}))
+	if failed := h.LastFailed(); len(failed) != 0 {
+		t.Errorf("LastFailed() = %v, want none after a passing rerun", failed)
+	}
+}
+
+func TestTestHistoryDir(t *testing.T) {
+	a := TestHistoryDir(filepath.Join("work", "api"))
+	b := TestHistoryDir(filepath.Join("clients", "api"))
+	if a == b {
+		t.Errorf("TestHistoryDir() = %q for both api projects, want separate directories", a)
+	}
+	if again := TestHistoryDir(filepath.Join("work", "api")); again != a {
+		t.Errorf("TestHistoryDir() = %q, then %q", a, again)
+	}
+}
+
+func TestTestHistory_Slowest(t *testing.T) {
+	h := &TestHistory{}
+	h.Record(runOf(
+		TestCase{Name: "fast", Status: TestPassed, Duration: 10 * time.Millisecond},
+		TestCase{Name: "slow", Status: TestPassed, Duration: time.Second},
+	))
+	h.Record(runOf(
+		TestCase{Name: "fast", Status: TestPassed, Duration: 30 * time.Millisecond},
+		TestCase{Name: "slow", Status: TestPassed, Duration: 3 * time.Second},
+	))
+
+	slow := h.Slowest(0, 1)
+	if len(slow) != 1 || slow[0].Name != "slow" {
+		t.Fatalf("Slowest(0, 1) = %v, want [slow]", slow)
+	}
+	if slow[0].AvgDuration != 2*time.Second || slow[0].MaxDuration != 3*time.Second {
+		t.Errorf("slow avg/max = %v/%v, want 2s/3s", slow[0].AvgDuration, slow[0].MaxDuration)
+	}
+}
+
+// runOf builds a report from test cases
+func runOf(cases ...TestCase) *TestReport {
+	r := &TestReport{Tool: "go test"}
+	for _, c := range cases {
+		r.Add(c)
+	}
+	return r
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/badie/bdev/internal/core/ports"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// PortConflict describes a dev server port that is already taken
+type PortConflict struct {
+	Port    int
+	Free    int              // next free port, 0 if none was found
+	Movable bool             // whether the dev server can be started on another port
+	Holders []ports.Listener // processes listening on Port, when visible
+}
+
+// CheckPort reports whether the port the dev server will listen on is taken.
+// It returns nil when the port is free or the framework has no default port.
+func (r *Runner) CheckPort() *PortConflict {
+	port := r.devPort()
+	if port == 0 || ports.IsFree(port) {
+		return nil
+	}
+
+	holders, _ := ports.Who(port)
+	_, _, movable := r.Project.StartCommandOnPort(port)
+	return &PortConflict{Port: port, Free: ports.NextFree(port + 1), Movable: movable, Holders: holders}
+}
+
+// UsePort makes the dev server listen on port, through PORT (and
+// SERVER_PORT for the start commands reading it) and through the start
+// command arguments for servers ignoring them, see StartCommandOnPort
+func (r *Runner) UsePort(port int) {
+	r.port = port
+	r.Env["PORT"] = strconv.Itoa(port)
+	if c := r.Project.TypeCommand("start", r.hasTool); c != nil && c.Port == projects.PortServerEnv && r.Project.Overrides().Start == "" {
+		r.Env["SERVER_PORT"] = strconv.Itoa(port)
+	}
+}
+
+// startCommand returns the start command, listening on the port set by UsePort
+func (r *Runner) startCommand() (string, []string) {
+	if r.port != 0 {
+		if bin, args, ok := r.Project.StartCommandOnPort(r.port); ok {
+			return bin, args
+		}
+	}
+	return r.Project.GetStartCommand()
+}
+
+// devPort returns the port requested through PORT, or the framework default
+func (r *Runner) devPort() int {
+	for _, v := range []string{r.Env["PORT"], os.Getenv("PORT")} {
+		if port, err := strconv.Atoi(v); err == nil {
+			return port
+		}
+	}
+	// A custom start command may listen anywhere
+	if r.Project.Overrides().Start != "" {
+		return 0
+	}
+	return r.Project.DefaultPort()
+}
@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// CheckPort Tests
+// ==============================================================
+
+func TestRunner_CheckPort(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	r := New(&projects.Project{Type: projects.TypeNextJS, Path: t.TempDir()})
+	r.Env["PORT"] = strconv.Itoa(port)
+
+	conflict := r.CheckPort()
+	if conflict == nil {
+		t.Fatalf("CheckPort() = nil for bound port %d", port)
+	}
+	if conflict.Port != port {
+		t.Errorf("Port = %d, want %d", conflict.Port, port)
+	}
+	if conflict.Free == 0 || conflict.Free == port {
+		t.Errorf("Free = %d, want another free port", conflict.Free)
+	}
+
+	ln.Close()
+	if c := r.CheckPort(); c != nil {
+		t.Errorf("CheckPort() = %+v after the port was released", c)
+	}
+}
+
+func TestRunner_CheckPort_NoDefault(t *testing.T) {
+	t.Setenv("PORT", "")
+
+	r := New(&projects.Project{Type: projects.TypeGo, Path: t.TempDir()})
+	if c := r.CheckPort(); c != nil {
+		t.Errorf("CheckPort() = %+v, want nil for a project without a dev port", c)
+	}
+}
+
+func TestRunner_UsePort(t *testing.T) {
+	r := New(&projects.Project{Type: projects.TypeLaravel})
+	r.UsePort(8001)
+
+	if r.Env["PORT"] != "8001" || r.Env["SERVER_PORT"] != "8001" {
+		t.Errorf("Env = %v, want PORT and SERVER_PORT set", r.Env)
+	}
+	if got := r.devPort(); got != 8001 {
+		t.Errorf("devPort() = %d, want 8001", got)
+	}
+}
+
+func TestRunner_UsePort_StartCommand(t *testing.T) {
+	r := New(&projects.Project{Type: projects.TypeReact, Scripts: map[string]string{"dev": "vite"}})
+	if bin, args := r.startCommand(); bin != "npm" || strings.Join(args, " ") != "run dev" {
+		t.Errorf("startCommand() = %s %v before UsePort", bin, args)
+	}
+
+	// Vite ignores PORT, the port goes on the command line
+	r.UsePort(5174)
+	if bin, args := r.startCommand(); bin != "npm" || strings.Join(args, " ") != "run dev -- --port 5174" {
+		t.Errorf("startCommand() = %s %v, want the port flag", bin, args)
+	}
+}
@@ -0,0 +1,599 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// TestStatus is the outcome of a single test
+type TestStatus string
+
+const (
+	TestPassed  TestStatus = "passed"
+	TestFailed  TestStatus = "failed"
+	TestSkipped TestStatus = "skipped"
+)
+
+// TestCase is a single test result
+type TestCase struct {
+	Name     string        `json:"name"`
+	Suite    string        `json:"suite,omitempty"`
+	Status   TestStatus    `json:"status"`
+	Duration time.Duration `json:"duration"`
+	File     string        `json:"file,omitempty"`
+	Line     int           `json:"line,omitempty"`
+	Message  string        `json:"message,omitempty"`
+}
+
+// Location returns file:line when known
+func (c TestCase) Location() string {
+	if c.File == "" {
+		return ""
+	}
+	if c.Line > 0 {
+		return fmt.Sprintf("%s:%d", c.File, c.Line)
+	}
+	return c.File
+}
+
+// TestReport is a structured summary of a test run
+type TestReport struct {
+	Project  string        `json:"project,omitempty"`
+	Tool     string        `json:"tool"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Skipped  int           `json:"skipped"`
+	Duration time.Duration `json:"duration"`
+	Cases    []TestCase    `json:"cases"`
+
+	// Output is the compiler output of builds that failed, printed in full
+	Output []string `json:"output,omitempty"`
+}
+
+// Add appends a test case and updates the totals
+func (r *TestReport) Add(c TestCase) {
+	switch c.Status {
+	case TestPassed:
+		r.Passed++
+	case TestFailed:
+		r.Failed++
+	case TestSkipped:
+		r.Skipped++
+	}
+	r.Cases = append(r.Cases, c)
+}
+
+// Total returns the number of tests in the report
+func (r *TestReport) Total() int {
+	return r.Passed + r.Failed + r.Skipped
+}
+
+// Failures returns the failing test cases
+func (r *TestReport) Failures() []TestCase {
+	var failed []TestCase
+	for _, c := range r.Cases {
+		if c.Status == TestFailed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Slowest returns up to n test cases ordered by duration
+func (r *TestReport) Slowest(n int) []TestCase {
+	cases := make([]TestCase, len(r.Cases))
+	copy(cases, r.Cases)
+	sort.SliceStable(cases, func(i, j int) bool { return cases[i].Duration > cases[j].Duration })
+	if len(cases) > n {
+		cases = cases[:n]
+	}
+	return cases
+}
+
+// Print writes the summary table
+func (r *TestReport) Print(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.Bold("Test Summary"))
+	fmt.Fprintln(w, ui.Muted(ui.SeparatorLight))
+	fmt.Fprintf(w, "  %-10s %s\n", ui.Primary("Tool:"), r.Tool)
+	fmt.Fprintf(w, "  %-10s %s\n", ui.Primary("Passed:"), ui.Success(r.Passed))
+	fmt.Fprintf(w, "  %-10s %s\n", ui.Primary("Failed:"), failedColor(r.Failed))
+	fmt.Fprintf(w, "  %-10s %s\n", ui.Primary("Skipped:"), ui.Warning(r.Skipped))
+	fmt.Fprintf(w, "  %-10s %s\n", ui.Primary("Duration:"), r.Duration.Round(time.Millisecond))
+
+	if len(r.Output) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, ui.Bold("Build Errors"))
+		for _, out := range r.Output {
+			for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+				fmt.Fprintf(w, "  %s\n", line)
+			}
+		}
+	}
+
+	if failures := r.Failures(); len(failures) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, ui.Bold("Failures"))
+		for _, c := range failures {
+			fmt.Fprintf(w, "  %s %s", ui.Error(ui.ActiveGlyphs.Cross), c.Name)
+			if loc := c.Location(); loc != "" {
+				fmt.Fprintf(w, " %s", ui.Muted(loc))
+			}
+			fmt.Fprintln(w)
+			if c.Message != "" {
+				fmt.Fprintf(w, "      %s\n", ui.Muted(firstLine(c.Message)))
+			}
+		}
+	}
+
+	if slow := r.Slowest(5); len(slow) > 0 && slow[0].Duration > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, ui.Bold("Slowest"))
+		for _, c := range slow {
+			if c.Duration == 0 {
+				break
+			}
+			fmt.Fprintf(w, "  %8s  %s\n", c.Duration.Round(time.Millisecond), c.Name)
+		}
+	}
+}
+
+func failedColor(n int) string {
+	if n > 0 {
+		return ui.Error(n)
+	}
+	return ui.Success(n)
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+// locationPattern finds "file.ext:line" references in failure output
+var locationPattern = regexp.MustCompile(`([\w./\\-]+\.\w+):(\d+)`)
+
+// findLocation extracts the first file:line reference from text
+func findLocation(text string) (string, int) {
+	m := locationPattern.FindStringSubmatch(text)
+	if m == nil {
+		return "", 0
+	}
+	line, _ := strconv.Atoi(m[2])
+	return m[1], line
+}
+
+// ============================================================
+// go test -json
+// ============================================================
+
+type goTestEvent struct {
+	Action      string  `json:"Action"`
+	Package     string  `json:"Package"`
+	ImportPath  string  `json:"ImportPath"` // build-output and build-fail events
+	Test        string  `json:"Test"`
+	Elapsed     float64 `json:"Elapsed"`
+	Output      string  `json:"Output"`
+	FailedBuild string  `json:"FailedBuild"`
+}
+
+// ParseGoTestJSON parses the output of `go test -json`. Subtests are folded
+// into their top-level test, whose failure message holds their output. A
+// package that fails without a failing test, because it does not compile
+// or its TestMain exits, is reported as a failure named after the package.
+// Packages run in parallel, so the duration is left to the caller.
+func ParseGoTestJSON(r io.Reader) (*TestReport, error) {
+	report := &TestReport{Tool: "go test"}
+	output := make(map[string]*strings.Builder)
+	buildOutput := make(map[string]*strings.Builder)
+	failedPkgs := make(map[string]bool)
+	var buildFails []string
+	parsed := false
+
+	write := func(m map[string]*strings.Builder, key, s string) {
+		if m[key] == nil {
+			m[key] = &strings.Builder{}
+		}
+		m[key].WriteString(s)
+	}
+	text := func(m map[string]*strings.Builder, key string) string {
+		if m[key] == nil {
+			return ""
+		}
+		return m[key].String()
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev goTestEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		parsed = true
+
+		switch ev.Action {
+		case "build-output":
+			write(buildOutput, ev.ImportPath, ev.Output)
+			continue
+		case "build-fail":
+			buildFails = append(buildFails, ev.ImportPath)
+			continue
+		}
+
+		if ev.Test == "" {
+			switch ev.Action {
+			case "output":
+				write(output, ev.Package, ev.Output)
+			case "fail":
+				if failedPkgs[ev.Package] {
+					continue
+				}
+				failedPkgs[ev.Package] = true
+				msg := failureOutput(text(output, ev.Package))
+				if out := text(buildOutput, ev.FailedBuild); out != "" {
+					msg = buildMessage(out)
+					report.Output = append(report.Output, out)
+					delete(buildOutput, ev.FailedBuild)
+				}
+				c := TestCase{Name: ev.Package, Suite: ev.Package, Status: TestFailed, Message: msg}
+				c.File, c.Line = findLocation(msg)
+				report.Add(c)
+			}
+			continue
+		}
+
+		top, _, sub := strings.Cut(ev.Test, "/")
+		key := ev.Package + "\x00" + top
+		switch ev.Action {
+		case "output":
+			write(output, key, ev.Output)
+		case "pass", "fail", "skip":
+			if sub {
+				continue
+			}
+			c := TestCase{
+				Name:     ev.Test,
+				Suite:    ev.Package,
+				Status:   goStatus(ev.Action),
+				Duration: seconds(ev.Elapsed),
+			}
+			if c.Status == TestFailed {
+				failedPkgs[ev.Package] = true
+				c.Message = failureOutput(text(output, key))
+				c.File, c.Line = findLocation(c.Message)
+			}
+			report.Add(c)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !parsed {
+		return nil, fmt.Errorf("no go test events found")
+	}
+
+	// Builds not claimed by a package failure, such as a broken dependency
+	for _, path := range buildFails {
+		out := text(buildOutput, path)
+		if out == "" {
+			continue
+		}
+		msg := buildMessage(out)
+		pkg, _, _ := strings.Cut(path, " ")
+		c := TestCase{Name: pkg, Suite: pkg, Status: TestFailed, Message: msg}
+		c.File, c.Line = findLocation(msg)
+		report.Add(c)
+		report.Output = append(report.Output, out)
+		delete(buildOutput, path)
+	}
+	return report, nil
+}
+
+// buildMessage drops the "# package" header lines of compiler output
+func buildMessage(out string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if !strings.HasPrefix(line, "# ") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func goStatus(action string) TestStatus {
+	switch action {
+	case "pass":
+		return TestPassed
+	case "fail":
+		return TestFailed
+	}
+	return TestSkipped
+}
+
+// failureOutput strips the === RUN / --- FAIL framing lines from test output
+func failureOutput(out string) string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- ") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// ============================================================
+// JUnit XML (pytest --junitxml, artisan --log-junit)
+// ============================================================
+
+type junitSuites struct {
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Time   float64      `xml:"time,attr"`
+	Cases  []junitCase  `xml:"testcase"`
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ParseJUnitXML parses a JUnit XML report
+func ParseJUnitXML(r io.Reader) (*TestReport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var suites []junitSuite
+	var root junitSuites
+	if err := xml.Unmarshal(data, &root); err == nil && len(root.Suites) > 0 {
+		suites = root.Suites
+	} else {
+		var single junitSuite
+		if err := xml.Unmarshal(data, &single); err != nil {
+			return nil, fmt.Errorf("invalid junit xml: %w", err)
+		}
+		suites = []junitSuite{single}
+	}
+
+	report := &TestReport{Tool: "junit"}
+	for _, s := range suites {
+		addJUnitSuite(report, s)
+	}
+	return report, nil
+}
+
+func addJUnitSuite(report *TestReport, s junitSuite) {
+	report.Duration += seconds(s.Time)
+
+	for _, tc := range s.Cases {
+		c := TestCase{
+			Name:     tc.Name,
+			Suite:    tc.ClassName,
+			Status:   TestPassed,
+			Duration: seconds(tc.Time),
+			File:     tc.File,
+			Line:     tc.Line,
+		}
+
+		msg := tc.Failure
+		if msg == nil {
+			msg = tc.Error
+		}
+		switch {
+		case msg != nil:
+			c.Status = TestFailed
+			c.Message = strings.TrimSpace(msg.Message + "\n" + msg.Text)
+			if c.Line == 0 {
+				if file, line := findLocation(msg.Text); file != "" {
+					c.File, c.Line = file, line
+				}
+			}
+		case tc.Skipped != nil:
+			c.Status = TestSkipped
+			c.Message = tc.Skipped.Message
+		}
+
+		report.Add(c)
+	}
+
+	for _, nested := range s.Suites {
+		// Nested suites report their own time, avoid double counting
+		nested.Time = 0
+		addJUnitSuite(report, nested)
+	}
+}
+
+// ============================================================
+// jest --json / vitest --reporter=json
+// ============================================================
+
+type jestReport struct {
+	StartTime   int64            `json:"startTime"`
+	TestResults []jestFileResult `json:"testResults"`
+}
+
+type jestFileResult struct {
+	Name             string          `json:"name"`
+	StartTime        int64           `json:"startTime"`
+	EndTime          int64           `json:"endTime"`
+	AssertionResults []jestAssertion `json:"assertionResults"`
+}
+
+type jestAssertion struct {
+	FullName        string   `json:"fullName"`
+	Title           string   `json:"title"`
+	Status          string   `json:"status"`
+	Duration        *float64 `json:"duration"`
+	FailureMessages []string `json:"failureMessages"`
+	Location        *struct {
+		Line int `json:"line"`
+	} `json:"location"`
+}
+
+// ParseJestJSON parses the JSON report written by jest or vitest
+func ParseJestJSON(r io.Reader) (*TestReport, error) {
+	var jr jestReport
+	if err := json.NewDecoder(r).Decode(&jr); err != nil {
+		return nil, fmt.Errorf("invalid jest json: %w", err)
+	}
+
+	report := &TestReport{Tool: "jest"}
+	for _, file := range jr.TestResults {
+		if file.EndTime > file.StartTime {
+			report.Duration += time.Duration(file.EndTime-file.StartTime) * time.Millisecond
+		}
+
+		for _, a := range file.AssertionResults {
+			name := a.FullName
+			if name == "" {
+				name = a.Title
+			}
+
+			c := TestCase{Name: name, Suite: file.Name, File: file.Name}
+			switch a.Status {
+			case "passed":
+				c.Status = TestPassed
+			case "failed":
+				c.Status = TestFailed
+			default: // pending, skipped, todo, disabled
+				c.Status = TestSkipped
+			}
+			if a.Duration != nil {
+				c.Duration = time.Duration(*a.Duration * float64(time.Millisecond))
+			}
+			if a.Location != nil {
+				c.Line = a.Location.Line
+			}
+			if len(a.FailureMessages) > 0 {
+				c.Message = strings.Join(a.FailureMessages, "\n")
+			}
+
+			report.Add(c)
+		}
+	}
+
+	return report, nil
+}
+
+// ============================================================
+// cargo test
+// ============================================================
+
+var (
+	cargoResultLine = regexp.MustCompile(`^test (\S+) \.\.\. (ok|FAILED|ignored)`)
+	cargoPanicLine  = regexp.MustCompile(`^thread '([^']+)' panicked at ([^:]+):(\d+)`)
+	cargoFinished   = regexp.MustCompile(`finished in ([\d.]+)s`)
+)
+
+// ParseCargoTest parses the human-readable output of `cargo test`
+func ParseCargoTest(r io.Reader) (*TestReport, error) {
+	report := &TestReport{Tool: "cargo test"}
+	index := make(map[string]int)
+	var current string
+	var message strings.Builder
+	found := false
+
+	flush := func() {
+		if current == "" {
+			return
+		}
+		if i, ok := index[current]; ok {
+			report.Cases[i].Message = strings.TrimSpace(message.String())
+		}
+		current = ""
+		message.Reset()
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := cargoResultLine.FindStringSubmatch(line); m != nil {
+			found = true
+			status := TestPassed
+			switch m[2] {
+			case "FAILED":
+				status = TestFailed
+			case "ignored":
+				status = TestSkipped
+			}
+			index[m[1]] = len(report.Cases)
+			report.Add(TestCase{Name: m[1], Status: status})
+			continue
+		}
+
+		if strings.HasPrefix(line, "---- ") && strings.HasSuffix(line, " ----") {
+			flush()
+			current = strings.Fields(strings.TrimPrefix(line, "---- "))[0]
+			continue
+		}
+
+		if m := cargoPanicLine.FindStringSubmatch(line); m != nil {
+			if i, ok := index[m[1]]; ok {
+				report.Cases[i].File = m[2]
+				report.Cases[i].Line, _ = strconv.Atoi(m[3])
+			}
+		}
+
+		if m := cargoFinished.FindStringSubmatch(line); m != nil {
+			flush()
+			secs, _ := strconv.ParseFloat(m[1], 64)
+			report.Duration += seconds(secs)
+			continue
+		}
+
+		if current != "" {
+			if strings.HasPrefix(line, "failures:") {
+				flush()
+				continue
+			}
+			message.WriteString(line + "\n")
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no cargo test results found")
+	}
+	return report, nil
+}
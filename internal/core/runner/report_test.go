@@ -0,0 +1,381 @@
+package runner
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Recorded tool outputs
+// ==============================================================
+
+const goTestJSONOutput = `{"Action":"start","Package":"example.com/app"}
+{"Action":"run","Package":"example.com/app","Test":"TestAdd"}
+{"Action":"output","Package":"example.com/app","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestAdd","Output":"--- PASS: TestAdd (0.00s)\n"}
+{"Action":"pass","Package":"example.com/app","Test":"TestAdd","Elapsed":0.01}
+{"Action":"run","Package":"example.com/app","Test":"TestSub"}
+{"Action":"output","Package":"example.com/app","Test":"TestSub","Output":"=== RUN   TestSub\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestSub","Output":"    math_test.go:14: Sub(2, 1) = 3, want 1\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestSub","Output":"--- FAIL: TestSub (0.00s)\n"}
+{"Action":"fail","Package":"example.com/app","Test":"TestSub","Elapsed":0.25}
+{"Action":"run","Package":"example.com/app","Test":"TestTable"}
+{"Action":"run","Package":"example.com/app","Test":"TestTable/empty"}
+{"Action":"pass","Package":"example.com/app","Test":"TestTable/empty","Elapsed":0}
+{"Action":"run","Package":"example.com/app","Test":"TestTable/large"}
+{"Action":"skip","Package":"example.com/app","Test":"TestTable/large","Elapsed":0}
+{"Action":"pass","Package":"example.com/app","Test":"TestTable","Elapsed":0.02}
+{"Action":"run","Package":"example.com/app","Test":"TestSlow"}
+{"Action":"skip","Package":"example.com/app","Test":"TestSlow","Elapsed":0}
+{"Action":"fail","Package":"example.com/app","Elapsed":0.5}
+`
+
+// goTestBuildFailure is a module where app does not compile and util passes
+const goTestBuildFailure = `{"ImportPath":"example.com/app [example.com/app.test]","Action":"build-output","Output":"# example.com/app [example.com/app.test]\n"}
+{"ImportPath":"example.com/app [example.com/app.test]","Action":"build-output","Output":"app/math_test.go:5:28: undefined: Mul\n"}
+{"ImportPath":"example.com/app [example.com/app.test]","Action":"build-fail"}
+{"Action":"start","Package":"example.com/app"}
+{"Action":"output","Package":"example.com/app","Output":"FAIL\texample.com/app [build failed]\n"}
+{"Action":"fail","Package":"example.com/app","Elapsed":0,"FailedBuild":"example.com/app [example.com/app.test]"}
+{"Action":"start","Package":"example.com/util"}
+{"Action":"run","Package":"example.com/util","Test":"TestTrim"}
+{"Action":"pass","Package":"example.com/util","Test":"TestTrim","Elapsed":0}
+{"Action":"pass","Package":"example.com/util","Elapsed":0.003}
+`
+
+const pytestJUnitOutput = `<?xml version="1.0" encoding="utf-8"?>
+<testsuites>
+  <testsuite name="pytest" errors="0" failures="1" skipped="1" tests="3" time="1.5">
+    <testcase classname="tests.test_api" name="test_health" time="0.010" />
+    <testcase classname="tests.test_api" name="test_create" time="0.420">
+      <failure message="AssertionError: assert 500 == 201">tests/test_api.py:27: in test_create
+    assert resp.status_code == 201
+E   AssertionError: assert 500 == 201</failure>
+    </testcase>
+    <testcase classname="tests.test_api" name="test_legacy" time="0.000">
+      <skipped type="pytest.skip" message="legacy endpoint removed" />
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+const jestJSONOutput = `{
+  "numPassedTests": 1,
+  "numFailedTests": 1,
+  "numPendingTests": 1,
+  "startTime": 1700000000000,
+  "testResults": [
+    {
+      "name": "/app/src/sum.test.ts",
+      "startTime": 1700000000000,
+      "endTime": 1700000000800,
+      "assertionResults": [
+        {"fullName": "sum adds numbers", "title": "adds numbers", "status": "passed", "duration": 3, "failureMessages": []},
+        {"fullName": "sum handles negatives", "title": "handles negatives", "status": "failed", "duration": 12,
+         "failureMessages": ["Error: expect(received).toBe(expected)"], "location": {"line": 9, "column": 3}},
+        {"fullName": "sum todo", "title": "todo", "status": "pending", "duration": null, "failureMessages": []}
+      ]
+    }
+  ]
+}`
+
+const cargoTestOutput = `
+running 3 tests
+test tests::it_adds ... ok
+test tests::it_divides ... FAILED
+test tests::it_is_slow ... ignored
+
+failures:
+
+---- tests::it_divides stdout ----
+thread 'tests::it_divides' panicked at src/lib.rs:22:9:
+attempt to divide by zero
+
+failures:
+    tests::it_divides
+
+test result: FAILED. 1 passed; 1 failed; 1 ignored; 0 measured; 0 filtered out; finished in 0.12s
+`
+
+// ==============================================================
+// Parser Tests
+// ==============================================================
+
+func TestParseGoTestJSON(t *testing.T) {
+	report, err := ParseGoTestJSON(strings.NewReader(goTestJSONOutput))
+	if err != nil {
+		t.Fatalf("ParseGoTestJSON() error = %v", err)
+	}
+
+	// Subtests are not counted on top of TestTable
+	assertTotals(t, report, 2, 1, 1)
+
+	failures := report.Failures()
+	if len(failures) != 1 {
+		t.Fatalf("Failures() = %d, want 1", len(failures))
+	}
+	f := failures[0]
+	if f.Name != "TestSub" || f.Suite != "example.com/app" {
+		t.Errorf("failure = %s in %s, want TestSub in example.com/app", f.Name, f.Suite)
+	}
+	if f.Location() != "math_test.go:14" {
+		t.Errorf("Location() = %q, want 'math_test.go:14'", f.Location())
+	}
+	if !strings.Contains(f.Message, "want 1") {
+		t.Errorf("Message = %q, should contain assertion text", f.Message)
+	}
+	if f.Duration != 250*time.Millisecond {
+		t.Errorf("Duration = %v, want 250ms", f.Duration)
+	}
+	// Packages run in parallel, their times do not add up
+	if report.Duration != 0 {
+		t.Errorf("report Duration = %v, want 0", report.Duration)
+	}
+}
+
+func TestParseGoTestJSON_BuildFailure(t *testing.T) {
+	report, err := ParseGoTestJSON(strings.NewReader(goTestBuildFailure))
+	if err != nil {
+		t.Fatalf("ParseGoTestJSON() error = %v", err)
+	}
+
+	assertTotals(t, report, 1, 1, 0)
+	f := report.Failures()[0]
+	if f.Name != "example.com/app" || f.Suite != "example.com/app" {
+		t.Errorf("failure = %s in %s, want the example.com/app package", f.Name, f.Suite)
+	}
+	if f.Message != "app/math_test.go:5:28: undefined: Mul" || f.Location() != "app/math_test.go:5" {
+		t.Errorf("Message = %q, Location() = %q", f.Message, f.Location())
+	}
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+	if out := buf.String(); !strings.Contains(out, "Build Errors") || !strings.Contains(out, "# example.com/app") {
+		t.Errorf("Print() output missing the build output:\n%s", out)
+	}
+}
+
+func TestParseGoTestJSON_PackageFailure(t *testing.T) {
+	// TestMain exiting non-zero fails the package without a failing test
+	input := `{"Action":"run","Package":"example.com/db","Test":"TestQuery"}
+{"Action":"pass","Package":"example.com/db","Test":"TestQuery","Elapsed":0}
+{"Action":"output","Package":"example.com/db","Output":"main_test.go:20: database not reachable\n"}
+{"Action":"output","Package":"example.com/db","Output":"FAIL\texample.com/db\t0.01s\n"}
+{"Action":"fail","Package":"example.com/db","Elapsed":0.01}
+`
+	report, err := ParseGoTestJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseGoTestJSON() error = %v", err)
+	}
+	assertTotals(t, report, 1, 1, 0)
+	if f := report.Failures()[0]; f.Name != "example.com/db" || f.Location() != "main_test.go:20" {
+		t.Errorf("failure = %s at %s, want the package at main_test.go:20", f.Name, f.Location())
+	}
+}
+
+func TestParseGoTestJSON_NoEvents(t *testing.T) {
+	if _, err := ParseGoTestJSON(strings.NewReader("# build failed\n")); err == nil {
+		t.Error("ParseGoTestJSON() should fail without events")
+	}
+}
+
+func TestParseJUnitXML(t *testing.T) {
+	report, err := ParseJUnitXML(strings.NewReader(pytestJUnitOutput))
+	if err != nil {
+		t.Fatalf("ParseJUnitXML() error = %v", err)
+	}
+
+	assertTotals(t, report, 1, 1, 1)
+
+	f := report.Failures()[0]
+	if f.Name != "test_create" || f.Suite != "tests.test_api" {
+		t.Errorf("failure = %s in %s, want test_create in tests.test_api", f.Name, f.Suite)
+	}
+	if f.Location() != "tests/test_api.py:27" {
+		t.Errorf("Location() = %q, want 'tests/test_api.py:27'", f.Location())
+	}
+	if report.Duration != 1500*time.Millisecond {
+		t.Errorf("Duration = %v, want 1.5s", report.Duration)
+	}
+}
+
+func TestParseJUnitXML_SingleSuite(t *testing.T) {
+	input := `<testsuite name="phpunit" time="0.2"><testcase name="test_it" classname="ExampleTest" file="tests/ExampleTest.php" line="12" time="0.1"/></testsuite>`
+
+	report, err := ParseJUnitXML(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseJUnitXML() error = %v", err)
+	}
+	assertTotals(t, report, 1, 0, 0)
+	if report.Cases[0].Location() != "tests/ExampleTest.php:12" {
+		t.Errorf("Location() = %q", report.Cases[0].Location())
+	}
+}
+
+func TestParseJestJSON(t *testing.T) {
+	report, err := ParseJestJSON(strings.NewReader(jestJSONOutput))
+	if err != nil {
+		t.Fatalf("ParseJestJSON() error = %v", err)
+	}
+
+	assertTotals(t, report, 1, 1, 1)
+
+	f := report.Failures()[0]
+	if f.Name != "sum handles negatives" {
+		t.Errorf("failure name = %q", f.Name)
+	}
+	if f.Location() != "/app/src/sum.test.ts:9" {
+		t.Errorf("Location() = %q, want '/app/src/sum.test.ts:9'", f.Location())
+	}
+	if report.Duration != 800*time.Millisecond {
+		t.Errorf("Duration = %v, want 800ms", report.Duration)
+	}
+}
+
+func TestParseCargoTest(t *testing.T) {
+	report, err := ParseCargoTest(strings.NewReader(cargoTestOutput))
+	if err != nil {
+		t.Fatalf("ParseCargoTest() error = %v", err)
+	}
+
+	assertTotals(t, report, 1, 1, 1)
+
+	f := report.Failures()[0]
+	if f.Name != "tests::it_divides" {
+		t.Errorf("failure name = %q", f.Name)
+	}
+	if f.Location() != "src/lib.rs:22" {
+		t.Errorf("Location() = %q, want 'src/lib.rs:22'", f.Location())
+	}
+	if !strings.Contains(f.Message, "divide by zero") {
+		t.Errorf("Message = %q, should contain panic text", f.Message)
+	}
+	if report.Duration != 120*time.Millisecond {
+		t.Errorf("Duration = %v, want 120ms", report.Duration)
+	}
+}
+
+// ==============================================================
+// TestReport Tests
+// ==============================================================
+
+func TestTestReport_Slowest(t *testing.T) {
+	report := &TestReport{}
+	report.Add(TestCase{Name: "a", Status: TestPassed, Duration: time.Second})
+	report.Add(TestCase{Name: "b", Status: TestPassed, Duration: 3 * time.Second})
+	report.Add(TestCase{Name: "c", Status: TestFailed, Duration: 2 * time.Second})
+
+	slow := report.Slowest(2)
+	if len(slow) != 2 || slow[0].Name != "b" || slow[1].Name != "c" {
+		t.Errorf("Slowest(2) = %v, want [b c]", slow)
+	}
+	if report.Total() != 3 {
+		t.Errorf("Total() = %d, want 3", report.Total())
+	}
+}
+
+func TestTestReport_Print(t *testing.T) {
+	report, _ := ParseGoTestJSON(strings.NewReader(goTestJSONOutput))
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+
+	out := buf.String()
+	for _, want := range []string{"Test Summary", "TestSub", "math_test.go:14"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Print() output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// ==============================================================
+// Report Spec Tests
+// ==============================================================
+
+func TestRunner_getReportSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  projects.Project
+		wantTool string
+		wantErr  bool
+	}{
+		{"go", projects.Project{Type: projects.TypeGo}, "go test", false},
+		{"rust", projects.Project{Type: projects.TypeRust}, "cargo test", false},
+		{"python", projects.Project{Type: projects.TypePython}, "pytest", false},
+		{"laravel", projects.Project{Type: projects.TypeLaravel}, "artisan test", false},
+		{"jest", projects.Project{Type: projects.TypeReact, Scripts: map[string]string{"test": "jest"}}, "jest", false},
+		{"vitest", projects.Project{Type: projects.TypeVue, Scripts: map[string]string{"test": "vitest"}}, "vitest", false},
+		{"mocha", projects.Project{Type: projects.TypeNode, Scripts: map[string]string{"test": "mocha"}}, "", true},
+		{"static", projects.Project{Type: projects.TypeStatic}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Runner{Project: &tt.project}
+			spec, err := r.getReportSpec(t.TempDir())
+			if tt.wantErr {
+				if err == nil {
+					t.Error("getReportSpec() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getReportSpec() error = %v", err)
+			}
+			if spec.tool != tt.wantTool {
+				t.Errorf("tool = %q, want %q", spec.tool, tt.wantTool)
+			}
+		})
+	}
+}
+
+func TestRunner_TestWithReport_Go(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/demo\n\ngo 1.21\n")
+	writeFile(t, dir, "demo_test.go", `package demo
+
+import "testing"
+
+func TestOK(t *testing.T) {}
+
+func TestBroken(t *testing.T) { t.Fatal("broken") }
+`)
+
+	r := &Runner{
+		Project: &projects.Project{Name: "demo", Path: dir, Type: projects.TypeGo},
+		Cwd:     dir,
+		Env:     map[string]string{"GOFLAGS": "-mod=mod"},
+		Stdout:  &bytes.Buffer{},
+		Stderr:  &bytes.Buffer{},
+	}
+
+	report, err := r.TestWithReport(nil)
+	if err != nil {
+		t.Fatalf("TestWithReport() error = %v", err)
+	}
+	assertTotals(t, report, 1, 1, 0)
+	if report.Project != "demo" {
+		t.Errorf("Project = %q, want 'demo'", report.Project)
+	}
+}
+
+// ==============================================================
+// Test Helpers
+// ==============================================================
+
+func assertTotals(t *testing.T, r *TestReport, passed, failed, skipped int) {
+	t.Helper()
+	if r.Passed != passed || r.Failed != failed || r.Skipped != skipped {
+		t.Errorf("totals = %d passed, %d failed, %d skipped; want %d, %d, %d",
+			r.Passed, r.Failed, r.Skipped, passed, failed, skipped)
+	}
+}
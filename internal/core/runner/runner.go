@@ -1,11 +1,18 @@
 package runner
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/badie/bdev/internal/core/config"
 	"github.com/badie/bdev/internal/core/projects"
 	"github.com/badie/bdev/pkg/ui"
 )
@@ -18,6 +25,8 @@ type Runner struct {
 	Stdout  io.Writer
 	Stderr  io.Writer
 	Stdin   io.Reader
+
+	port int // dev server port set by UsePort
 }
 
 // New creates a new runner for a project
@@ -43,23 +52,90 @@ func NewFromCwd() (*Runner, error) {
 	if project == nil {
 		return nil, fmt.Errorf("not a recognized project in %s", cwd)
 	}
+	project.OverridePackageManager(config.Get().SettingsFor(project.Name).PackageManager)
+
+	// Analyze tolerates a broken project file, commands should not
+	if _, err := projects.LoadProjectFile(project.Path); err != nil {
+		return nil, err
+	}
+
+	// Commands run in a project rank it higher in `bdev jump`
+	projects.RecordUse(config.Get().UsageFile(), project.Path)
 
 	return New(project), nil
 }
 
 // Start starts the development server
 func (r *Runner) Start() error {
-	bin, args := r.Project.GetStartCommand()
+	overridden := r.Project.Overrides().Start != ""
+	if r.Project.Type == projects.TypeStatic && !overridden {
+		return r.ServeStatic(context.Background(), "")
+	}
+
+	bin, args := r.startCommand()
 	if bin == "" {
 		return fmt.Errorf("no start command available for %s projects", r.Project.Type)
 	}
 
 	ui.PrintHeader(fmt.Sprintf("Starting %s", r.Project.Name))
+	if !overridden {
+		if err := r.prepare(true); err != nil {
+			return err
+		}
+	}
 	fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(bin), args)
 
 	return r.execute(bin, args)
 }
 
+// StartWatch starts the development server and restarts it on source changes
+func (r *Runner) StartWatch() error {
+	if r.Project.Type == projects.TypeStatic && r.Project.Overrides().Start == "" {
+		// Files are served from disk on every request, a reload is all it takes
+		return r.ServeStatic(context.Background(), "")
+	}
+
+	bin, args := r.startCommand()
+	if bin == "" {
+		return fmt.Errorf("no start command available for %s projects", r.Project.Type)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Starting %s (watch mode)", r.Project.Name))
+	fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(bin), args)
+
+	return r.Watch(context.Background(), bin, args)
+}
+
+// StartServices runs the project's Procfile or .bdev.yml services together.
+// When names are given only those services are started.
+func (r *Runner) StartServices(names ...string) error {
+	services, err := LoadServices(r.Project.Path)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return fmt.Errorf("no services defined for %s (add a Procfile or services: in .bdev.yml)", r.Project.Name)
+	}
+
+	sup := NewSupervisor(services, r.Cwd)
+	sup.Stdout = r.Stdout
+	sup.Env = r.Env
+	if err := sup.Select(names...); err != nil {
+		return err
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Starting %s", r.Project.Name))
+	fmt.Fprintf(r.Stdout, "%s %s\n\n", ui.Muted("Services:"), ui.Primary(strings.Join(sup.Names(), ", ")))
+
+	return sup.Run(context.Background())
+}
+
+// HasServices reports whether the project declares multiple services
+func (r *Runner) HasServices() bool {
+	services, err := LoadServices(r.Project.Path)
+	return err == nil && len(services) > 0
+}
+
 // Test runs tests
 func (r *Runner) Test(watch bool) error {
 	bin, args := r.Project.GetTestCommand()
@@ -67,22 +143,160 @@ func (r *Runner) Test(watch bool) error {
 		return fmt.Errorf("no test command available for %s projects", r.Project.Type)
 	}
 
-	// Add watch flag for supported frameworks
-	if watch {
-		switch r.Project.Type.String() {
-		case "Next.js", "React", "Vue", "Node.js":
-			args = append(args, "--", "--watch")
-		case "Go":
-			// Go doesn't have native watch, would need external tool
+	// JS test runners have their own watch mode, everything else uses ours.
+	// A .bdev.yml override is an opaque shell line, so it always uses ours.
+	overridden := r.Project.Overrides().Test != ""
+	nativeWatch := false
+	if watch && !overridden {
+		switch r.Project.Type {
+		case projects.TypeNextJS, projects.TypeReact, projects.TypeVue, projects.TypeAngular, projects.TypeNode:
+			args = append(args, r.Project.GetPackageManager().ScriptArgs("--watch")...)
+			nativeWatch = true
 		}
 	}
 
 	ui.PrintHeader(fmt.Sprintf("Testing %s", r.Project.Name))
+	if !overridden {
+		if err := r.prepare(true); err != nil {
+			return err
+		}
+	}
 	fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(bin), args)
 
+	if watch && !nativeWatch {
+		return r.Watch(context.Background(), bin, args)
+	}
 	return r.execute(bin, args)
 }
 
+// reportSpec describes how to produce a structured report from a test run
+type reportSpec struct {
+	tool  string
+	bin   string
+	args  []string
+	file  string // report file, empty means the report is parsed from stdout
+	parse func(io.Reader) (*TestReport, error)
+	quiet bool // stdout is machine-readable and is not streamed
+}
+
+// getReportSpec returns the reporter invocation for the project, writing report files to dir
+func (r *Runner) getReportSpec(dir string) (*reportSpec, error) {
+	if r.Project.Overrides().Test != "" {
+		return nil, fmt.Errorf("structured test reports are not supported for custom test commands")
+	}
+	pm := r.Project.GetPackageManager()
+
+	switch r.Project.Type {
+	case projects.TypeGo:
+		return &reportSpec{tool: "go test", bin: "go", args: []string{"test", "-json", "./..."}, parse: ParseGoTestJSON, quiet: true}, nil
+
+	case projects.TypeRust:
+		return &reportSpec{tool: "cargo test", bin: "cargo", args: []string{"test"}, parse: ParseCargoTest}, nil
+
+	case projects.TypePython:
+		file := filepath.Join(dir, "junit.xml")
+		bin, args := pm.Exec("pytest", "--junitxml="+file)
+		return &reportSpec{tool: "pytest", bin: bin, args: args, file: file, parse: ParseJUnitXML}, nil
+
+	case projects.TypeLaravel:
+		file := filepath.Join(dir, "junit.xml")
+		return &reportSpec{tool: "artisan test", bin: "php", args: []string{"artisan", "test", "--log-junit", file}, file: file, parse: ParseJUnitXML}, nil
+
+	case projects.TypeNextJS, projects.TypeReact, projects.TypeVue, projects.TypeNuxt,
+		projects.TypeSvelte, projects.TypeAstro, projects.TypeNode:
+		script, ok := r.Project.Scripts["test"]
+		if !ok {
+			break
+		}
+		file := filepath.Join(dir, "report.json")
+		bin, args := pm.RunScript("test")
+		switch {
+		case strings.Contains(script, "vitest"):
+			args = append(args, pm.ScriptArgs("--run", "--reporter=json", "--outputFile="+file)...)
+			return &reportSpec{tool: "vitest", bin: bin, args: args, file: file, parse: ParseJestJSON}, nil
+		case strings.Contains(script, "jest"):
+			args = append(args, pm.ScriptArgs("--json", "--outputFile="+file)...)
+			return &reportSpec{tool: "jest", bin: bin, args: args, file: file, parse: ParseJestJSON}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("structured test reports are not supported for %s projects", r.Project.Type)
+}
+
+// SupportsTestReport reports whether TestWithReport can parse this project's tests
+func (r *Runner) SupportsTestReport() bool {
+	_, err := r.getReportSpec(os.TempDir())
+	return err == nil
+}
+
+// TestWithReport runs the tests with a machine-readable reporter and returns
+// the parsed results. Failing tests are reported, not returned as an error,
+// but a failed run without any failing test is.
+// A non-nil selection limits the run to specific tests or targets.
+func (r *Runner) TestWithReport(sel *TestSelection) (*TestReport, error) {
+	dir, err := os.MkdirTemp("", "bdev-test-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	spec, err := r.getReportSpec(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.applySelection(spec, sel); err != nil {
+		return nil, err
+	}
+
+	// Header goes to r.Stdout so JSON output on os.Stdout stays clean
+	fmt.Fprintf(r.Stdout, "\n%s\n%s\n", ui.Bold(fmt.Sprintf("Testing %s", r.Project.Name)), ui.Muted(ui.SeparatorMedium))
+	fmt.Fprintf(r.Stdout, "%s %s %v\n\n", ui.Muted("$"), ui.Primary(spec.bin), spec.args)
+
+	cmd := r.command(spec.bin, spec.args)
+	var stdout bytes.Buffer
+	if spec.file == "" {
+		if spec.quiet {
+			cmd.Stdout = &stdout
+		} else {
+			cmd.Stdout = io.MultiWriter(r.Stdout, &stdout)
+		}
+	}
+
+	start := time.Now()
+	runErr := cmd.Run()
+	elapsed := time.Since(start)
+
+	var src io.Reader = &stdout
+	if spec.file != "" {
+		f, err := os.Open(spec.file)
+		if err != nil {
+			if runErr != nil {
+				return nil, exitError(runErr)
+			}
+			return nil, fmt.Errorf("%s did not write a report", spec.tool)
+		}
+		defer f.Close()
+		src = f
+	}
+
+	report, err := spec.parse(src)
+	if err != nil || (report.Failed == 0 && runErr != nil) {
+		// A failed run without failing tests usually means a build error
+		// the reporter did not cover, surface the exit code
+		if runErr != nil {
+			return nil, exitError(runErr)
+		}
+		return nil, err
+	}
+
+	report.Project = r.Project.Name
+	report.Tool = spec.tool
+	if report.Duration == 0 {
+		report.Duration = elapsed
+	}
+	return report, nil
+}
+
 // Build builds for production
 func (r *Runner) Build() error {
 	bin, args := r.Project.GetBuildCommand()
@@ -91,11 +305,34 @@ func (r *Runner) Build() error {
 	}
 
 	ui.PrintHeader(fmt.Sprintf("Building %s", r.Project.Name))
+	if r.Project.Overrides().Build == "" {
+		if err := r.prepare(false); err != nil {
+			return err
+		}
+	}
 	fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(bin), args)
 
 	return r.execute(bin, args)
 }
 
+// prepare runs the steps a command depends on, such as CMake's configure step.
+// withBuild also compiles CMake projects, for commands that run build outputs.
+func (r *Runner) prepare(withBuild bool) error {
+	if bin, args := r.Project.GetConfigureCommand(); bin != "" {
+		fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(bin), args)
+		if err := r.execute(bin, args); err != nil {
+			return err
+		}
+	}
+
+	if withBuild && r.Project.Type == projects.TypeCpp && r.Project.UsesCMake() {
+		bin, args := r.Project.GetBuildCommand()
+		fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(bin), args)
+		return r.execute(bin, args)
+	}
+	return nil
+}
+
 // RunScript runs a specific script
 func (r *Runner) RunScript(script string) error {
 	if r.Project.Scripts == nil {
@@ -107,7 +344,56 @@ func (r *Runner) RunScript(script string) error {
 	}
 
 	ui.PrintHeader(fmt.Sprintf("Running %s", script))
-	return r.execute("npm", []string{"run", script})
+	bin, args := r.Project.GetPackageManager().RunScript(script)
+	return r.execute(bin, args)
+}
+
+// RunTask runs a task from the project's .bdev.yml, falling back to a
+// package script of the same name. Extra args are appended to the command line.
+func (r *Runner) RunTask(name string, args []string) error {
+	task, ok := r.Project.Overrides().Tasks[name]
+	if !ok {
+		if _, ok := r.Project.Scripts[name]; ok {
+			pm := r.Project.GetPackageManager()
+			bin, scriptArgs := pm.RunScript(name)
+			if len(args) > 0 {
+				scriptArgs = append(scriptArgs, pm.ScriptArgs(args...)...)
+			}
+			ui.PrintHeader(fmt.Sprintf("Running %s", name))
+			return r.execute(bin, scriptArgs)
+		}
+		return fmt.Errorf("task '%s' not found. Available: %v", name, r.TaskList())
+	}
+
+	line := task.Command
+	for _, arg := range args {
+		line += " " + projects.ShellQuote(arg)
+	}
+	bin, cmdArgs := projects.ShellCommand(line)
+
+	cmd := r.command(bin, cmdArgs)
+	if task.Cwd != "" {
+		cmd.Dir = filepath.Join(r.Cwd, task.Cwd)
+	}
+	for k, v := range task.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Running %s", name))
+	fmt.Printf("%s %s\n\n", ui.Muted("$"), ui.Primary(line))
+	return exitError(cmd.Run())
+}
+
+// TaskList returns the .bdev.yml tasks followed by package scripts not shadowed by a task
+func (r *Runner) TaskList() []string {
+	pf := r.Project.Overrides()
+	names := pf.TaskNames()
+	for _, s := range r.Project.ScriptList() {
+		if _, ok := pf.Tasks[s]; !ok {
+			names = append(names, s)
+		}
+	}
+	return names
 }
 
 // Install installs dependencies
@@ -125,74 +411,60 @@ func (r *Runner) Install() error {
 
 // GetInstallCommand returns the install command
 func (r *Runner) GetInstallCommand() (string, []string, error) {
-	switch r.Project.Type {
-	case projects.TypeNextJS, projects.TypeReact, projects.TypeVue,
-		projects.TypeNuxt, projects.TypeSvelte, projects.TypeAstro, projects.TypeNode:
-		return "npm", []string{"install"}, nil
-	case projects.TypeGo:
-		return "go", []string{"mod", "download"}, nil
-	case projects.TypePython:
-		return "pip", []string{"install", "-r", "requirements.txt"}, nil
-	case projects.TypeRust:
-		return "cargo", []string{"build"}, nil
-	case projects.TypeLaravel:
-		return "composer", []string{"install"}, nil
-	default:
-		return "", nil, fmt.Errorf("no install command for %s", r.Project.Type)
+	if line := r.Project.Overrides().Install; line != "" {
+		bin, args := projects.ShellCommand(line)
+		return bin, args, nil
+	}
+	if c := r.Project.TypeCommand("install", r.hasTool); c != nil {
+		return c.Bin, c.Args, nil
 	}
+	return "", nil, fmt.Errorf("no install command for %s", r.Project.Type)
 }
 
 // Lint runs the linter
 func (r *Runner) Lint(fix bool) error {
-	bin, args, err := r.GetLintCommand(fix)
+	c, err := r.lintCommand(fix)
 	if err != nil {
 		return err
 	}
+	if err := r.ensureTool(c); err != nil {
+		return err
+	}
 
 	ui.PrintHeader(fmt.Sprintf("Linting %s", r.Project.Name))
-	fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(bin), args)
+	fmt.Printf("%s %s %v\n\n", ui.Muted("$"), ui.Primary(c.bin), c.args)
 
-	return r.execute(bin, args)
+	return r.execute(c.bin, c.args)
 }
 
 // GetLintCommand returns the lint command
 func (r *Runner) GetLintCommand(fix bool) (string, []string, error) {
-	var bin string
-	var args []string
+	c, err := r.lintCommand(fix)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.bin, c.args, nil
+}
 
-	switch r.Project.Type {
-	case projects.TypeNextJS, projects.TypeReact, projects.TypeVue,
-		projects.TypeNuxt, projects.TypeSvelte, projects.TypeNode:
-		if _, ok := r.Project.Scripts["lint"]; ok {
-			args = []string{"run", "lint"}
-			if fix {
-				args = append(args, "--", "--fix")
-			}
-			bin = "npm"
-		} else {
-			bin = "npx"
-			args = []string{"eslint", "."}
-			if fix {
-				args = append(args, "--fix")
-			}
-		}
-		return bin, args, nil
-	case projects.TypeGo:
-		return "go", []string{"vet", "./..."}, nil
-	case projects.TypePython:
-		bin = "ruff"
-		args = []string{"check", "."}
-		if fix {
-			args = append(args, "--fix")
-		}
-		return bin, args, nil
-	default:
-		return "", nil, fmt.Errorf("no lint command for %s", r.Project.Type)
+func (r *Runner) lintCommand(fix bool) (*toolCommand, error) {
+	// Custom lint commands have no fix mode, they run as written
+	if line := r.Project.Overrides().Lint; line != "" {
+		bin, args := projects.ShellCommand(line)
+		return &toolCommand{bin: bin, args: args}, nil
+	}
+
+	kind := "lint"
+	if fix {
+		kind = "lint_fix"
+	}
+	if c := r.Project.TypeCommand(kind, r.hasTool); c != nil {
+		return newToolCommand(c), nil
 	}
+	return nil, fmt.Errorf("no lint command for %s", r.Project.Type)
 }
 
-// execute runs a command with streaming output
-func (r *Runner) execute(bin string, args []string) error {
+// command builds a command with the runner's directory, streams and environment
+func (r *Runner) command(bin string, args []string) *exec.Cmd {
 	cmd := exec.Command(bin, args...)
 	cmd.Dir = r.Cwd
 	cmd.Stdout = r.Stdout
@@ -205,15 +477,114 @@ func (r *Runner) execute(bin string, args []string) error {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	err := cmd.Run()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return fmt.Errorf("command exited with code %d", exitErr.ExitCode())
+	return cmd
+}
+
+// execute runs a command with streaming output
+func (r *Runner) execute(bin string, args []string) error {
+	err := r.command(bin, args).Run()
+	return exitError(err)
+}
+
+// exitError converts exec exit errors into a readable message
+func exitError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return fmt.Errorf("command exited with code %d", exitErr.ExitCode())
+	}
+	return err
+}
+
+// Watch runs a command and restarts it whenever watched source files change.
+// It returns when ctx is cancelled or the user presses Ctrl+C.
+func (r *Runner) Watch(ctx context.Context, bin string, args []string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	watcher := NewWatcher(r.Project)
+	watcher.Root = r.Cwd
+	changes := watcher.Watch(ctx)
+
+	for {
+		cmd := r.command(bin, args)
+		// The child gets its own process group and no terminal input,
+		// so restarts never leave orphans behind
+		cmd.Stdin = nil
+		setProcessGroup(cmd)
+
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+
+		exited := false
+		for !exited {
+			select {
+			case <-ctx.Done():
+				stopProcess(cmd, done)
+				fmt.Fprintln(r.Stdout, ui.Muted("\nWatch stopped"))
+				return nil
+
+			case err := <-done:
+				exited = true
+				if err != nil {
+					fmt.Fprintln(r.Stdout, ui.Error(exitError(err).Error()))
+				} else {
+					fmt.Fprintln(r.Stdout, ui.Success("Process exited cleanly"))
+				}
+				fmt.Fprintln(r.Stdout, ui.Muted("Waiting for changes..."))
+
+				// Block until something changes, then restart
+				select {
+				case <-ctx.Done():
+					fmt.Fprintln(r.Stdout, ui.Muted("\nWatch stopped"))
+					return nil
+				case changed, ok := <-changes:
+					if !ok {
+						return nil
+					}
+					printChanges(r.Stdout, changed)
+				}
+
+			case changed, ok := <-changes:
+				if !ok {
+					stopProcess(cmd, done)
+					return nil
+				}
+				printChanges(r.Stdout, changed)
+				stopProcess(cmd, done)
+				exited = true
+			}
 		}
-		return err
 	}
+}
 
-	return nil
+// stopProcess terminates a running command, escalating to kill after a grace period
+func stopProcess(cmd *exec.Cmd, done <-chan error) {
+	_ = terminateProcess(cmd)
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		_ = killProcess(cmd)
+		<-done
+	}
+}
+
+// printChanges reports the files that triggered a restart
+func printChanges(w io.Writer, changed []string) {
+	shown := changed
+	if len(shown) > 3 {
+		shown = shown[:3]
+	}
+	msg := strings.Join(shown, ", ")
+	if extra := len(changed) - len(shown); extra > 0 {
+		msg += fmt.Sprintf(" (+%d more)", extra)
+	}
+	fmt.Fprintf(w, "\n%s %s\n\n", ui.Info("Change detected:"), ui.Muted(msg))
 }
 
 // ExecuteRaw executes a raw command in the project directory
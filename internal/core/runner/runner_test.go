@@ -2,6 +2,8 @@ package runner
 
 import (
 	"bytes"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/badie/bdev/internal/core/projects"
@@ -46,11 +48,12 @@ func TestNew(t *testing.T) {
 
 func TestRunner_GetInstallCommand(t *testing.T) {
 	tests := []struct {
-		name        string
-		projectType projects.ProjectType
-		wantBin     string
-		wantArgs    []string
-		wantErr     bool
+		name           string
+		projectType    projects.ProjectType
+		packageManager projects.PackageManager
+		wantBin        string
+		wantArgs       []string
+		wantErr        bool
 	}{
 		{
 			name:        "go_project",
@@ -100,6 +103,56 @@ func TestRunner_GetInstallCommand(t *testing.T) {
 			wantBin:     "composer",
 			wantArgs:    []string{"install"},
 		},
+		{
+			name:           "pnpm_project",
+			projectType:    projects.TypeNextJS,
+			packageManager: projects.PMPnpm,
+			wantBin:        "pnpm",
+			wantArgs:       []string{"install"},
+		},
+		{
+			name:           "uv_project",
+			projectType:    projects.TypePython,
+			packageManager: projects.PMUv,
+			wantBin:        "uv",
+			wantArgs:       []string{"sync"},
+		},
+		{
+			name:           "poetry_project",
+			projectType:    projects.TypePython,
+			packageManager: projects.PMPoetry,
+			wantBin:        "poetry",
+			wantArgs:       []string{"install"},
+		},
+		{
+			name:        "angular_project",
+			projectType: projects.TypeAngular,
+			wantBin:     "npm",
+			wantArgs:    []string{"install"},
+		},
+		{
+			name:        "django_project",
+			projectType: projects.TypeDjango,
+			wantBin:     "pip",
+			wantArgs:    []string{"install", "-r", "requirements.txt"},
+		},
+		{
+			name:        "php_project",
+			projectType: projects.TypePHP,
+			wantBin:     "composer",
+			wantArgs:    []string{"install"},
+		},
+		{
+			name:        "java_gradle_project",
+			projectType: projects.TypeJava,
+			wantBin:     "gradle",
+			wantArgs:    []string{"dependencies"},
+		},
+		{
+			name:        "cpp_makefile_project",
+			projectType: projects.TypeCpp,
+			wantErr:     true,
+		},
 		{
 			name:        "unknown_project",
 			projectType: projects.TypeUnknown,
@@ -115,7 +168,7 @@ func TestRunner_GetInstallCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Runner{
-				Project: &projects.Project{Type: tt.projectType},
+				Project: &projects.Project{Type: tt.projectType, PackageManager: tt.packageManager},
 			}
 
 			bin, args, err := r.GetInstallCommand()
@@ -146,13 +199,14 @@ func TestRunner_GetInstallCommand(t *testing.T) {
 
 func TestRunner_GetLintCommand(t *testing.T) {
 	tests := []struct {
-		name        string
-		projectType projects.ProjectType
-		scripts     map[string]string
-		fix         bool
-		wantBin     string
-		wantArgs    []string
-		wantErr     bool
+		name           string
+		projectType    projects.ProjectType
+		packageManager projects.PackageManager
+		scripts        map[string]string
+		fix            bool
+		wantBin        string
+		wantArgs       []string
+		wantErr        bool
 	}{
 		{
 			name:        "go_project",
@@ -214,6 +268,69 @@ func TestRunner_GetLintCommand(t *testing.T) {
 			wantBin:     "npx",
 			wantArgs:    []string{"eslint", ".", "--fix"},
 		},
+		{
+			name:           "pnpm_with_lint_script_fix",
+			projectType:    projects.TypeNode,
+			packageManager: projects.PMPnpm,
+			scripts:        map[string]string{"lint": "eslint ."},
+			fix:            true,
+			wantBin:        "pnpm",
+			wantArgs:       []string{"run", "lint", "--fix"},
+		},
+		{
+			name:           "yarn_without_lint_script",
+			projectType:    projects.TypeNode,
+			packageManager: projects.PMYarn,
+			scripts:        map[string]string{},
+			wantBin:        "yarn",
+			wantArgs:       []string{"eslint", "."},
+		},
+		{
+			name:           "poetry_python_fix",
+			projectType:    projects.TypePython,
+			packageManager: projects.PMPoetry,
+			fix:            true,
+			wantBin:        "poetry",
+			wantArgs:       []string{"run", "ruff", "check", ".", "--fix"},
+		},
+		{
+			name:        "rust_clippy",
+			projectType: projects.TypeRust,
+			wantBin:     "cargo",
+			wantArgs:    []string{"clippy"},
+		},
+		{
+			name:        "rust_clippy_fix",
+			projectType: projects.TypeRust,
+			fix:         true,
+			wantBin:     "cargo",
+			wantArgs:    []string{"clippy", "--fix", "--allow-dirty", "--allow-staged"},
+		},
+		{
+			name:        "laravel_pint",
+			projectType: projects.TypeLaravel,
+			wantBin:     "pint",
+			wantArgs:    []string{"--test"},
+		},
+		{
+			name:        "laravel_pint_fix",
+			projectType: projects.TypeLaravel,
+			fix:         true,
+			wantBin:     "pint",
+			wantArgs:    nil,
+		},
+		{
+			name:        "php_phpstan",
+			projectType: projects.TypePHP,
+			wantBin:     "phpstan",
+			wantArgs:    []string{"analyse"},
+		},
+		{
+			name:        "java_gradle",
+			projectType: projects.TypeJava,
+			wantBin:     "gradle",
+			wantArgs:    []string{"check", "-x", "test"},
+		},
 		{
 			name:        "django_no_lint",
 			projectType: projects.TypeDjango,
@@ -230,8 +347,9 @@ func TestRunner_GetLintCommand(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Runner{
 				Project: &projects.Project{
-					Type:    tt.projectType,
-					Scripts: tt.scripts,
+					Type:           tt.projectType,
+					PackageManager: tt.packageManager,
+					Scripts:        tt.scripts,
 				},
 			}
 
@@ -398,3 +516,73 @@ func TestRunner_WithEnv(t *testing.T) {
 		t.Error("Environment variable should be set")
 	}
 }
+
+// ==============================================================
+// Override and Task Tests
+// ==============================================================
+
+func TestRunner_Overrides(t *testing.T) {
+	r := &Runner{Project: &projects.Project{
+		Type: projects.TypeGo,
+		File: &projects.ProjectFile{Lint: "golangci-lint run", Install: "make deps", Test: "make test"},
+	}}
+
+	if bin, args, _ := r.GetLintCommand(true); bin == "go" || !strings.Contains(strings.Join(args, " "), "golangci-lint run") {
+		t.Errorf("GetLintCommand() = %s %v, want the override", bin, args)
+	}
+	if bin, args, _ := r.GetInstallCommand(); bin == "go" || !strings.Contains(strings.Join(args, " "), "make deps") {
+		t.Errorf("GetInstallCommand() = %s %v, want the override", bin, args)
+	}
+	if r.SupportsTestReport() {
+		t.Error("SupportsTestReport() should be false with a custom test command")
+	}
+}
+
+func TestRunner_RunTask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, "sub/.keep", "")
+
+	stdout := &bytes.Buffer{}
+	r := &Runner{
+		Project: &projects.Project{
+			Name: "test",
+			Path: dir,
+			File: &projects.ProjectFile{Tasks: map[string]projects.TaskSpec{
+				"greet": {Command: `echo "$GREETING from $(basename "$PWD")"`, Cwd: "sub", Env: map[string]string{"GREETING": "hello"}},
+			}},
+		},
+		Cwd:    dir,
+		Env:    make(map[string]string),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}
+
+	if err := r.RunTask("greet", []string{"it's me"}); err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "hello from sub it's me" {
+		t.Errorf("output = %q, want 'hello from sub it's me'", got)
+	}
+}
+
+func TestRunner_RunTask_NotFound(t *testing.T) {
+	r := &Runner{Project: &projects.Project{
+		Name:    "test",
+		Scripts: map[string]string{"dev": "vite"},
+		File:    &projects.ProjectFile{Tasks: map[string]projects.TaskSpec{"migrate": {Command: "make migrate"}}},
+	}}
+
+	err := r.RunTask("deploy", nil)
+	if err == nil {
+		t.Fatal("RunTask() should fail for an unknown task")
+	}
+	for _, want := range []string{"migrate", "dev"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should list %q", err, want)
+		}
+	}
+}
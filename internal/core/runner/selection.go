@@ -0,0 +1,313 @@
+package runner
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/badie/bdev/internal/core/git"
+	"github.com/badie/bdev/internal/core/ignore"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// TestSelection narrows a structured test run. A nil selection runs everything.
+type TestSelection struct {
+	Tests   []TestCase // tests to re-run by name
+	Targets []string   // test packages or files, relative to the project
+}
+
+// ChangedFiles returns the files with staged or unstaged changes according to
+// git diff, relative to the project directory
+func (r *Runner) ChangedFiles() ([]string, error) {
+	repo, err := git.Open(r.Cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, staged := range []bool{false, true} {
+		diff, err := repo.Diff(staged)
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, git.ParseDiffFiles(diff)...)
+	}
+
+	root := repo.Root()
+	seen := make(map[string]bool)
+	var files []string
+	for _, f := range changed {
+		rel, err := filepath.Rel(r.Cwd, filepath.Join(root, filepath.FromSlash(f)))
+		if err != nil || strings.HasPrefix(rel, "..") || seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		files = append(files, filepath.ToSlash(rel))
+	}
+	return files, nil
+}
+
+// TestTargets maps changed files to the test packages or files that cover them.
+// all is true when a change (e.g. a manifest) affects the whole suite.
+func TestTargets(project *projects.Project, changed []string) (targets []string, all bool) {
+	seen := make(map[string]bool)
+	add := func(t string) {
+		if !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+	}
+
+	switch project.Type {
+	case projects.TypeGo:
+		for _, f := range changed {
+			switch {
+			case f == "go.mod" || f == "go.sum":
+				return nil, true
+			case strings.HasSuffix(f, ".go"):
+				if dir := path.Dir(f); dir == "." {
+					add(".")
+				} else {
+					add("./" + dir)
+				}
+			}
+		}
+
+	case projects.TypeRust:
+		for _, f := range changed {
+			switch {
+			case f == "Cargo.toml" || f == "Cargo.lock":
+				return nil, true
+			case path.Dir(f) == "tests" && strings.HasSuffix(f, ".rs"):
+				add(strings.TrimSuffix(path.Base(f), ".rs"))
+			case strings.HasSuffix(f, ".rs"):
+				// Unit tests live next to the code, cargo cannot select by file
+				return nil, true
+			}
+		}
+
+	default:
+		conv := testConventionFor(project.Type)
+		if conv == nil {
+			return nil, true
+		}
+
+		index := indexTestFiles(project.Path, conv)
+		for _, f := range changed {
+			base := path.Base(f)
+			if conv.manifests[base] {
+				return nil, true
+			}
+			if _, ok := conv.subject(base); ok {
+				add(f)
+				continue
+			}
+			for _, t := range index[stem(base)] {
+				add(t)
+			}
+		}
+	}
+
+	sort.Strings(targets)
+	return targets, false
+}
+
+// testConvention describes how test files are named for an ecosystem
+type testConvention struct {
+	manifests map[string]bool
+	subject   func(name string) (string, bool) // stem under test, if name is a test file
+}
+
+var jsTestSuffix = regexp.MustCompile(`^(.+)\.(test|spec)\.[cm]?[jt]sx?$`)
+
+func testConventionFor(t projects.ProjectType) *testConvention {
+	switch t {
+	case projects.TypePython, projects.TypeDjango:
+		return &testConvention{
+			manifests: map[string]bool{"pyproject.toml": true, "requirements.txt": true, "conftest.py": true, "pytest.ini": true},
+			subject: func(name string) (string, bool) {
+				if !strings.HasSuffix(name, ".py") {
+					return "", false
+				}
+				s := strings.TrimSuffix(name, ".py")
+				if strings.HasPrefix(s, "test_") {
+					return strings.TrimPrefix(s, "test_"), true
+				}
+				if strings.HasSuffix(s, "_test") {
+					return strings.TrimSuffix(s, "_test"), true
+				}
+				return "", false
+			},
+		}
+
+	case projects.TypeLaravel, projects.TypePHP:
+		return &testConvention{
+			manifests: map[string]bool{"composer.json": true, "composer.lock": true, "phpunit.xml": true},
+			subject: func(name string) (string, bool) {
+				if !strings.HasSuffix(name, "Test.php") {
+					return "", false
+				}
+				return strings.TrimSuffix(name, "Test.php"), true
+			},
+		}
+
+	case projects.TypeNextJS, projects.TypeReact, projects.TypeVue, projects.TypeNuxt,
+		projects.TypeSvelte, projects.TypeAstro, projects.TypeNode:
+		return &testConvention{
+			manifests: map[string]bool{"package.json": true, "jest.config.js": true, "jest.config.ts": true, "vitest.config.ts": true, "vitest.config.js": true},
+			subject: func(name string) (string, bool) {
+				m := jsTestSuffix.FindStringSubmatch(name)
+				if m == nil {
+					return "", false
+				}
+				return m[1], true
+			},
+		}
+	}
+	return nil
+}
+
+// indexTestFiles maps subject stems to the test files covering them
+func indexTestFiles(root string, conv *testConvention) map[string][]string {
+	index := make(map[string][]string)
+	matcher := ignore.Load(root)
+
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if matcher.Match(rel, d.IsDir()) || (d.IsDir() && rel == "vendor") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		if s, ok := conv.subject(d.Name()); ok {
+			index[s] = append(index[s], rel)
+		}
+		return nil
+	})
+
+	return index
+}
+
+// stem returns a file name without its extension
+func stem(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// applySelection narrows a reporter invocation to the selected tests or targets
+func (r *Runner) applySelection(spec *reportSpec, sel *TestSelection) error {
+	if sel == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(sel.Tests))
+	for _, c := range sel.Tests {
+		names = append(names, c.Name)
+	}
+
+	switch spec.tool {
+	case "go test":
+		if len(sel.Tests) > 0 {
+			pkgs := make(map[string]bool)
+			top := make(map[string]bool)
+			var runNames, pkgList []string
+			for _, c := range sel.Tests {
+				name, _, _ := strings.Cut(c.Name, "/")
+				// A package that failed as a whole, such as a build failure,
+				// only needs to be listed
+				if c.Name != c.Suite && !top[name] {
+					top[name] = true
+					runNames = append(runNames, regexp.QuoteMeta(name))
+				}
+				if c.Suite != "" && !pkgs[c.Suite] {
+					pkgs[c.Suite] = true
+					pkgList = append(pkgList, c.Suite)
+				}
+			}
+			if len(pkgList) == 0 {
+				pkgList = []string{"./..."}
+			}
+			if len(runNames) == 0 {
+				spec.args = append([]string{"test", "-json"}, pkgList...)
+				return nil
+			}
+			spec.args = append([]string{"test", "-json", "-run", "^(" + strings.Join(runNames, "|") + ")$"}, pkgList...)
+			return nil
+		}
+		spec.args = append([]string{"test", "-json"}, sel.Targets...)
+
+	case "cargo test":
+		if len(sel.Tests) > 0 {
+			spec.args = append([]string{"test", "--", "--exact"}, names...)
+			return nil
+		}
+		args := []string{"test"}
+		for _, t := range sel.Targets {
+			args = append(args, "--test", t)
+		}
+		spec.args = args
+
+	case "pytest":
+		if len(sel.Tests) > 0 {
+			// -k takes an expression, parametrized ids like test_x[1] are not valid in it
+			exprs := make([]string, 0, len(names))
+			for _, n := range names {
+				n, _, _ = strings.Cut(n, "[")
+				exprs = append(exprs, n)
+			}
+			spec.args = append(spec.args, "-k", strings.Join(exprs, " or "))
+			return nil
+		}
+		spec.args = append(spec.args, sel.Targets...)
+
+	case "artisan test":
+		if len(sel.Tests) > 0 {
+			// PHPUnit matches the filter against "Class::method"
+			quoted := make([]string, len(names))
+			for i, n := range names {
+				quoted[i] = regexp.QuoteMeta(n)
+			}
+			spec.args = append(spec.args, "--filter", strings.Join(quoted, "|"))
+			return nil
+		}
+		spec.args = append(spec.args, sel.Targets...)
+
+	case "jest", "vitest":
+		// The reporter flags already went through ScriptArgs, so npm's "--" is in place
+		if len(sel.Tests) > 0 {
+			spec.args = append(spec.args, "-t", namePattern(names))
+			return nil
+		}
+		spec.args = append(spec.args, sel.Targets...)
+
+	default:
+		return fmt.Errorf("test selection is not supported for %s", spec.tool)
+	}
+
+	return nil
+}
+
+// namePattern builds a regex matching any of the test names exactly
+func namePattern(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = regexp.QuoteMeta(n)
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
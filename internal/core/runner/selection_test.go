@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// TestTargets Tests
+// ==============================================================
+
+func TestTestTargets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "src/sum.ts", "")
+	writeFile(t, dir, "src/sum.test.ts", "")
+	writeFile(t, dir, "src/__tests__/sum.spec.ts", "")
+	writeFile(t, dir, "node_modules/lib/sum.test.ts", "")
+	writeFile(t, dir, "app/models.py", "")
+	writeFile(t, dir, "tests/test_models.py", "")
+	writeFile(t, dir, "app/Models/User.php", "")
+	writeFile(t, dir, "tests/Unit/UserTest.php", "")
+
+	tests := []struct {
+		name        string
+		projectType projects.ProjectType
+		changed     []string
+		want        []string
+		wantAll     bool
+	}{
+		{"go_packages", projects.TypeGo, []string{"main.go", "internal/api/handler.go", "internal/api/handler_test.go", "README.md"}, []string{".", "./internal/api"}, false},
+		{"go_mod", projects.TypeGo, []string{"internal/api/handler.go", "go.mod"}, nil, true},
+		{"rust_integration", projects.TypeRust, []string{"tests/api.rs"}, []string{"api"}, false},
+		{"rust_source", projects.TypeRust, []string{"src/lib.rs"}, nil, true},
+		{"js_source", projects.TypeReact, []string{"src/sum.ts"}, []string{"src/__tests__/sum.spec.ts", "src/sum.test.ts"}, false},
+		{"js_test_file", projects.TypeReact, []string{"src/other.test.tsx"}, []string{"src/other.test.tsx"}, false},
+		{"js_manifest", projects.TypeReact, []string{"package.json"}, nil, true},
+		{"python_source", projects.TypePython, []string{"app/models.py"}, []string{"tests/test_models.py"}, false},
+		{"laravel_source", projects.TypeLaravel, []string{"app/Models/User.php"}, []string{"tests/Unit/UserTest.php"}, false},
+		{"no_tests", projects.TypePython, []string{"app/views.py"}, nil, false},
+		{"unsupported", projects.TypeStatic, []string{"index.html"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &projects.Project{Path: dir, Type: tt.projectType}
+			got, all := TestTargets(project, tt.changed)
+
+			if all != tt.wantAll {
+				t.Errorf("TestTargets() all = %v, want %v", all, tt.wantAll)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TestTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// applySelection Tests
+// ==============================================================
+
+func TestRunner_applySelection(t *testing.T) {
+	failed := []TestCase{
+		{Name: "TestA/sub", Suite: "example.com/app"},
+		{Name: "TestB", Suite: "example.com/app/pkg"},
+	}
+
+	tests := []struct {
+		name     string
+		project  projects.Project
+		sel      *TestSelection
+		wantArgs string
+	}{
+		{
+			name:     "go_tests",
+			project:  projects.Project{Type: projects.TypeGo},
+			sel:      &TestSelection{Tests: failed},
+			wantArgs: "test -json -run ^(TestA|TestB)$ example.com/app example.com/app/pkg",
+		},
+		{
+			name:     "go_package_failure",
+			project:  projects.Project{Type: projects.TypeGo},
+			sel:      &TestSelection{Tests: []TestCase{{Name: "example.com/app", Suite: "example.com/app"}}},
+			wantArgs: "test -json example.com/app",
+		},
+		{
+			name:     "go_targets",
+			project:  projects.Project{Type: projects.TypeGo},
+			sel:      &TestSelection{Targets: []string{"./internal/api"}},
+			wantArgs: "test -json ./internal/api",
+		},
+		{
+			name:     "cargo_tests",
+			project:  projects.Project{Type: projects.TypeRust},
+			sel:      &TestSelection{Tests: []TestCase{{Name: "tests::it_divides"}}},
+			wantArgs: "test -- --exact tests::it_divides",
+		},
+		{
+			name:     "cargo_targets",
+			project:  projects.Project{Type: projects.TypeRust},
+			sel:      &TestSelection{Targets: []string{"api"}},
+			wantArgs: "test --test api",
+		},
+		{
+			name:     "pytest_tests",
+			project:  projects.Project{Type: projects.TypePython},
+			sel:      &TestSelection{Tests: []TestCase{{Name: "test_a[1]"}, {Name: "test_b"}}},
+			wantArgs: "-k test_a or test_b",
+		},
+		{
+			name:     "jest_targets_npm",
+			project:  projects.Project{Type: projects.TypeReact, Scripts: map[string]string{"test": "jest"}},
+			sel:      &TestSelection{Targets: []string{"src/sum.test.ts"}},
+			wantArgs: "report.json src/sum.test.ts",
+		},
+		{
+			name:     "vitest_tests",
+			project:  projects.Project{Type: projects.TypeVue, Scripts: map[string]string{"test": "vitest"}, PackageManager: projects.PMPnpm},
+			sel:      &TestSelection{Tests: []TestCase{{Name: "sum adds"}}},
+			wantArgs: "-t ^(sum adds)$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Runner{Project: &tt.project}
+			spec, err := r.getReportSpec(t.TempDir())
+			if err != nil {
+				t.Fatalf("getReportSpec() error = %v", err)
+			}
+			if err := r.applySelection(spec, tt.sel); err != nil {
+				t.Fatalf("applySelection() error = %v", err)
+			}
+
+			got := strings.Join(spec.args, " ")
+			if !strings.HasSuffix(got, tt.wantArgs) {
+				t.Errorf("args = %q, want suffix %q", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// ChangedFiles Tests
+// ==============================================================
+
+func TestRunner_ChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	writeFile(t, repo, "api/main.go", "package main\n")
+	writeFile(t, repo, "api/util.go", "package main\n")
+	writeFile(t, repo, "web/index.ts", "")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	writeFile(t, repo, "api/main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, repo, "api/util.go", "package main\n\nvar x = 1\n")
+	writeFile(t, repo, "web/index.ts", "export {}\n")
+	git("add", "api/util.go")
+
+	r := &Runner{Cwd: repo + "/api"}
+	got, err := r.ChangedFiles()
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+
+	want := []string{"main.go", "util.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedFiles() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,361 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// RestartPolicy controls what happens when a service exits
+type RestartPolicy string
+
+const (
+	RestartNo        RestartPolicy = "no"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartAlways    RestartPolicy = "always"
+)
+
+// Service is a named long-running process managed by the supervisor
+type Service struct {
+	Name    string
+	Command string
+	Cwd     string
+	Env     map[string]string
+	Restart RestartPolicy
+}
+
+// ParseProcfile parses Procfile lines of the form "name: command"
+func ParseProcfile(r io.Reader) ([]Service, error) {
+	var services []Service
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, command, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" || strings.TrimSpace(command) == "" {
+			return nil, fmt.Errorf("Procfile line %d: expected 'name: command'", lineNo)
+		}
+
+		services = append(services, Service{
+			Name:    strings.TrimSpace(name),
+			Command: strings.TrimSpace(command),
+			Restart: RestartOnFailure,
+		})
+	}
+
+	return services, scanner.Err()
+}
+
+// LoadServices reads services from a Procfile or the services section of .bdev.yml
+func LoadServices(projectPath string) ([]Service, error) {
+	if f, err := os.Open(filepath.Join(projectPath, "Procfile")); err == nil {
+		defer f.Close()
+		return ParseProcfile(f)
+	}
+
+	pf, err := projects.LoadProjectFile(projectPath)
+	if err != nil || pf == nil {
+		return nil, err
+	}
+
+	services := make([]Service, 0, len(pf.Services))
+	for name, spec := range pf.Services {
+		if spec.Command == "" {
+			return nil, fmt.Errorf("service '%s' has no command", name)
+		}
+
+		policy := RestartPolicy(spec.Restart)
+		switch policy {
+		case "":
+			policy = RestartOnFailure
+		case RestartNo, RestartOnFailure, RestartAlways:
+		default:
+			return nil, fmt.Errorf("service '%s': unknown restart policy '%s'", name, spec.Restart)
+		}
+
+		services = append(services, Service{
+			Name:    name,
+			Command: spec.Command,
+			Cwd:     spec.Cwd,
+			Env:     spec.Env,
+			Restart: policy,
+		})
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+// Supervisor runs several services together with prefixed output
+type Supervisor struct {
+	Services    []Service
+	Cwd         string
+	Env         map[string]string
+	Stdout      io.Writer
+	MaxRestarts int
+	Backoff     time.Duration
+	StopTimeout time.Duration
+
+	// StableAfter is how long a service must stay up for its restart count
+	// to start over, so rare crashes over a long session are not fatal
+	StableAfter time.Duration
+
+	mu sync.Mutex // serializes writes to Stdout
+}
+
+// prefixColors cycles through the palette used for service name prefixes
+var prefixColors = []*color.Color{
+	color.New(color.FgCyan),
+	color.New(color.FgMagenta),
+	color.New(color.FgGreen),
+	color.New(color.FgYellow),
+	color.New(color.FgBlue),
+	color.New(color.FgHiRed),
+}
+
+// NewSupervisor creates a supervisor for the given services
+func NewSupervisor(services []Service, cwd string) *Supervisor {
+	return &Supervisor{
+		Services:    services,
+		Cwd:         cwd,
+		Env:         make(map[string]string),
+		Stdout:      os.Stdout,
+		MaxRestarts: 5,
+		Backoff:     time.Second,
+		StopTimeout: 5 * time.Second,
+		StableAfter: time.Minute,
+	}
+}
+
+// Select narrows the supervisor to the named services
+func (s *Supervisor) Select(names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	selected := make([]Service, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, svc := range s.Services {
+			if svc.Name == name {
+				selected = append(selected, svc)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("service '%s' not found. Available: %s", name, strings.Join(s.Names(), ", "))
+		}
+	}
+
+	s.Services = selected
+	return nil
+}
+
+// Names returns the names of all services
+func (s *Supervisor) Names() []string {
+	names := make([]string, len(s.Services))
+	for i, svc := range s.Services {
+		names[i] = svc.Name
+	}
+	return names
+}
+
+// Run starts every service and blocks until they have all stopped.
+// Ctrl+C or cancelling ctx shuts all services down.
+func (s *Supervisor) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	width := 0
+	for _, svc := range s.Services {
+		if len(svc.Name) > width {
+			width = len(svc.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(s.Services))
+
+	for i, svc := range s.Services {
+		wg.Add(1)
+		prefix := prefixColors[i%len(prefixColors)].Sprintf("%-*s |", width, svc.Name)
+
+		go func(svc Service, prefix string) {
+			defer wg.Done()
+			if err := s.supervise(ctx, svc, prefix); err != nil {
+				errs <- fmt.Errorf("%s: %w", svc.Name, err)
+			}
+		}(svc, prefix)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var failed []string
+	for err := range errs {
+		failed = append(failed, err.Error())
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("services failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// supervise runs a single service, restarting it according to its policy
+func (s *Supervisor) supervise(ctx context.Context, svc Service, prefix string) error {
+	restarts := 0
+
+	for {
+		out := &prefixWriter{prefix: prefix, w: s.Stdout, mu: &s.mu}
+		started := time.Now()
+		err := s.runOnce(ctx, svc, out)
+		out.Flush()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		s.println(prefix, exitMessage(err))
+
+		restart := svc.Restart == RestartAlways || (svc.Restart == RestartOnFailure && err != nil)
+		if !restart {
+			return exitError(err)
+		}
+
+		if s.StableAfter > 0 && time.Since(started) >= s.StableAfter {
+			restarts = 0
+		}
+		restarts++
+		if restarts > s.MaxRestarts {
+			s.println(prefix, ui.Error(fmt.Sprintf("giving up after %d restarts", s.MaxRestarts)))
+			return fmt.Errorf("crashed %d times", restarts)
+		}
+
+		delay := s.Backoff * time.Duration(restarts)
+		s.println(prefix, ui.Muted(fmt.Sprintf("restarting in %s (%d/%d)", delay, restarts, s.MaxRestarts)))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runOnce starts the service process and waits for it to exit or for ctx to end
+func (s *Supervisor) runOnce(ctx context.Context, svc Service, out io.Writer) error {
+	bin, args := projects.ShellCommand(svc.Command)
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = s.Cwd
+	if svc.Cwd != "" {
+		cmd.Dir = filepath.Join(s.Cwd, svc.Cwd)
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	cmd.Env = os.Environ()
+	for k, v := range s.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	for k, v := range svc.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		_ = terminateProcess(cmd)
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(s.StopTimeout):
+			_ = killProcess(cmd)
+			return <-done
+		}
+	}
+}
+
+// println writes a prefixed status line
+func (s *Supervisor) println(prefix, msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.Stdout, "%s %s\n", prefix, msg)
+}
+
+// exitMessage describes how a service process ended
+func exitMessage(err error) string {
+	if err == nil {
+		return ui.Muted("exited")
+	}
+	return ui.Error(exitError(err).Error())
+}
+
+// prefixWriter prefixes every complete line written to it
+type prefixWriter struct {
+	prefix string
+	w      io.Writer
+	mu     *sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf.Write(data)
+
+	for {
+		line, err := p.buf.ReadBytes('\n')
+		if err != nil {
+			// Keep the partial line for the next write
+			p.buf.Reset()
+			p.buf.Write(line)
+			break
+		}
+		p.writeLine(line)
+	}
+
+	return len(data), nil
+}
+
+// Flush writes any trailing partial line
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() > 0 {
+		p.writeLine(append(p.buf.Bytes(), '\n'))
+		p.buf.Reset()
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "%s %s", p.prefix, line)
+}
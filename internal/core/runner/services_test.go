@@ -0,0 +1,243 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ==============================================================
+// ParseProcfile Tests
+// ==============================================================
+
+func TestParseProcfile(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		input := `# comment
+web: npm run dev
+api:  go run ./cmd/api --port 8080
+
+worker: python worker.py
+`
+		services, err := ParseProcfile(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ParseProcfile() error = %v", err)
+		}
+
+		want := []struct{ name, command string }{
+			{"web", "npm run dev"},
+			{"api", "go run ./cmd/api --port 8080"},
+			{"worker", "python worker.py"},
+		}
+		if len(services) != len(want) {
+			t.Fatalf("ParseProcfile() returned %d services, want %d", len(services), len(want))
+		}
+		for i, w := range want {
+			if services[i].Name != w.name || services[i].Command != w.command {
+				t.Errorf("services[%d] = %s: %s, want %s: %s", i, services[i].Name, services[i].Command, w.name, w.command)
+			}
+			if services[i].Restart != RestartOnFailure {
+				t.Errorf("services[%d].Restart = %q, want %q", i, services[i].Restart, RestartOnFailure)
+			}
+		}
+	})
+
+	t.Run("invalid_line", func(t *testing.T) {
+		_, err := ParseProcfile(strings.NewReader("web npm run dev\n"))
+		if err == nil {
+			t.Error("ParseProcfile() should reject lines without a colon")
+		}
+	})
+}
+
+// ==============================================================
+// LoadServices Tests
+// ==============================================================
+
+func TestLoadServices(t *testing.T) {
+	t.Run("procfile", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "Procfile", "web: npm start\n")
+
+		services, err := LoadServices(dir)
+		if err != nil {
+			t.Fatalf("LoadServices() error = %v", err)
+		}
+		if len(services) != 1 || services[0].Name != "web" {
+			t.Errorf("LoadServices() = %+v, want web service", services)
+		}
+	})
+
+	t.Run("bdev_yml", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, ".bdev.yml", `services:
+  worker:
+    command: python worker.py
+    restart: always
+    env:
+      QUEUE: default
+  api:
+    command: go run ./cmd/api
+    cwd: backend
+`)
+
+		services, err := LoadServices(dir)
+		if err != nil {
+			t.Fatalf("LoadServices() error = %v", err)
+		}
+		if len(services) != 2 {
+			t.Fatalf("LoadServices() returned %d services, want 2", len(services))
+		}
+		if services[0].Name != "api" || services[0].Cwd != "backend" || services[0].Restart != RestartOnFailure {
+			t.Errorf("services[0] = %+v, want api in backend with on-failure", services[0])
+		}
+		if services[1].Name != "worker" || services[1].Restart != RestartAlways || services[1].Env["QUEUE"] != "default" {
+			t.Errorf("services[1] = %+v, want worker with always restart and env", services[1])
+		}
+	})
+
+	t.Run("bad_policy", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, ".bdev.yml", "services:\n  web:\n    command: npm start\n    restart: sometimes\n")
+
+		if _, err := LoadServices(dir); err == nil {
+			t.Error("LoadServices() should reject unknown restart policies")
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		services, err := LoadServices(t.TempDir())
+		if err != nil || len(services) != 0 {
+			t.Errorf("LoadServices() = %v, %v, want no services", services, err)
+		}
+	})
+}
+
+// ==============================================================
+// Supervisor Tests
+// ==============================================================
+
+func TestSupervisor_Select(t *testing.T) {
+	sup := NewSupervisor([]Service{{Name: "web"}, {Name: "api"}, {Name: "worker"}}, ".")
+
+	if err := sup.Select("api"); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if names := sup.Names(); len(names) != 1 || names[0] != "api" {
+		t.Errorf("Names() = %v, want [api]", names)
+	}
+
+	if err := sup.Select("missing"); err == nil {
+		t.Error("Select() should fail for unknown service")
+	}
+}
+
+func TestSupervisor_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	t.Run("prefixed_output", func(t *testing.T) {
+		out := &safeBuffer{}
+		sup := NewSupervisor([]Service{
+			{Name: "one", Command: "echo hello", Restart: RestartNo},
+			{Name: "two", Command: "echo world", Restart: RestartNo},
+		}, t.TempDir())
+		sup.Stdout = out
+
+		if err := sup.Run(context.Background()); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		got := out.String()
+		if !strings.Contains(got, "one |") || !strings.Contains(got, "hello") {
+			t.Errorf("output missing prefixed 'one' line:\n%s", got)
+		}
+		if !strings.Contains(got, "two |") || !strings.Contains(got, "world") {
+			t.Errorf("output missing prefixed 'two' line:\n%s", got)
+		}
+	})
+
+	t.Run("restart_on_failure", func(t *testing.T) {
+		out := &safeBuffer{}
+		sup := NewSupervisor([]Service{
+			{Name: "crash", Command: "echo boom; exit 3", Restart: RestartOnFailure},
+		}, t.TempDir())
+		sup.Stdout = out
+		sup.MaxRestarts = 2
+		sup.Backoff = time.Millisecond
+
+		err := sup.Run(context.Background())
+		if err == nil {
+			t.Fatal("Run() should fail once restarts are exhausted")
+		}
+		if n := strings.Count(out.String(), "boom"); n != 3 {
+			t.Errorf("service ran %d times, want 3 (1 + 2 restarts)", n)
+		}
+	})
+
+	t.Run("restart_count_resets_when_stable", func(t *testing.T) {
+		// The first three runs stay up past StableAfter, later ones crash at once
+		out := &safeBuffer{}
+		sup := NewSupervisor([]Service{{
+			Name:    "flaky",
+			Command: `n=$(cat count 2>/dev/null || echo 0); n=$((n+1)); echo $n > count; echo boom; [ $n -le 3 ] && sleep 0.2; exit 3`,
+			Restart: RestartOnFailure,
+		}}, t.TempDir())
+		sup.Stdout = out
+		sup.MaxRestarts = 1
+		sup.Backoff = time.Millisecond
+		sup.StableAfter = 100 * time.Millisecond
+
+		if err := sup.Run(context.Background()); err == nil {
+			t.Fatal("Run() should fail once restarts are exhausted")
+		}
+		if n := strings.Count(out.String(), "boom"); n != 4 {
+			t.Errorf("service ran %d times, want 4 (3 stable runs, then 1 crash past the limit)", n)
+		}
+	})
+
+	t.Run("shutdown_on_cancel", func(t *testing.T) {
+		sup := NewSupervisor([]Service{
+			{Name: "sleeper", Command: "sleep 30", Restart: RestartAlways},
+		}, t.TempDir())
+		sup.Stdout = &safeBuffer{}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- sup.Run(ctx) }()
+
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Run() error = %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Run() did not stop after cancel")
+		}
+	})
+}
+
+// ==============================================================
+// prefixWriter Tests
+// ==============================================================
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	pw := &prefixWriter{prefix: "web |", w: &buf, mu: &sync.Mutex{}}
+
+	pw.Write([]byte("first line\nsecond "))
+	pw.Write([]byte("line\npartial"))
+	pw.Flush()
+
+	want := "web | first line\nweb | second line\nweb | partial\n"
+	if buf.String() != want {
+		t.Errorf("prefixWriter output = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/badie/bdev/pkg/ui"
+)
+
+// DefaultStaticAddr is where static projects are served unless PORT is set
+const DefaultStaticAddr = "localhost:8080"
+
+// ServeStatic serves the project directory over HTTP until Ctrl+C or ctx ends.
+// An empty addr uses PORT from the runner environment or DefaultStaticAddr.
+func (r *Runner) ServeStatic(ctx context.Context, addr string) error {
+	if addr == "" {
+		addr = DefaultStaticAddr
+		if port := r.Env["PORT"]; port != "" {
+			addr = "localhost:" + port
+		} else if port := os.Getenv("PORT"); port != "" {
+			addr = "localhost:" + port
+		}
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen on %s: %w", addr, err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	srv := &http.Server{
+		Handler:           staticHandler(r.Cwd, r.Stdout),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Serving %s", r.Project.Name))
+	fmt.Fprintf(r.Stdout, "%s %s\n", ui.Muted("Local:"), ui.Primary("http://"+ln.Addr().String()))
+	fmt.Fprintln(r.Stdout, ui.Muted("Press Ctrl+C to stop"))
+	fmt.Fprintln(r.Stdout)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// staticHandler serves files from root without caching and logs each request
+func staticHandler(root string, log io.Writer) http.Handler {
+	files := http.FileServer(http.Dir(root))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Always serve the latest version of edited files
+		w.Header().Set("Cache-Control", "no-store")
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		if hiddenPath(req.URL.Path) {
+			http.NotFound(rec, req)
+		} else {
+			files.ServeHTTP(rec, req)
+		}
+
+		status := fmt.Sprint(rec.status)
+		if rec.status >= 400 {
+			status = ui.Error(status)
+		} else {
+			status = ui.Muted(status)
+		}
+		fmt.Fprintf(log, "%s %s %s\n", status, req.Method, req.URL.Path)
+	})
+}
+
+// hiddenPath reports whether a request path goes through a dotfile or dot
+// directory such as .env or .git, keeping .well-known reachable
+func hiddenPath(p string) bool {
+	for _, seg := range strings.Split(p, "/") {
+		if strings.HasPrefix(seg, ".") && seg != ".well-known" {
+			return true
+		}
+	}
+	return false
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// Static Server Tests
+// ==============================================================
+
+func TestStaticHandler(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "index.html", "<h1>home</h1>")
+	writeFile(t, dir, "css/site.css", "body{}")
+	writeFile(t, dir, ".env", "SECRET=1")
+	writeFile(t, dir, ".git/config", "[core]")
+	writeFile(t, dir, ".well-known/security.txt", "Contact: me")
+
+	var log bytes.Buffer
+	srv := httptest.NewServer(staticHandler(dir, &log))
+	defer srv.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/", http.StatusOK, "<h1>home</h1>"},
+		{"/css/site.css", http.StatusOK, "body{}"},
+		{"/missing.js", http.StatusNotFound, ""},
+		{"/.env", http.StatusNotFound, ""},
+		{"/.git/config", http.StatusNotFound, ""},
+		{"/css/../.env", http.StatusNotFound, ""},
+		{"/.well-known/security.txt", http.StatusOK, "Contact: me"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tt.path)
+			if err != nil {
+				t.Fatalf("GET %s error = %v", tt.path, err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if cc := resp.Header.Get("Cache-Control"); tt.wantStatus == http.StatusOK && cc != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", cc)
+			}
+		})
+	}
+
+	if !strings.Contains(log.String(), "GET /css/site.css") {
+		t.Errorf("request log missing entry:\n%s", log.String())
+	}
+}
+
+func TestRunner_ServeStatic(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "index.html", "hello")
+
+	// Reserve a free port, then hand it to the server
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	r := &Runner{
+		Project: &projects.Project{Name: "site", Type: projects.TypeStatic},
+		Cwd:     dir,
+		Stdout:  &safeBuffer{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.ServeStatic(ctx, addr) }()
+
+	var body string
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get(fmt.Sprintf("http://%s/", addr))
+		if err == nil {
+			b, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			body = string(b)
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if body != "hello" {
+		t.Errorf("body = %q, want 'hello'", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ServeStatic() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeStatic() did not stop after cancel")
+	}
+}
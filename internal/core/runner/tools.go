@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// lookPath finds tools on PATH; tests replace it to control detection
+var (
+	defaultLookPath = exec.LookPath
+	lookPath        = defaultLookPath
+)
+
+// toolCommand is a command backed by a tool that may not be installed
+type toolCommand struct {
+	bin  string
+	args []string
+
+	// tool is checked for before running, empty means bin itself
+	tool string
+
+	// failOnOutput treats any stdout as failure (gofmt -l lists unformatted files)
+	failOnOutput bool
+}
+
+// newToolCommand wraps a type definition command
+func newToolCommand(c *projects.Command) *toolCommand {
+	return &toolCommand{bin: c.Bin, args: c.Args, tool: c.Tool, failOnOutput: c.FailOnOutput}
+}
+
+// toolHints are install suggestions shown when a tool is missing
+var toolHints = map[string]string{
+	"goimports":      "go install golang.org/x/tools/cmd/goimports@latest",
+	"gofmt":          "install Go from https://go.dev/dl/",
+	"prettier":       "npm install -D prettier",
+	"eslint":         "npm install -D eslint",
+	"ruff":           "pip install ruff",
+	"black":          "pip install black",
+	"rustfmt":        "rustup component add rustfmt",
+	"cargo-clippy":   "rustup component add clippy",
+	"php-cs-fixer":   "composer require --dev friendsofphp/php-cs-fixer",
+	"phpstan":        "composer require --dev phpstan/phpstan",
+	"pint":           "composer require --dev laravel/pint",
+	"clang-format":   "install clang-format with your LLVM or system package manager",
+	"mvn":            "install Maven or add the Maven wrapper (mvnw)",
+	"gradle":         "install Gradle or add the Gradle wrapper (gradlew)",
+	"cargo-outdated": "cargo install cargo-outdated",
+	"cargo-audit":    "cargo install cargo-audit",
+	"govulncheck":    "go install golang.org/x/vuln/cmd/govulncheck@latest",
+	"pip-audit":      "pip install pip-audit",
+}
+
+// localBinDirs are project-local tool directories, searched before PATH
+var localBinDirs = []string{
+	filepath.Join("node_modules", ".bin"),
+	filepath.Join(".venv", "bin"),
+	filepath.Join("venv", "bin"),
+	filepath.Join(".venv", "Scripts"),
+	filepath.Join("vendor", "bin"),
+}
+
+// hasTool reports whether a tool is installed locally in the project or on PATH
+func (r *Runner) hasTool(name string) bool {
+	if filepath.IsAbs(name) || filepath.Base(name) != name {
+		// Explicit path such as vendor/bin/pint or ./gradlew
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.Cwd, path)
+		}
+		return fileExists(path)
+	}
+
+	for _, dir := range localBinDirs {
+		for _, candidate := range executableNames(name) {
+			if fileExists(filepath.Join(r.Cwd, dir, candidate)) {
+				return true
+			}
+		}
+	}
+
+	_, err := lookPath(name)
+	return err == nil
+}
+
+// ensureTool returns a helpful error when the command's tool is missing
+func (r *Runner) ensureTool(c *toolCommand) error {
+	tool := c.tool
+	if tool == "" {
+		tool = c.bin
+	}
+	if r.hasTool(tool) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("%s is not installed", filepath.Base(tool))
+	if hint, ok := toolHints[filepath.Base(tool)]; ok {
+		msg += fmt.Sprintf(" (%s)", hint)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// localTool prefers a project-local binary such as vendor/bin/pint
+func (r *Runner) localTool(dir, name string) string {
+	rel := filepath.Join(dir, name)
+	if fileExists(filepath.Join(r.Cwd, rel)) {
+		return rel
+	}
+	return name
+}
+
+func executableNames(name string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{name + ".exe", name + ".cmd", name + ".bat", name}
+	}
+	return []string{name}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
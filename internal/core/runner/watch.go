@@ -0,0 +1,173 @@
+package runner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/badie/bdev/internal/core/ignore"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// Watcher polls a project tree and reports changed source files
+type Watcher struct {
+	Root     string
+	Patterns []string
+	Ignore   *ignore.Matcher
+	Interval time.Duration
+	Debounce time.Duration
+}
+
+// fileState is the part of a file's metadata used to detect changes
+type fileState struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// DefaultWatchPatterns returns the source globs watched for a project type
+func DefaultWatchPatterns(t projects.ProjectType) []string {
+	if def := projects.LookupType(t); def != nil && len(def.Watch) > 0 {
+		return def.Watch
+	}
+	return []string{"**/*"}
+}
+
+// NewWatcher creates a watcher for a project using its type defaults and .gitignore
+func NewWatcher(project *projects.Project) *Watcher {
+	return &Watcher{
+		Root:     project.Path,
+		Patterns: DefaultWatchPatterns(project.Type),
+		Ignore:   ignore.Load(project.Path),
+		Interval: 500 * time.Millisecond,
+		Debounce: 300 * time.Millisecond,
+	}
+}
+
+// Snapshot records the state of every watched file under Root
+func (w *Watcher) Snapshot() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+
+	err := filepath.WalkDir(w.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can vanish mid-walk, that is a change the next poll will see
+			return nil
+		}
+
+		rel, err := filepath.Rel(w.Root, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if w.Ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() || !w.matches(rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[rel] = fileState{ModTime: info.ModTime(), Size: info.Size()}
+		return nil
+	})
+
+	return files, err
+}
+
+// matches reports whether a relative path matches any watch pattern
+func (w *Watcher) matches(rel string) bool {
+	if len(w.Patterns) == 0 {
+		return true
+	}
+	for _, p := range w.Patterns {
+		if ignore.MatchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch polls for changes and sends each debounced batch of changed paths
+func (w *Watcher) Watch(ctx context.Context) <-chan []string {
+	changes := make(chan []string)
+
+	go func() {
+		defer close(changes)
+
+		prev, _ := w.Snapshot()
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		pending := make(map[string]bool)
+		var lastChange time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			next, err := w.Snapshot()
+			if err != nil {
+				continue
+			}
+
+			if changed := diffSnapshots(prev, next); len(changed) > 0 {
+				for _, c := range changed {
+					pending[c] = true
+				}
+				lastChange = time.Now()
+			}
+			prev = next
+
+			// Wait for the tree to settle before reporting
+			if len(pending) == 0 || time.Since(lastChange) < w.Debounce {
+				continue
+			}
+
+			batch := make([]string, 0, len(pending))
+			for c := range pending {
+				batch = append(batch, c)
+			}
+			sort.Strings(batch)
+			pending = make(map[string]bool)
+
+			select {
+			case changes <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes
+}
+
+// diffSnapshots returns the paths added, removed or modified between two snapshots
+func diffSnapshots(prev, next map[string]fileState) []string {
+	var changed []string
+
+	for path, state := range next {
+		if old, ok := prev[path]; !ok || !old.ModTime.Equal(state.ModTime) || old.Size != state.Size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
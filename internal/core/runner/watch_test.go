@@ -0,0 +1,222 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/badie/bdev/internal/core/ignore"
+	"github.com/badie/bdev/internal/core/projects"
+)
+
+// ==============================================================
+// DefaultWatchPatterns Tests
+// ==============================================================
+
+func TestDefaultWatchPatterns(t *testing.T) {
+	tests := []struct {
+		ptype     projects.ProjectType
+		file      string
+		wantWatch bool
+	}{
+		{projects.TypeGo, "internal/core/runner.go", true},
+		{projects.TypeGo, "README.md", false},
+		{projects.TypePython, "app/views.py", true},
+		{projects.TypeRust, "src/main.rs", true},
+		{projects.TypeNextJS, "src/app/page.tsx", true},
+		{projects.TypeUnknown, "anything.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ptype.String()+"_"+tt.file, func(t *testing.T) {
+			w := &Watcher{Patterns: DefaultWatchPatterns(tt.ptype)}
+			if got := w.matches(tt.file); got != tt.wantWatch {
+				t.Errorf("matches(%q) = %v, want %v", tt.file, got, tt.wantWatch)
+			}
+		})
+	}
+}
+
+// ==============================================================
+// Snapshot Tests
+// ==============================================================
+
+func TestWatcher_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.go", "package main\n")
+	writeFile(t, dir, "internal/app/app.go", "package app\n")
+	writeFile(t, dir, "README.md", "# readme\n")
+	writeFile(t, dir, "gen/out.go", "package gen\n")
+	writeFile(t, dir, ".gitignore", "gen/\n")
+
+	w := &Watcher{
+		Root:     dir,
+		Patterns: DefaultWatchPatterns(projects.TypeGo),
+		Ignore:   ignore.Load(dir),
+	}
+
+	snap, err := w.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	got := make([]string, 0, len(snap))
+	for path := range snap {
+		got = append(got, path)
+	}
+
+	want := []string{"internal/app/app.go", "main.go"}
+	if len(got) != len(want) {
+		t.Fatalf("Snapshot() = %v, want %v", got, want)
+	}
+	for _, p := range want {
+		if _, ok := snap[p]; !ok {
+			t.Errorf("Snapshot() missing %q", p)
+		}
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Now()
+	prev := map[string]fileState{
+		"a.go": {ModTime: now, Size: 10},
+		"b.go": {ModTime: now, Size: 10},
+		"c.go": {ModTime: now, Size: 10},
+	}
+	next := map[string]fileState{
+		"a.go": {ModTime: now, Size: 10},
+		"b.go": {ModTime: now.Add(time.Second), Size: 10},
+		"d.go": {ModTime: now, Size: 5},
+	}
+
+	got := diffSnapshots(prev, next)
+	want := []string{"b.go", "c.go", "d.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffSnapshots() = %v, want %v", got, want)
+	}
+}
+
+// ==============================================================
+// Watch Tests
+// ==============================================================
+
+func TestWatcher_Watch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.go", "package main\n")
+
+	w := &Watcher{
+		Root:     dir,
+		Patterns: []string{"**/*.go"},
+		Interval: 20 * time.Millisecond,
+		Debounce: 40 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changes := w.Watch(ctx)
+	time.Sleep(50 * time.Millisecond)
+	writeFile(t, dir, "util.go", "package main\n")
+	writeFile(t, dir, "notes.txt", "ignored by pattern\n")
+
+	select {
+	case batch := <-changes:
+		if !reflect.DeepEqual(batch, []string{"util.go"}) {
+			t.Errorf("Watch() batch = %v, want [util.go]", batch)
+		}
+	case <-ctx.Done():
+		t.Fatal("Watch() did not report the change")
+	}
+}
+
+func TestRunner_Watch_Restarts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, "main.go", "package main\n")
+
+	stdout := &safeBuffer{}
+	r := &Runner{
+		Project: &projects.Project{Name: "test", Path: dir, Type: projects.TypeGo},
+		Cwd:     dir,
+		Env:     make(map[string]string),
+		Stdout:  stdout,
+		Stderr:  stdout,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Watch(ctx, "sh", []string{"-c", "echo run; sleep 30"})
+	}()
+
+	waitFor(t, stdout, "run", 1)
+	time.Sleep(600 * time.Millisecond) // let the watcher take its first snapshot
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	waitFor(t, stdout, "run", 2)
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not stop after cancel")
+	}
+}
+
+// ==============================================================
+// Test Helpers
+// ==============================================================
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, buf *safeBuffer, substr string, count int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Count(buf.String(), substr) >= count {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d x %q in output:\n%s", count, substr, buf.String())
+}
+
+// safeBuffer is a bytes.Buffer that can be written by a child process
+// while the test reads it
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,250 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/badie/bdev/internal/core/git"
+	"github.com/badie/bdev/internal/core/projects"
+	"github.com/badie/bdev/templates"
+)
+
+// SpecFile is the optional template manifest. It is not copied into new projects.
+const SpecFile = "template.yml"
+
+// SourceBundled is the Source of templates shipped with bdev
+const SourceBundled = "bundled"
+
+// Spec is the manifest of a template
+type Spec struct {
+	Description string            `yaml:"description"`
+	Variables   map[string]string `yaml:"variables"`   // defaults, may reference other variables
+	PostCreate  []string          `yaml:"post_create"` // shell commands run in the new project
+}
+
+// Template is a project template
+type Template struct {
+	Name   string
+	Source string // template directory, SourceBundled or git URL
+	Spec   Spec
+
+	fsys fs.FS
+}
+
+// List returns the templates in dirs followed by the bundled ones. A template
+// in an earlier directory hides later ones with the same name.
+func List(dirs []string) []Template {
+	seen := make(map[string]bool)
+	var list []Template
+
+	add := func(source string, fsys fs.FS) {
+		entries, err := fs.ReadDir(fsys, ".")
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || seen[e.Name()] {
+				continue
+			}
+			sub, err := fs.Sub(fsys, e.Name())
+			if err != nil {
+				continue
+			}
+			seen[e.Name()] = true
+			list = append(list, load(e.Name(), source, sub))
+		}
+	}
+
+	for _, dir := range dirs {
+		add(dir, os.DirFS(dir))
+	}
+	add(SourceBundled, templates.FS)
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Find returns the template with the given name
+func Find(name string, dirs []string) (*Template, error) {
+	var names []string
+	for _, t := range List(dirs) {
+		if t.Name == name {
+			return &t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("unknown template '%s' (available: %s)", name, strings.Join(names, ", "))
+}
+
+// IsGitURL reports whether a template argument is a git repository URL
+// rather than a template name
+func IsGitURL(s string) bool {
+	for _, prefix := range []string{"file://", "git@", "git://", "ssh://", "https://", "http://"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(s, ".git")
+}
+
+// FromGit clones a template repository. The returned cleanup function
+// removes the clone.
+func FromGit(url string) (*Template, func(), error) {
+	tmp, err := os.MkdirTemp("", "bdev-template-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	dest := filepath.Join(tmp, "template")
+	if err := git.Clone(url, dest, 1); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	name := strings.TrimSuffix(path.Base(strings.TrimSuffix(filepath.ToSlash(url), "/")), ".git")
+	t := load(name, url, os.DirFS(dest))
+	return &t, cleanup, nil
+}
+
+func load(name, source string, fsys fs.FS) Template {
+	t := Template{Name: name, Source: source, fsys: fsys}
+	if data, err := fs.ReadFile(fsys, SpecFile); err == nil {
+		// A broken manifest still leaves the files usable
+		_ = yaml.Unmarshal(data, &t.Spec)
+	}
+	return t
+}
+
+// Vars returns the variables for a new project: Name, Date and Year, then
+// the template defaults, then overrides
+func Vars(name string, spec Spec, overrides map[string]string) map[string]string {
+	now := time.Now()
+	vars := map[string]string{
+		"Name": name,
+		"Date": now.Format("2006-01-02"),
+		"Year": strconv.Itoa(now.Year()),
+	}
+
+	keys := make([]string, 0, len(spec.Variables))
+	for k := range spec.Variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		vars[k] = Substitute(spec.Variables[k], vars)
+	}
+
+	for k, v := range overrides {
+		vars[k] = v
+	}
+	return vars
+}
+
+// legacyVars maps the placeholders of the original templates to variables
+var legacyVars = map[string]string{
+	"PROJECT_NAME": "Name",
+	"DATE":         "Date",
+}
+
+var placeholder = regexp.MustCompile(`\{\{\s*\.?([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Substitute replaces {{ .Var }} placeholders (also {{Var}} and the legacy
+// {{PROJECT_NAME}}) with their values. Unknown placeholders are kept, so
+// framework templates such as Angular's {{ title }} survive.
+func Substitute(s string, vars map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		name := placeholder.FindStringSubmatch(m)[1]
+		if alias, ok := legacyVars[name]; ok {
+			name = alias
+		}
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// Create copies the template into dest, which must not exist, substituting
+// variables in file contents and paths. Binary files are copied as is.
+func (t *Template) Create(dest string, vars map[string]string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+
+	err := fs.WalkDir(t.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == SpecFile || d.Name() == ".git" {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(Substitute(p, vars)))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		data, err := fs.ReadFile(t.fsys, p)
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(data, []byte{0}) {
+			data = []byte(Substitute(string(data), vars))
+		}
+
+		perm := os.FileMode(0o644)
+		if info, err := d.Info(); err == nil && info.Mode()&0o111 != 0 {
+			perm = 0o755
+		}
+		return os.WriteFile(target, data, perm)
+	})
+	if err != nil {
+		os.RemoveAll(dest)
+		return fmt.Errorf("failed to copy template: %w", err)
+	}
+	return nil
+}
+
+// RunHooks runs the post-create commands of the template in dir
+func (t *Template) RunHooks(dir string, stdout, stderr io.Writer) error {
+	for _, line := range t.Spec.PostCreate {
+		bin, args := projects.ShellCommand(line)
+		cmd := exec.Command(bin, args...)
+		cmd.Dir = dir
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("post-create hook '%s' failed: %w", line, err)
+		}
+	}
+	return nil
+}
+
+// InitGit creates a repository in dir with a first commit of every file
+func InitGit(dir, template string) error {
+	repo, err := git.Init(dir)
+	if err != nil {
+		return err
+	}
+	if err := repo.Add(); err != nil {
+		return err
+	}
+	return repo.Commit(fmt.Sprintf("Initial commit from %s template", template), false)
+}
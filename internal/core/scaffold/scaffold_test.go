@@ -0,0 +1,315 @@
+package scaffold
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// writeFile creates a file with content, creating parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+// readFile returns the content of a file, failing the test if it is missing
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// runGit runs a git command in dir
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// setGitIdentity lets commits succeed without a global git config
+func setGitIdentity(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+}
+
+// ==============================================================
+// Substitute Tests
+// ==============================================================
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{"Name": "acme", "Date": "2026-01-02", "Port": "8080"}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"dot_syntax", "# {{ .Name }}", "# acme"},
+		{"no_spaces", "{{.Name}}-{{.Port}}", "acme-8080"},
+		{"without_dot", "{{Name}}", "acme"},
+		{"legacy_project_name", "APP_NAME={{PROJECT_NAME}}", "APP_NAME=acme"},
+		{"legacy_date", "Created {{DATE}}", "Created 2026-01-02"},
+		{"unknown_kept", "<h1>{{ title }}</h1>", "<h1>{{ title }}</h1>"},
+		{"no_placeholder", "plain text", "plain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Substitute(tt.in, vars); got != tt.want {
+				t.Errorf("Substitute(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVars(t *testing.T) {
+	spec := Spec{Variables: map[string]string{
+		"Module": "github.com/me/{{ .Name }}",
+		"Port":   "3000",
+	}}
+
+	vars := Vars("acme", spec, map[string]string{"Port": "4000"})
+
+	if vars["Name"] != "acme" {
+		t.Errorf("Name = %q, want acme", vars["Name"])
+	}
+	if vars["Year"] != time.Now().Format("2006") {
+		t.Errorf("Year = %q", vars["Year"])
+	}
+	if vars["Module"] != "github.com/me/acme" {
+		t.Errorf("Module = %q, want defaults to see Name", vars["Module"])
+	}
+	if vars["Port"] != "4000" {
+		t.Errorf("Port = %q, want the override", vars["Port"])
+	}
+}
+
+// ==============================================================
+// Template Tests
+// ==============================================================
+
+func TestTemplate_Create(t *testing.T) {
+	tpl := load("starter", "test", fstest.MapFS{
+		SpecFile:                     {Data: []byte("description: Starter\npost_create:\n  - echo hi\n")},
+		"README.md":                  {Data: []byte("# {{ .Name }}\n")},
+		"cmd/{{ .Name }}/main.go":    {Data: []byte("package main // {{ .Name }}\n")},
+		".gitignore":                 {Data: []byte("dist/\n")},
+		"bin/run.sh":                 {Data: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"assets/logo.bin":            {Data: []byte("{{ .Name }}\x00")},
+		".git/HEAD":                  {Data: []byte("ref: refs/heads/main\n")},
+		"nested/{{PROJECT_NAME}}.md": {Data: []byte("{{DATE}}")},
+	})
+
+	if tpl.Spec.Description != "Starter" || len(tpl.Spec.PostCreate) != 1 {
+		t.Fatalf("Spec = %+v, want the manifest to be loaded", tpl.Spec)
+	}
+
+	dest := filepath.Join(t.TempDir(), "acme")
+	vars := map[string]string{"Name": "acme", "Date": "2026-01-02"}
+	if err := tpl.Create(dest, vars); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	files := map[string]string{
+		"README.md":        "# acme\n",
+		"cmd/acme/main.go": "package main // acme\n",
+		".gitignore":       "dist/\n",
+		"assets/logo.bin":  "{{ .Name }}\x00",
+		"nested/acme.md":   "2026-01-02",
+		"bin/run.sh":       "#!/bin/sh\n",
+	}
+	for name, want := range files {
+		if got := readFile(t, filepath.Join(dest, filepath.FromSlash(name))); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	for _, skipped := range []string{SpecFile, ".git"} {
+		if _, err := os.Stat(filepath.Join(dest, skipped)); err == nil {
+			t.Errorf("%s was copied into the project", skipped)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(filepath.Join(dest, "bin", "run.sh"))
+		if info.Mode().Perm()&0o100 == 0 {
+			t.Errorf("run.sh mode = %v, want executable", info.Mode())
+		}
+	}
+
+	t.Run("existing_destination", func(t *testing.T) {
+		if err := tpl.Create(dest, vars); err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("Create() error = %v, want already exists", err)
+		}
+	})
+}
+
+func TestTemplate_RunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use sh")
+	}
+
+	dir := t.TempDir()
+	tpl := Template{Spec: Spec{PostCreate: []string{"echo one > hook.txt", "echo two >> hook.txt"}}}
+	if err := tpl.RunHooks(dir, nil, nil); err != nil {
+		t.Fatalf("RunHooks() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "hook.txt")); got != "one\ntwo\n" {
+		t.Errorf("hook.txt = %q, want both hooks to run in order", got)
+	}
+
+	tpl.Spec.PostCreate = []string{"exit 3", "touch never"}
+	if err := tpl.RunHooks(dir, nil, nil); err == nil {
+		t.Error("RunHooks() expected error for a failing hook")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never")); err == nil {
+		t.Error("hooks kept running after a failure")
+	}
+}
+
+// ==============================================================
+// Lookup Tests
+// ==============================================================
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "python-cli", "main.py"), "print('custom')\n")
+	writeFile(t, filepath.Join(dir, "go-service", SpecFile), "description: Go service\n")
+	writeFile(t, filepath.Join(dir, "not-a-template.txt"), "")
+
+	list := List([]string{dir, filepath.Join(dir, "missing")})
+
+	got := make(map[string]Template)
+	for _, tpl := range list {
+		got[tpl.Name] = tpl
+	}
+	if got["go-service"].Spec.Description != "Go service" {
+		t.Errorf("go-service = %+v, want it listed with its description", got["go-service"])
+	}
+	if got["python-cli"].Source != dir {
+		t.Errorf("python-cli source = %q, want the configured dir to hide the bundled one", got["python-cli"].Source)
+	}
+	if got["nextjs-starter"].Source != SourceBundled {
+		t.Errorf("nextjs-starter source = %q, want %q", got["nextjs-starter"].Source, SourceBundled)
+	}
+	if _, ok := got["not-a-template.txt"]; ok {
+		t.Error("files are listed as templates")
+	}
+
+	if _, err := Find("nope", []string{dir}); err == nil || !strings.Contains(err.Error(), "go-service") {
+		t.Errorf("Find() error = %v, want the available templates", err)
+	}
+}
+
+func TestBundledTemplates(t *testing.T) {
+	tpl, err := Find("laravel-api", nil)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "shop")
+	if err := tpl.Create(dest, Vars("shop", tpl.Spec, nil)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if env := readFile(t, filepath.Join(dest, ".env.example")); !strings.Contains(env, "APP_NAME=shop") {
+		t.Errorf(".env.example = %q, want the project name substituted", env)
+	}
+}
+
+func TestIsGitURL(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want bool
+	}{
+		{"nextjs-starter", false},
+		{"/srv/templates/api.git", true},
+		{"file:///srv/templates/api", true},
+		{"git@github.com:me/template.git", true},
+		{"https://github.com/me/template", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsGitURL(tt.arg); got != tt.want {
+			t.Errorf("IsGitURL(%q) = %v, want %v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+// ==============================================================
+// Git Tests
+// ==============================================================
+
+func TestFromGit_BareRepo(t *testing.T) {
+	setGitIdentity(t)
+
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "README.md"), "# {{ .Name }}\n")
+	writeFile(t, filepath.Join(src, SpecFile), "description: From git\n")
+	runGit(t, src, "init")
+	runGit(t, src, "add", ".")
+	runGit(t, src, "commit", "-m", "template")
+
+	bare := filepath.Join(t.TempDir(), "service.git")
+	runGit(t, src, "clone", "--bare", src, bare)
+
+	tpl, cleanup, err := FromGit(bare)
+	if err != nil {
+		t.Fatalf("FromGit() error = %v", err)
+	}
+	defer cleanup()
+
+	if tpl.Name != "service" || tpl.Source != bare || tpl.Spec.Description != "From git" {
+		t.Errorf("template = %+v", tpl)
+	}
+
+	dest := filepath.Join(t.TempDir(), "billing")
+	if err := tpl.Create(dest, map[string]string{"Name": "billing"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(dest, "README.md")); got != "# billing\n" {
+		t.Errorf("README.md = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git")); err == nil {
+		t.Error("the template's .git was copied")
+	}
+}
+
+func TestInitGit(t *testing.T) {
+	setGitIdentity(t)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+
+	if err := InitGit(dir, "go-cli"); err != nil {
+		t.Fatalf("InitGit() error = %v", err)
+	}
+	if got := runGit(t, dir, "log", "--format=%s"); got != "Initial commit from go-cli template" {
+		t.Errorf("log = %q", got)
+	}
+	if got := runGit(t, dir, "status", "--porcelain"); got != "" {
+		t.Errorf("status = %q, want everything committed", got)
+	}
+}
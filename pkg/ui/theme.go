@@ -99,6 +99,7 @@ type Glyphs struct {
 	Branch  string
 	Lock    string
 	Sparkle string
+	Star    string
 
 	// Box Drawing (Light)
 	BoxTopLeft     string
@@ -144,6 +145,7 @@ var ActiveGlyphs = Glyphs{
 	Branch:  "⎇",
 	Lock:    "🔒",
 	Sparkle: "✓",
+	Star:    "★",
 
 	// Light Box
 	BoxTopLeft:     "┌",
@@ -187,6 +189,7 @@ var FallbackGlyphs = Glyphs{
 	Branch:  "[b]",
 	Lock:    "[L]",
 	Sparkle: "*",
+	Star:    "*",
 
 	BoxTopLeft:     "+",
 	BoxTopRight:    "+",
@@ -0,0 +1,9 @@
+// Package templates bundles the project templates used by `bdev projects new`
+package templates
+
+import "embed"
+
+// FS holds the bundled templates, one directory per template
+//
+//go:embed all:angular-app all:laravel-api all:nextjs-starter all:python-cli
+var FS embed.FS